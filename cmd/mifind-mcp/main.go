@@ -15,9 +15,10 @@ import (
 
 	"github.com/yourname/mifind/internal/api"
 	"github.com/yourname/mifind/internal/provider"
-	"github.com/yourname/mifind/internal/provider/mock"
 	"github.com/yourname/mifind/internal/search"
 	"github.com/yourname/mifind/internal/types"
+	"github.com/yourname/mifind/pkg/provider/builtin"
+	"github.com/yourname/mifind/pkg/provider/plugin"
 )
 
 func main() {
@@ -42,37 +43,42 @@ func main() {
 	// Initialize provider registry
 	providerRegistry := provider.NewRegistry()
 
-	// Register mock provider
-	if err := providerRegistry.Register(provider.ProviderMetadata{
-		Name:        "mock",
-		Description: "Mock provider for testing",
-		Factory:     func() provider.Provider { return mock.NewMockProvider() },
-	}); err != nil {
-		logger.Fatal().Err(err).Msg("Failed to register mock provider")
+	// Register built-in provider types
+	if err := builtin.Register(providerRegistry); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to register providers")
+	}
+
+	// Register out-of-process plugin provider types
+	if err := plugin.Register(context.Background(), providerRegistry, config.Plugins, &logger); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to register plugins")
 	}
 
 	// Initialize provider manager
 	providerManager := provider.NewManager(providerRegistry, &logger)
+	providerManager.SetTypeRegistry(typeRegistry)
 
-	// Initialize mock provider
-	if config.MockEnabled {
-		mockConfig := map[string]any{
-			"instance_id":  "default",
-			"entity_count": config.MockEntityCount,
-		}
-		if err := providerManager.Initialize(context.Background(), "mock", mockConfig); err != nil {
-			logger.Warn().Err(err).Msg("Failed to initialize mock provider")
-		} else {
-			logger.Info().Int("count", config.MockEntityCount).Msg("Mock provider initialized")
-		}
+	// Initialize configured provider instances
+	if err := providerManager.InitializeInstances(context.Background(), config.Providers); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to initialize providers")
 	}
+	logger.Info().
+		Int("configured", len(config.Providers)).
+		Int("initialized", providerManager.Count()).
+		Msg("Providers initialized")
 
 	// Initialize search components
 	// Use in-memory ranking strategy for MCP server
 	rankingConfig := search.DefaultRankingConfig()
 	rankingStrategy := search.NewInMemoryRanker(rankingConfig)
 
-	federator := search.NewFederator(providerManager, rankingStrategy, &logger, 30*time.Second)
+	federator := search.NewFederator(providerManager, rankingStrategy, &logger, config.Search.Timeout)
+	federator.SetSoftDeadline(config.Search.SoftDeadline)
+	federator.SetLateResultsTTL(config.Search.LateResultsTTL)
+	federator.SetSnapshotTTL(config.Search.SnapshotTTL)
+	federator.SetMaxSnapshots(config.Search.MaxSnapshots)
+	federator.SetPageWindow(config.Search.PageWindow)
+	federator.SetVocabularySize(config.Search.VocabularySize)
+	federator.SetTypeRegistry(typeRegistry)
 	ranker := search.NewRanker()
 	filters := search.NewFilters(typeRegistry)
 	relationships := search.NewRelationships(providerManager, &logger)
@@ -106,20 +112,33 @@ func main() {
 }
 
 // Config holds the application configuration.
+// The providers list uses the same format as the mifind API server.
 type Config struct {
-	MockEnabled     bool `mapstructure:"mock_enabled"`
-	MockEntityCount int  `mapstructure:"mock_entity_count"`
+	Search    search.FederatorConfig    `mapstructure:"search"`
+	Plugins   []plugin.Config           `mapstructure:"plugins"`
+	Providers []provider.InstanceConfig `mapstructure:"providers"`
 }
 
 // loadConfig loads configuration from file and environment.
 func loadConfig() (*Config, error) {
-	// Set defaults
-	viper.SetDefault("mock_enabled", true)
-	viper.SetDefault("mock_entity_count", 10)
-
-	// Read config file
-	viper.SetConfigName("config")
+	// Set defaults - without a config file, run a single mock instance
+	viper.SetDefault("providers", []map[string]any{
+		{"type": "mock", "instance_id": "default", "config": map[string]any{"entity_count": 10}},
+	})
+
+	searchDefaults := search.DefaultFederatorConfig()
+	viper.SetDefault("search.timeout", searchDefaults.Timeout)
+	viper.SetDefault("search.soft_deadline", searchDefaults.SoftDeadline)
+	viper.SetDefault("search.late_results_ttl", searchDefaults.LateResultsTTL)
+	viper.SetDefault("search.snapshot_ttl", searchDefaults.SnapshotTTL)
+	viper.SetDefault("search.max_snapshots", searchDefaults.MaxSnapshots)
+	viper.SetDefault("search.page_window", searchDefaults.PageWindow)
+	viper.SetDefault("search.vocabulary_size", searchDefaults.VocabularySize)
+
+	// Read config file - shared with the mifind API server
+	viper.SetConfigName("mifind")
 	viper.SetConfigType("yaml")
+	viper.AddConfigPath("config")
 	viper.AddConfigPath(".")
 	viper.AddConfigPath("/etc/mifind")
 	viper.AddConfigPath("$HOME/.mifind")
@@ -0,0 +1,181 @@
+// Command mifind-search searches a running mifind server from the command
+// line. The arguments form a query in the search syntax:
+//
+//	mifind-search type:photo camera:"Canon EOS" size>10MB modified:2024-01..2024-06 vacation
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// searchResponse is the part of the /api/search response the command prints.
+type searchResponse struct {
+	Entities []struct {
+		ID       string  `json:"ID"`
+		Type     string  `json:"Type"`
+		Title    string  `json:"Title"`
+		Score    float64 `json:"score"`
+		Provider string  `json:"provider"`
+	} `json:"entities"`
+	TotalCount int `json:"total_count"`
+	Providers  []struct {
+		Provider string `json:"provider"`
+		Status   string `json:"status"`
+		Error    string `json:"error"`
+	} `json:"providers"`
+}
+
+// errorResponse is an error response of the API.
+type errorResponse struct {
+	Error    string `json:"error"`
+	Reason   string `json:"reason"`
+	Position *int   `json:"position"`
+	End      int    `json:"end"`
+}
+
+func main() {
+	server := flag.String("server", envOr("MIFIND_URL", "http://localhost:8080"), "mifind server URL (or $MIFIND_URL)")
+	limit := flag.Int("limit", 20, "maximum number of results")
+	asJSON := flag.Bool("json", false, "print the raw JSON response")
+	sortSpec := flag.String("sort", "", `sort clauses instead of relevance, e.g. "modified desc, size asc"`)
+	timezone := flag.String("tz", os.Getenv("TZ"), "IANA timezone of dates like yesterday or this-year (or $TZ, default UTC)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] query...\n\n", os.Args[0])
+		fmt.Fprintln(flag.CommandLine.Output(), `Example: mifind-search type:photo camera:"Canon EOS" size>10MB -extension:gif vacation`)
+		fmt.Fprintln(flag.CommandLine.Output())
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	query := strings.Join(quoteArgs(flag.Args()), " ")
+	if query == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*server, query, *sortSpec, *timezone, *limit, *asJSON); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run sends the query to the server and prints the results.
+func run(server, query, sortSpec, timezone string, limit int, asJSON bool) error {
+	body, err := json.Marshal(map[string]any{"q": query, "sort": sortSpec, "timezone": timezone, "limit": limit})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Post(strings.TrimSuffix(server, "/")+"/api/search", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return responseError(query, resp.Status, data)
+	}
+
+	if asJSON {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	var result searchResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, entity := range result.Entities {
+		fmt.Fprintf(w, "%.2f\t%s\t%s\t%s\n", entity.Score, entity.Type, entity.Title, entity.ID)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d of %d results\n", len(result.Entities), result.TotalCount)
+	for _, report := range result.Providers {
+		if report.Status == "late" || report.Status == "failed" {
+			fmt.Fprintf(os.Stderr, "%s: %s %s\n", report.Provider, report.Status, report.Error)
+		}
+	}
+	return nil
+}
+
+// responseError describes an error response, pointing at the position of
+// query errors.
+func responseError(query, status string, data []byte) error {
+	var apiErr errorResponse
+	if err := json.Unmarshal(data, &apiErr); err != nil || apiErr.Error == "" {
+		return fmt.Errorf("search failed: %s: %s", status, strings.TrimSpace(string(data)))
+	}
+	if apiErr.Position == nil {
+		if apiErr.Reason != "" {
+			return fmt.Errorf("%s: %s", apiErr.Error, apiErr.Reason)
+		}
+		return fmt.Errorf("search failed: %s", apiErr.Error)
+	}
+
+	width := max(apiErr.End-*apiErr.Position, 1)
+	return fmt.Errorf("%s: %s\n  %s\n  %s%s", apiErr.Error, apiErr.Reason, query,
+		strings.Repeat(" ", *apiErr.Position), strings.Repeat("^", width))
+}
+
+// quoteArgs quotes the values of arguments that contain spaces, which the
+// shell unquoted, so that camera:"Canon EOS" survives as one term.
+func quoteArgs(args []string) []string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if !strings.ContainsAny(arg, " \t") || strings.Contains(arg, `"`) {
+			quoted[i] = arg
+			continue
+		}
+		name, value, ok := cutOperator(arg)
+		if !ok {
+			quoted[i] = `"` + arg + `"`
+			continue
+		}
+		quoted[i] = name + `"` + value + `"`
+	}
+	return quoted
+}
+
+// cutOperator splits an attribute term after its operator.
+func cutOperator(arg string) (string, string, bool) {
+	for i, r := range arg {
+		switch {
+		case r == ':' || r == '>' || r == '<' || r == '~' || r == '!':
+			j := i + 1
+			for j < len(arg) && strings.ContainsRune(">=<", rune(arg[j])) {
+				j++
+			}
+			return arg[:j], arg[j:], true
+		case r == ' ' || r == '\t':
+			return "", "", false
+		}
+	}
+	return "", "", false
+}
+
+// envOr returns the environment variable or fallback if it's unset.
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -2,10 +2,12 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -16,13 +18,11 @@ import (
 
 	"github.com/yourname/mifind/internal/api"
 	"github.com/yourname/mifind/internal/provider"
-	"github.com/yourname/mifind/internal/provider/mock"
 	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/store"
 	"github.com/yourname/mifind/internal/types"
-	"github.com/yourname/mifind/pkg/provider/filesystem"
-	"github.com/yourname/mifind/pkg/provider/gitlab"
-	"github.com/yourname/mifind/pkg/provider/immich"
-	"github.com/yourname/mifind/pkg/provider/jellyfin"
+	"github.com/yourname/mifind/pkg/provider/builtin"
+	"github.com/yourname/mifind/pkg/provider/plugin"
 )
 
 func main() {
@@ -50,126 +50,79 @@ func main() {
 	// Initialize provider registry
 	providerRegistry := provider.NewRegistry()
 
-	// Register mock provider
-	if err := providerRegistry.Register(provider.ProviderMetadata{
-		Name:        "mock",
-		Description: "Mock provider for testing",
-		Factory:     func() provider.Provider { return mock.NewMockProvider() },
-	}); err != nil {
-		logger.Fatal().Err(err).Msg("Failed to register mock provider")
+	// Register built-in provider types
+	if err := builtin.Register(providerRegistry); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to register providers")
 	}
 
-	// Register filesystem provider
-	if err := providerRegistry.Register(provider.ProviderMetadata{
-		Name:        "filesystem",
-		Description: "Filesystem provider via filesystem-api",
-		Factory:     func() provider.Provider { return filesystem.NewProvider() },
-	}); err != nil {
-		logger.Fatal().Err(err).Msg("Failed to register filesystem provider")
-	}
-
-	// Register Immich provider
-	if err := providerRegistry.Register(provider.ProviderMetadata{
-		Name:        "immich",
-		Description: "Immich photo and video server",
-		Factory:     func() provider.Provider { return immich.NewProvider() },
-	}); err != nil {
-		logger.Fatal().Err(err).Msg("Failed to register Immich provider")
-	}
-
-	// Register Jellyfin provider
-	if err := providerRegistry.Register(provider.ProviderMetadata{
-		Name:        "jellyfin",
-		Description: "Jellyfin media server",
-		Factory:     func() provider.Provider { return jellyfin.NewProvider() },
-	}); err != nil {
-		logger.Fatal().Err(err).Msg("Failed to register Jellyfin provider")
-	}
-
-	// Register GitLab provider
-	if err := providerRegistry.Register(provider.ProviderMetadata{
-		Name:        "gitlab",
-		Description: "GitLab code repository",
-		Factory:     func() provider.Provider { return gitlab.NewProvider() },
-	}); err != nil {
-		logger.Fatal().Err(err).Msg("Failed to register GitLab provider")
+	// Register out-of-process plugin provider types
+	if err := plugin.Register(context.Background(), providerRegistry, config.Plugins, &logger); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to register plugins")
 	}
 
 	// Initialize provider manager
 	providerManager := provider.NewManager(providerRegistry, &logger)
-
-	// Initialize mock provider
-	if config.MockEnabled {
-		mockConfig := map[string]any{
-			"instance_id":  "default",
-			"entity_count": config.MockEntityCount,
+	providerManager.SetTypeRegistry(typeRegistry)
+
+	// Provider changes made through the admin API are persisted separately and
+	// take precedence over the config file
+	var instanceStore *provider.FileInstanceStore
+	if config.Admin.Enabled {
+		instanceStore = provider.NewFileInstanceStore(config.Admin.StatePath)
+		persisted, ok, err := instanceStore.Load()
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to load persisted providers")
 		}
-		if err := providerManager.Initialize(context.Background(), "mock", mockConfig); err != nil {
-			logger.Warn().Err(err).Msg("Failed to initialize mock provider")
-		} else {
-			logger.Info().Int("count", config.MockEntityCount).Msg("Mock provider initialized")
+		if ok {
+			logger.Info().Str("path", instanceStore.Path()).Msg("Using persisted provider configuration")
+			config.Providers = persisted
 		}
 	}
 
-	// Initialize filesystem providers
-	for _, fsConfig := range config.FilesystemProviders {
-		providerConfig := map[string]any{
-			"instance_id": fsConfig.InstanceID,
-			"url":         fsConfig.URL,
-		}
-		if fsConfig.APIKey != "" {
-			providerConfig["api_key"] = fsConfig.APIKey
-		}
-		if err := providerManager.Initialize(context.Background(), "filesystem", providerConfig); err != nil {
-			logger.Warn().Err(err).Str("instance", fsConfig.InstanceID).Msg("Failed to initialize filesystem provider")
-		} else {
-			logger.Info().Str("instance", fsConfig.InstanceID).Str("url", fsConfig.URL).Msg("Filesystem provider initialized")
-		}
+	// Initialize configured provider instances
+	if err := providerManager.InitializeInstances(context.Background(), config.Providers); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to initialize providers")
 	}
-
-	// Initialize Immich providers
-	for _, immichConfig := range config.ImmichProviders {
-		providerConfig := map[string]any{
-			"instance_id":          immichConfig.InstanceID,
-			"url":                  immichConfig.URL,
-			"api_key":              immichConfig.APIKey,
-			"insecure_skip_verify": immichConfig.InsecureSkipVerify,
-		}
-		if err := providerManager.Initialize(context.Background(), "immich", providerConfig); err != nil {
-			logger.Warn().Err(err).Str("instance", immichConfig.InstanceID).Msg("Failed to initialize Immich provider")
-		} else {
-			logger.Info().Str("instance", immichConfig.InstanceID).Str("url", immichConfig.URL).Msg("Immich provider initialized")
-		}
+	logger.Info().
+		Int("configured", len(config.Providers)).
+		Int("initialized", providerManager.Count()).
+		Msg("Providers initialized")
+
+	// Start provider health checks and re-initialization of failed instances
+	var healthMonitor *provider.HealthMonitor
+	if config.Health.Enabled {
+		healthMonitor = provider.NewHealthMonitor(providerManager, config.Health, &logger)
+		healthMonitor.Start(context.Background())
 	}
 
-	// Initialize Jellyfin providers
-	for _, jellyfinConfig := range config.JellyfinProviders {
-		providerConfig := map[string]any{
-			"instance_id": jellyfinConfig.InstanceID,
-			"url":         jellyfinConfig.URL,
-			"api_key":     jellyfinConfig.APIKey,
-			"user_id":     jellyfinConfig.UserID,
-		}
-		if err := providerManager.Initialize(context.Background(), "jellyfin", providerConfig); err != nil {
-			logger.Warn().Err(err).Str("instance", jellyfinConfig.InstanceID).Msg("Failed to initialize Jellyfin provider")
+	// Open the local entity store
+	var entityStore *store.EntityStore
+	if config.EntityStore.Enabled {
+		entityStore, err = store.Open(config.EntityStore.Path)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to open entity store, searching providers live only")
 		} else {
-			logger.Info().Str("instance", jellyfinConfig.InstanceID).Str("url", jellyfinConfig.URL).Msg("Jellyfin provider initialized")
+			logger.Info().Str("path", config.EntityStore.Path).Msg("Entity store opened")
+			providerManager.OnReset(entityStore.Delete)
+			if !config.Sync.Enabled {
+				logger.Warn().Msg("Entity store is enabled but sync is disabled, store will not be updated")
+			}
 		}
 	}
 
-	// Initialize GitLab providers
-	for _, gitlabConfig := range config.GitLabProviders {
-		providerConfig := map[string]any{
-			"instance_id":   gitlabConfig.InstanceID,
-			"url":          gitlabConfig.URL,
-			"access_token": gitlabConfig.AccessToken,
-			"search_issues": gitlabConfig.SearchIssues,
-			"projects":     gitlabConfig.Projects,
+	// Start background provider sync
+	var syncer *provider.Syncer
+	if config.Sync.Enabled {
+		syncer = provider.NewSyncer(providerManager, config.Sync, provider.NewFileWatermarkStore(config.Sync.StatePath), &logger)
+		syncer.SetFailureThreshold(config.Health.FailureThreshold)
+		if entityStore != nil {
+			syncer.OnSync(entityStore.HandleSync)
 		}
-		if err := providerManager.Initialize(context.Background(), "gitlab", providerConfig); err != nil {
-			logger.Warn().Err(err).Str("instance", gitlabConfig.InstanceID).Msg("Failed to initialize GitLab provider")
+		if err := syncer.Start(context.Background()); err != nil {
+			logger.Error().Err(err).Msg("Failed to start provider sync")
+			syncer = nil
 		} else {
-			logger.Info().Str("instance", gitlabConfig.InstanceID).Str("url", gitlabConfig.URL).Msg("GitLab provider initialized")
+			providerManager.OnReset(syncer.Reset)
 		}
 	}
 
@@ -180,13 +133,30 @@ func main() {
 	}
 	logger.Info().Str("strategy", rankingStrategy.Name()).Msg("Ranking strategy initialized")
 
-	federator := search.NewFederator(providerManager, rankingStrategy, &logger, 30*time.Second)
+	federator := search.NewFederator(providerManager, rankingStrategy, &logger, config.Search.Timeout)
+	federator.SetSoftDeadline(config.Search.SoftDeadline)
+	federator.SetLateResultsTTL(config.Search.LateResultsTTL)
+	federator.SetSnapshotTTL(config.Search.SnapshotTTL)
+	federator.SetMaxSnapshots(config.Search.MaxSnapshots)
+	federator.SetPageWindow(config.Search.PageWindow)
+	federator.SetVocabularySize(config.Search.VocabularySize)
+	federator.SetTypeRegistry(typeRegistry)
+	if entityStore != nil {
+		federator.SetCache(entityStore)
+	}
 	ranker := search.NewRanker()
 	filters := search.NewFilters(typeRegistry)
 	relationships := search.NewRelationships(providerManager, &logger)
 
 	// Initialize API handlers
 	handlers := api.NewHandlers(providerManager, federator, ranker, filters, relationships, typeRegistry, &logger)
+	if config.Admin.Enabled {
+		if config.Admin.Token == "" {
+			logger.Error().Msg("Admin API is enabled without a token, not enabling it")
+		} else {
+			handlers.EnableAdmin(config.Admin, instanceStore)
+		}
+	}
 
 	// Setup HTTP server
 	router := mux.NewRouter()
@@ -212,6 +182,15 @@ func main() {
 		}
 	}()
 
+	// Reload the providers list from the config file on SIGHUP
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			reloadProviders(handlers, &logger)
+		}
+	}()
+
 	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
@@ -227,6 +206,19 @@ func main() {
 		logger.Error().Err(err).Msg("Server shutdown failed")
 	}
 
+	// Stop background sync and health checks before shutting down providers
+	if syncer != nil {
+		syncer.Stop()
+	}
+	if healthMonitor != nil {
+		healthMonitor.Stop()
+	}
+	if entityStore != nil {
+		if err := entityStore.Close(); err != nil {
+			logger.Error().Err(err).Msg("Entity store close failed")
+		}
+	}
+
 	// Shutdown providers
 	if err := providerManager.ShutdownAll(ctx); err != nil {
 		logger.Error().Err(err).Msg("Provider shutdown failed")
@@ -237,15 +229,16 @@ func main() {
 
 // Config holds the application configuration.
 type Config struct {
-	HTTPPort            int                        `mapstructure:"http_port"`
-	UI                  UIConfig                   `mapstructure:"ui"`
-	Ranking             search.RankingConfig       `mapstructure:"ranking"`
-	MockEnabled         bool                       `mapstructure:"mock_enabled"`
-	MockEntityCount     int                        `mapstructure:"mock_entity_count"`
-	FilesystemProviders []FilesystemProviderConfig `mapstructure:"filesystem_providers"`
-	ImmichProviders     []ImmichProviderConfig     `mapstructure:"immich_providers"`
-	JellyfinProviders   []JellyfinProviderConfig   `mapstructure:"jellyfin_providers"`
-	GitLabProviders     []GitLabProviderConfig     `mapstructure:"gitlab_providers"`
+	HTTPPort    int                       `mapstructure:"http_port"`
+	UI          UIConfig                  `mapstructure:"ui"`
+	Ranking     search.RankingConfig      `mapstructure:"ranking"`
+	Search      search.FederatorConfig    `mapstructure:"search"`
+	Sync        provider.SyncConfig       `mapstructure:"sync"`
+	EntityStore store.Config              `mapstructure:"entity_store"`
+	Health      provider.HealthConfig     `mapstructure:"health"`
+	Admin       api.AdminConfig           `mapstructure:"admin"`
+	Plugins     []plugin.Config           `mapstructure:"plugins"`
+	Providers   []provider.InstanceConfig `mapstructure:"providers"`
 }
 
 // UIConfig holds configuration for the web UI.
@@ -256,44 +249,60 @@ type UIConfig struct {
 	IndexPath string `mapstructure:"index_path"`
 }
 
-// FilesystemProviderConfig holds configuration for a filesystem provider instance.
-type FilesystemProviderConfig struct {
-	InstanceID string `mapstructure:"instance_id"`
-	URL        string `mapstructure:"url"`
-	APIKey     string `mapstructure:"api_key"`
-}
-
-// ImmichProviderConfig holds configuration for an Immich provider instance.
-type ImmichProviderConfig struct {
-	InstanceID         string `mapstructure:"instance_id"`
-	URL                string `mapstructure:"url"`
-	APIKey             string `mapstructure:"api_key"`
-	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
-}
-
-// JellyfinProviderConfig holds configuration for a Jellyfin provider instance.
-type JellyfinProviderConfig struct {
-	InstanceID string `mapstructure:"instance_id"`
-	URL        string `mapstructure:"url"`
-	APIKey     string `mapstructure:"api_key"`
-	UserID     string `mapstructure:"user_id"`
-}
-
-// GitLabProviderConfig holds configuration for a GitLab provider instance.
-type GitLabProviderConfig struct {
-	InstanceID   string   `mapstructure:"instance_id"`
-	URL          string   `mapstructure:"url"`
-	AccessToken  string   `mapstructure:"access_token"`
-	SearchIssues bool     `mapstructure:"search_issues"`
-	Projects     []string `mapstructure:"projects"`
-}
-
 // loadConfig loads configuration from file and environment.
 func loadConfig() (*Config, error) {
 	// Set defaults
 	viper.SetDefault("http_port", 8080)
-	viper.SetDefault("mock_enabled", true)
-	viper.SetDefault("mock_entity_count", 10)
+
+	// Without a config file, run a single mock instance
+	viper.SetDefault("providers", []map[string]any{
+		{"type": "mock", "instance_id": "default", "config": map[string]any{"entity_count": 10}},
+	})
+
+	bm25Defaults := search.DefaultBM25Config()
+	viper.SetDefault("ranking.bm25.k1", bm25Defaults.K1)
+	viper.SetDefault("ranking.bm25.b", bm25Defaults.B)
+
+	fuzzyDefaults := search.DefaultFuzzyConfig()
+	viper.SetDefault("ranking.fuzzy.enabled", fuzzyDefaults.Enabled)
+	viper.SetDefault("ranking.fuzzy.max_edits", fuzzyDefaults.MaxEdits)
+	viper.SetDefault("ranking.fuzzy.min_similarity", fuzzyDefaults.MinSimilarity)
+
+	fusionDefaults := search.DefaultFusionConfig()
+	viper.SetDefault("ranking.fusion.method", fusionDefaults.Method)
+	viper.SetDefault("ranking.fusion.k", fusionDefaults.K)
+	viper.SetDefault("ranking.fusion.native_weight", fusionDefaults.NativeWeight)
+	viper.SetDefault("ranking.fusion.text_weight", fusionDefaults.TextWeight)
+
+	searchDefaults := search.DefaultFederatorConfig()
+	viper.SetDefault("search.timeout", searchDefaults.Timeout)
+	viper.SetDefault("search.soft_deadline", searchDefaults.SoftDeadline)
+	viper.SetDefault("search.late_results_ttl", searchDefaults.LateResultsTTL)
+	viper.SetDefault("search.snapshot_ttl", searchDefaults.SnapshotTTL)
+	viper.SetDefault("search.max_snapshots", searchDefaults.MaxSnapshots)
+	viper.SetDefault("search.page_window", searchDefaults.PageWindow)
+	viper.SetDefault("search.vocabulary_size", searchDefaults.VocabularySize)
+
+	syncDefaults := provider.DefaultSyncConfig()
+	viper.SetDefault("sync.enabled", syncDefaults.Enabled)
+	viper.SetDefault("sync.full_interval", syncDefaults.FullInterval)
+	viper.SetDefault("sync.incremental_interval", syncDefaults.IncrementalInterval)
+	viper.SetDefault("sync.state_path", syncDefaults.StatePath)
+
+	healthDefaults := provider.DefaultHealthConfig()
+	viper.SetDefault("health.enabled", healthDefaults.Enabled)
+	viper.SetDefault("health.interval", healthDefaults.Interval)
+	viper.SetDefault("health.timeout", healthDefaults.Timeout)
+	viper.SetDefault("health.failure_threshold", healthDefaults.FailureThreshold)
+
+	adminDefaults := api.DefaultAdminConfig()
+	viper.SetDefault("admin.enabled", adminDefaults.Enabled)
+	viper.SetDefault("admin.token", adminDefaults.Token)
+	viper.SetDefault("admin.state_path", adminDefaults.StatePath)
+
+	storeDefaults := store.DefaultConfig()
+	viper.SetDefault("entity_store.enabled", storeDefaults.Enabled)
+	viper.SetDefault("entity_store.path", storeDefaults.Path)
 
 	// Read config file - check config/ dir first, then fallback locations
 	viper.SetConfigName("mifind")
@@ -319,6 +328,37 @@ func loadConfig() (*Config, error) {
 	return &config, nil
 }
 
+// reloadProviders re-reads the providers list from the config file and applies
+// it to the running instances.
+func reloadProviders(handlers *api.Handlers, logger *zerolog.Logger) {
+	logger.Info().Msg("Reloading provider configuration")
+
+	if err := viper.ReadInConfig(); err != nil {
+		logger.Error().Err(err).Msg("Failed to read config file, providers unchanged")
+		return
+	}
+	var instances []provider.InstanceConfig
+	if err := viper.UnmarshalKey("providers", &instances); err != nil {
+		logger.Error().Err(err).Msg("Failed to parse providers, providers unchanged")
+		return
+	}
+
+	result, err := handlers.ReloadProviders(context.Background(), instances)
+	if errors.Is(err, provider.ErrInvalidConfig) {
+		logger.Error().Err(err).Msg("Invalid provider configuration, providers unchanged")
+		return
+	}
+	if err != nil {
+		logger.Error().Err(err).Msg("Some providers failed to reload")
+	}
+	logger.Info().
+		Strs("added", result.Added).
+		Strs("updated", result.Updated).
+		Strs("removed", result.Removed).
+		Int("unchanged", len(result.Unchanged)).
+		Msg("Provider configuration reloaded")
+}
+
 // registerCoreTypes registers core entity types from the central type definitions.
 func registerCoreTypes(registry *types.TypeRegistry, logger zerolog.Logger) {
 	types.RegisterCoreTypes(registry)
@@ -357,12 +397,23 @@ func (w *responseWriter) WriteHeader(status int) {
 	w.ResponseWriter.WriteHeader(status)
 }
 
-// corsMiddleware adds CORS headers.
+// Unwrap returns the wrapped writer, so handlers can flush streamed responses.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// corsMiddleware adds CORS headers. The admin endpoints are left out, so
+// other sites can't reconfigure providers from the operator's browser.
 func corsMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/api/admin/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			w.Header().Set("Access-Control-Allow-Origin", "*")
-			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
 			if r.Method == "OPTIONS" {
@@ -385,6 +436,10 @@ func createRankingStrategy(config search.RankingConfig, logger *zerolog.Logger,
 			return search.NewInMemoryRanker(config), nil
 		}
 		return ranker, nil
+	case "bm25":
+		return search.NewBM25Ranker(config), nil
+	case "fusion":
+		return search.NewFusionRanker(config), nil
 	case "in-memory", "":
 		return search.NewInMemoryRanker(config), nil
 	default:
@@ -7,6 +7,9 @@ require (
 	github.com/meilisearch/meilisearch-go v0.29.0
 	github.com/rs/zerolog v1.34.0
 	github.com/spf13/viper v1.21.0
+	gitlab.com/gitlab-org/api/client-go v1.29.0
+	go.etcd.io/bbolt v1.4.3
+	golang.org/x/time v0.14.0
 )
 
 require (
@@ -28,10 +31,8 @@ require (
 	github.com/spf13/cast v1.10.0 // indirect
 	github.com/spf13/pflag v1.0.10 // indirect
 	github.com/subosito/gotenv v1.6.0 // indirect
-	gitlab.com/gitlab-org/api/client-go v1.29.0 // indirect
 	go.yaml.in/yaml/v3 v3.0.4 // indirect
 	golang.org/x/oauth2 v0.34.0 // indirect
 	golang.org/x/sys v0.39.0 // indirect
 	golang.org/x/text v0.32.0 // indirect
-	golang.org/x/time v0.14.0 // indirect
 )
@@ -0,0 +1,245 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/yourname/mifind/internal/provider"
+)
+
+// AdminConfig configures the runtime provider admin API.
+type AdminConfig struct {
+	// Enabled registers the /api/admin endpoints
+	Enabled bool `mapstructure:"enabled"`
+
+	// Token is the bearer token required by the admin endpoints. The admin
+	// API isn't enabled without one.
+	Token string `mapstructure:"token"`
+
+	// StatePath is the file the provider list is persisted to after runtime
+	// changes. When it exists, it takes precedence over the providers list in
+	// the config file at startup.
+	StatePath string `mapstructure:"state_path"`
+}
+
+// DefaultAdminConfig returns the default admin API configuration.
+func DefaultAdminConfig() AdminConfig {
+	return AdminConfig{
+		Enabled:   false,
+		StatePath: "data/providers.json",
+	}
+}
+
+// EnableAdmin turns on the admin endpoints. Must be called before RegisterRoutes.
+// store receives the provider list after every change and may be nil.
+func (h *Handlers) EnableAdmin(config AdminConfig, store provider.InstanceStore) {
+	h.admin = &config
+	h.instanceStore = store
+}
+
+// registerAdminRoutes registers the provider admin endpoints.
+func (h *Handlers) registerAdminRoutes(apiRouter *mux.Router) {
+	adminRouter := apiRouter.PathPrefix("/admin").Subrouter()
+	adminRouter.Use(h.adminAuth)
+
+	adminRouter.HandleFunc("/providers", h.AdminListProviders).Methods("GET")
+	adminRouter.HandleFunc("/providers", h.AdminAddProvider).Methods("POST")
+	adminRouter.HandleFunc("/providers/{type}/{instance}", h.AdminGetProvider).Methods("GET")
+	adminRouter.HandleFunc("/providers/{type}/{instance}", h.AdminUpdateProvider).Methods("PUT")
+	adminRouter.HandleFunc("/providers/{type}/{instance}", h.AdminRemoveProvider).Methods("DELETE")
+}
+
+// adminAuth requires the configured bearer token on admin requests. Without
+// a configured token every request is refused.
+func (h *Handlers) adminAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if h.admin.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.admin.Token)) != 1 {
+			h.writeError(w, http.StatusUnauthorized, "invalid admin token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AdminListProviders returns the configuration and status of all provider instances.
+func (h *Handlers) AdminListProviders(w http.ResponseWriter, r *http.Request) {
+	instances := h.manager.Instances()
+
+	providers := make([]map[string]interface{}, 0, len(instances))
+	for _, inst := range instances {
+		providers = append(providers, h.adminInstance(inst))
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"providers": providers,
+		"count":     len(providers),
+	})
+}
+
+// AdminGetProvider returns the configuration and status of one provider instance.
+func (h *Handlers) AdminGetProvider(w http.ResponseWriter, r *http.Request) {
+	key := adminInstanceKey(r)
+
+	inst, ok := h.manager.GetInstanceConfig(key)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("provider instance not found: %s", key))
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.adminInstance(inst))
+}
+
+// AdminAddProvider validates and starts a new provider instance.
+// The request body is a providers list entry (type, instance_id, config).
+func (h *Handlers) AdminAddProvider(w http.ResponseWriter, r *http.Request) {
+	var inst provider.InstanceConfig
+	if err := json.NewDecoder(r.Body).Decode(&inst); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+
+	err := h.manager.AddInstance(r.Context(), inst)
+	h.writeAdminResult(w, inst.Key(), http.StatusCreated, err)
+}
+
+// AdminUpdateProvider replaces the configuration of a provider instance.
+// The type and instance ID come from the path; the body holds the new config,
+// either as {"config": {...}} or as a full providers list entry.
+// Secret fields sent back as the redacted placeholder keep their current value.
+func (h *Handlers) AdminUpdateProvider(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	var inst provider.InstanceConfig
+	if err := json.NewDecoder(r.Body).Decode(&inst); err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	if (inst.Type != "" && inst.Type != vars["type"]) || (inst.InstanceID != "" && inst.InstanceID != vars["instance"]) {
+		h.writeError(w, http.StatusBadRequest, "type and instance_id cannot be changed, remove and add the instance instead")
+		return
+	}
+	inst.Type = vars["type"]
+	inst.InstanceID = vars["instance"]
+
+	if current, ok := h.manager.GetInstanceConfig(inst.Key()); ok {
+		inst = h.manager.Registry().KeepSecrets(inst, current)
+	}
+
+	err := h.manager.UpdateInstance(r.Context(), inst)
+	h.writeAdminResult(w, inst.Key(), http.StatusOK, err)
+}
+
+// AdminRemoveProvider drains and shuts down a provider instance.
+func (h *Handlers) AdminRemoveProvider(w http.ResponseWriter, r *http.Request) {
+	key := adminInstanceKey(r)
+
+	err := h.manager.RemoveInstance(r.Context(), key)
+	if errors.Is(err, provider.ErrUnknownInstance) {
+		h.writeAdminError(w, err)
+		return
+	}
+
+	// The instance is gone even if its provider failed to shut down cleanly
+	h.persistInstances()
+	if err != nil {
+		h.writeAdminError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{
+		"removed": key,
+	})
+}
+
+// ReloadProviders reconciles the running provider instances with the given
+// list and persists the result. Used for SIGHUP config reloads.
+func (h *Handlers) ReloadProviders(ctx context.Context, instances []provider.InstanceConfig) (provider.ReloadResult, error) {
+	result, err := h.manager.Reload(ctx, instances)
+	if errors.Is(err, provider.ErrInvalidConfig) {
+		return result, err
+	}
+	h.persistInstances()
+	return result, err
+}
+
+// writeAdminResult persists the provider list and writes the instance after an
+// add or update. An instance that failed to initialize is reported with 502
+// together with its (pending or unchanged) state.
+func (h *Handlers) writeAdminResult(w http.ResponseWriter, key string, status int, err error) {
+	if err != nil && !errors.Is(err, provider.ErrInitFailed) {
+		h.writeAdminError(w, err)
+		return
+	}
+	h.persistInstances()
+
+	body := map[string]interface{}{}
+	if inst, ok := h.manager.GetInstanceConfig(key); ok {
+		body = h.adminInstance(inst)
+	}
+	if err != nil {
+		body["error"] = err.Error()
+		status = http.StatusBadGateway
+	}
+	h.writeJSON(w, status, body)
+}
+
+// writeAdminError maps manager errors to HTTP status codes.
+func (h *Handlers) writeAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, provider.ErrInvalidConfig):
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, provider.ErrInstanceExists):
+		h.writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, provider.ErrUnknownInstance):
+		h.writeError(w, http.StatusNotFound, err.Error())
+	default:
+		h.writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// persistInstances saves the current provider list and drops cached filter
+// values, which may come from instances that changed. The list is read and
+// saved under persistMu, so concurrent changes can't save an older list last.
+func (h *Handlers) persistInstances() {
+	h.filterCache.Clear()
+
+	if h.instanceStore == nil {
+		return
+	}
+
+	h.persistMu.Lock()
+	defer h.persistMu.Unlock()
+	if err := h.instanceStore.Save(h.manager.Instances()); err != nil {
+		h.logger.Error().Err(err).Msg("Failed to persist provider configuration")
+	}
+}
+
+// adminInstance builds the admin view of an instance: its config with secrets
+// redacted, and its current status.
+func (h *Handlers) adminInstance(inst provider.InstanceConfig) map[string]interface{} {
+	redacted := h.manager.Registry().Redact(inst)
+
+	result := map[string]interface{}{
+		"name":        inst.Key(),
+		"type":        redacted.Type,
+		"instance_id": redacted.InstanceID,
+		"config":      redacted.Config,
+	}
+	if status, ok := h.manager.GetStatus(inst.Key()); ok {
+		result["status"] = status
+	}
+	return result
+}
+
+// adminInstanceKey returns the instance key named by the request path.
+func adminInstanceKey(r *http.Request) string {
+	vars := mux.Vars(r)
+	return provider.InstanceKey(vars["type"], vars["instance"])
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/search/filters"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// defaultGeoLimit is the number of features returned when the request sets
+// no limit. Map views show many more results than a page of the result list.
+const defaultGeoLimit = 500
+
+// GeoFeatureCollection is the GeoJSON response of SearchGeo. Besides the
+// GeoJSON members it carries the counts and provider reports of /search.
+type GeoFeatureCollection struct {
+	Type            string           `json:"type"` // Always "FeatureCollection"
+	Features        []GeoFeature     `json:"features"`
+	TotalCount      int              `json:"total_count"`      // Results with coordinates
+	WithoutLocation int              `json:"without_location"` // Results without coordinates, which are left out
+	Duration        float64          `json:"duration_ms"`
+	Providers       []ProviderReport `json:"providers"`
+}
+
+// GeoFeature is a GeoJSON feature for one entity.
+type GeoFeature struct {
+	Type       string         `json:"type"` // Always "Feature"
+	ID         string         `json:"id"`
+	Geometry   GeoPoint       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// GeoPoint is a GeoJSON point geometry.
+type GeoPoint struct {
+	Type        string     `json:"type"`        // Always "Point"
+	Coordinates [2]float64 `json:"coordinates"` // Longitude, latitude
+}
+
+// SearchGeo handles searches for map views. It takes the same requests as
+// /search (a body on POST, query params on GET) and returns the ranked
+// results that have coordinates as a GeoJSON FeatureCollection.
+//
+// Coordinates come from the attribute of the query's radius or bounding box
+// filter, or from the gps attribute. limit and offset page through the
+// features.
+func (h *Handlers) SearchGeo(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	req, err := readSearchRequest(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+
+	query, ok := h.searchQuery(r.Context(), w, req)
+	if !ok {
+		return
+	}
+
+	response := h.federator.Search(r.Context(), query)
+	attribute := geoAttribute(query)
+
+	collection := GeoFeatureCollection{
+		Type:      "FeatureCollection",
+		Features:  []GeoFeature{},
+		Providers: providerReports(response.Reports),
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultGeoLimit
+	}
+
+	for _, ranked := range response.RankedEntities {
+		point, ok := entityPoint(ranked.Entity, attribute)
+		if !ok {
+			collection.WithoutLocation++
+			continue
+		}
+
+		collection.TotalCount++
+		if collection.TotalCount <= req.Offset || len(collection.Features) >= limit {
+			continue
+		}
+
+		collection.Features = append(collection.Features, GeoFeature{
+			Type: "Feature",
+			ID:   ranked.Entity.ID,
+			Geometry: GeoPoint{
+				Type:        "Point",
+				Coordinates: [2]float64{point.Longitude, point.Latitude},
+			},
+			Properties: map[string]any{
+				"title":    ranked.Entity.Title,
+				"type":     ranked.Entity.Type,
+				"provider": ranked.Provider,
+				"score":    ranked.Score,
+				"source":   ranked.Source,
+			},
+		})
+	}
+
+	collection.Duration = float64(time.Since(start).Microseconds()) / 1000
+	w.Header().Set("Content-Type", "application/geo+json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(collection); err != nil {
+		h.logger.Error().Err(err).Msg("failed to encode GeoJSON response")
+	}
+}
+
+// geoAttribute returns the attribute a query filters by location, or the gps
+// attribute if it has no geo filter.
+func geoAttribute(query search.SearchQuery) string {
+	for name, filter := range query.TypedFilters {
+		switch filter.Operation() {
+		case filters.OpGeoRadius, filters.OpGeoBox:
+			return name
+		}
+	}
+	return types.AttrGPS
+}
+
+// entityPoint returns the coordinates of an entity from a GPS attribute, or
+// from its latitude and longitude attributes.
+func entityPoint(entity types.Entity, attribute string) (types.GPS, bool) {
+	if point, ok := search.GPSValue(entity.Attributes[attribute]); ok {
+		return point, true
+	}
+
+	lat, latOK := entity.Attributes[types.AttrLatitude].(float64)
+	lng, lngOK := entity.Attributes[types.AttrLongitude].(float64)
+	if latOK && lngOK {
+		return types.GPS{Latitude: lat, Longitude: lng}, true
+	}
+	return types.GPS{}, false
+}
@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"sort"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
 
@@ -66,6 +65,15 @@ func (c *FilterValueCache) Set(key string, values []provider.FilterOption) {
 	c.expiresAt[key] = time.Now().Add(c.ttl)
 }
 
+// Clear drops all cached values.
+func (c *FilterValueCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values = make(map[string][]provider.FilterOption)
+	c.expiresAt = make(map[string]time.Time)
+}
+
 // Handlers provides HTTP handlers for the mifind API.
 type Handlers struct {
 	manager       *provider.Manager
@@ -76,6 +84,11 @@ type Handlers struct {
 	typeRegistry  *types.TypeRegistry
 	logger        *zerolog.Logger
 	filterCache   *FilterValueCache
+	admin         *AdminConfig
+	instanceStore provider.InstanceStore
+
+	// persistMu orders the saves of the provider list (see persistInstances)
+	persistMu sync.Mutex
 }
 
 // NewHandlers creates a new handlers instance.
@@ -108,6 +121,9 @@ func (h *Handlers) RegisterRoutes(router *mux.Router) {
 	// Search endpoints
 	apiRouter.HandleFunc("/search", h.Search).Methods("POST")
 	apiRouter.HandleFunc("/search/federated", h.SearchFederated).Methods("POST")
+	apiRouter.HandleFunc("/search/late/{token}", h.SearchLate).Methods("GET")
+	apiRouter.HandleFunc("/search/stream", h.SearchStream).Methods("GET", "POST")
+	apiRouter.HandleFunc("/search/geo", h.SearchGeo).Methods("GET", "POST")
 
 	// Entity endpoints
 	apiRouter.HandleFunc("/entity/{id}", h.GetEntity).Methods("GET")
@@ -128,6 +144,11 @@ func (h *Handlers) RegisterRoutes(router *mux.Router) {
 	// Thumbnail proxy endpoint
 	apiRouter.HandleFunc("/thumbnail", h.ProxyThumbnail).Methods("GET")
 
+	// Admin endpoints, only when enabled
+	if h.admin != nil {
+		h.registerAdminRoutes(apiRouter)
+	}
+
 	// Health check
 	apiRouter.HandleFunc("/health", h.Health).Methods("GET")
 
@@ -139,25 +160,63 @@ func (h *Handlers) RegisterRoutes(router *mux.Router) {
 // SearchRequest represents a search request.
 type SearchRequest struct {
 	Query          string             `json:"query"`
+	Q              string             `json:"q,omitempty"` // Query in the search syntax, e.g. type:photo size>10MB vacation
 	Filters        map[string]any     `json:"filters,omitempty"`
 	Type           string             `json:"type,omitempty"`
 	Limit          int                `json:"limit,omitempty"`
 	Offset         int                `json:"offset,omitempty"`
+	Cursor         string             `json:"cursor,omitempty"` // next_cursor of a previous page; the other fields except limit are ignored
+	Sort           string             `json:"sort,omitempty"`   // Sort clauses, e.g. "modified desc, size asc" (default: score)
 	TypeWeights    map[string]float64 `json:"type_weights,omitempty"`
 	IncludeRelated bool               `json:"include_related,omitempty"`
 	MaxDepth       int                `json:"max_depth,omitempty"`
+	CollectLate    bool               `json:"collect_late,omitempty"`
+	Timezone       string             `json:"timezone,omitempty"` // IANA timezone of date expressions, e.g. Europe/Berlin (default UTC)
 }
 
+// defaultPageSize is the number of entities per page when the request sets
+// no limit.
+const defaultPageSize = 24
+
 // SearchResponse represents a search response.
 type SearchResponse struct {
-	Entities     []EntityWithScore                   `json:"entities"`
-	TotalCount   int                                 `json:"total_count"`
-	TypeCounts   map[string]int                      `json:"type_counts"`
-	Duration     float64                             `json:"duration_ms"`
-	Filters      search.FilterResult                 `json:"filters,omitempty"`
+	Entities     []EntityWithScore                    `json:"entities"`
+	TotalCount   int                                  `json:"total_count"`
+	TypeCounts   map[string]int                       `json:"type_counts"`
+	Duration     float64                              `json:"duration_ms"`
+	Filters      search.FilterResult                  `json:"filters,omitempty"`
 	Capabilities map[string]provider.FilterCapability `json:"capabilities,omitempty"`
-	Values       map[string][]provider.FilterOption  `json:"values,omitempty"` // Pre-obtained filter values for provider-based filters
-	Attributes   map[string]types.AttributeDef       `json:"attributes,omitempty"` // Full attribute definitions for generic UI rendering
+	Values       map[string][]provider.FilterOption   `json:"values,omitempty"`     // Pre-obtained filter values for provider-based filters
+	Attributes   map[string]types.AttributeDef        `json:"attributes,omitempty"` // Full attribute definitions for generic UI rendering
+	Providers    []ProviderReport                     `json:"providers"`
+	LateToken    string                               `json:"late_token,omitempty"`  // Fetch late provider results from /search/late/{token}
+	NextCursor   string                               `json:"next_cursor,omitempty"` // Pass as cursor to read the next page from the stored results
+	HasMore      bool                                 `json:"has_more,omitempty"`    // Providers have results beyond total_count
+	Suggestion   string                               `json:"suggestion,omitempty"`  // Did you mean: the query with misspelled words corrected
+}
+
+// ProviderReport reports how a provider instance fared in a search.
+type ProviderReport struct {
+	Provider   string  `json:"provider"`
+	Status     string  `json:"status"` // "ok", "late", "failed" or "skipped"
+	ErrorClass string  `json:"error_class,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	Duration   float64 `json:"duration_ms"`
+}
+
+// providerReports converts federator reports for the response.
+func providerReports(reports []search.ProviderReport) []ProviderReport {
+	result := make([]ProviderReport, len(reports))
+	for i, report := range reports {
+		result[i] = ProviderReport{
+			Provider:   report.Provider,
+			Status:     report.Status,
+			ErrorClass: report.ErrorClass,
+			Error:      report.Error,
+			Duration:   float64(report.Duration.Microseconds()) / 1000,
+		}
+	}
+	return result
 }
 
 // EntityWithScore is an entity with its ranking score.
@@ -165,6 +224,7 @@ type EntityWithScore struct {
 	types.Entity
 	Score    float64 `json:"score,omitempty"`
 	Provider string  `json:"provider,omitempty"`
+	Source   string  `json:"source,omitempty"` // "live" or "cached"
 }
 
 // Search handles search requests.
@@ -177,15 +237,62 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPageSize
+	}
+
+	var response search.FederatedResponse
+	if req.Cursor != "" {
+		// Read the page from the results stored by the first request
+		cursor, err := search.ParseCursor(req.Cursor)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+			return
+		}
+		response, err = h.federator.ReadSnapshot(r.Context(), cursor.Snapshot, cursor.Offset+limit)
+		if err != nil {
+			h.writeError(w, http.StatusNotFound, "cursor not found or expired")
+			return
+		}
+		req.Offset = cursor.Offset
+	} else {
+		query, ok := h.searchQuery(r.Context(), w, req)
+		if !ok {
+			return
+		}
+
+		// Execute search and store the ranked results for the next pages
+		response = h.federator.SearchSnapshot(r.Context(), query, req.Offset+limit)
+	}
+
+	resp := h.searchPage(r.Context(), req, response, start)
+	if next := req.Offset + limit; response.Snapshot != "" && (next < len(response.RankedEntities) || response.HasMore) {
+		resp.NextCursor = search.Cursor{Snapshot: response.Snapshot, Offset: next}.String()
+	}
+	resp.HasMore = response.HasMore
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// searchQuery validates a search request and converts it to a federator
+// query. On invalid requests it writes the error response and returns false.
+func (h *Handlers) searchQuery(ctx context.Context, w http.ResponseWriter, req SearchRequest) (search.SearchQuery, bool) {
 	// Debug log the incoming request
 	h.logger.Debug().
 		Str("query", req.Query).
+		Str("q", req.Q).
 		Str("type", req.Type).
 		Interface("filters", req.Filters).
 		Msg("Search request received")
 
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid timezone: %v", err))
+		return search.SearchQuery{}, false
+	}
+
 	// Parse and validate filters using the new typed system
-	typedQuery, err := search.ParseAndValidate(req.Query, req.Filters, h.typeRegistry)
+	typedQuery, err := search.ParseAndValidate(req.Query, req.Filters, h.typeRegistry, loc)
 	if err != nil {
 		// Log the validation error for debugging
 		h.logger.Debug().Err(err).Interface("filters", req.Filters).Msg("Filter validation failed")
@@ -197,7 +304,7 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 				"error":   "filter validation failed",
 				"details": formatValidationErrors(multiErr.AllErrors()),
 			})
-			return
+			return search.SearchQuery{}, false
 		}
 		if valErr, ok := err.(*filters.ValidationError); ok {
 			h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
@@ -205,10 +312,10 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 				"field":  valErr.FilterName,
 				"reason": valErr.Reason,
 			})
-			return
+			return search.SearchQuery{}, false
 		}
 		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
-		return
+		return search.SearchQuery{}, false
 	}
 
 	// Set additional query fields
@@ -218,12 +325,50 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 	typedQuery.MaxDepth = req.MaxDepth
 	// Don't set typedQuery.Limit/Offset - we'll paginate after ranking
 
+	typedQuery.Sort, err = search.ParseSort(req.Sort, h.typeRegistry)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid sort: %v", err))
+		return search.SearchQuery{}, false
+	}
+
+	// Add the text, type and filters written in the search syntax
+	if req.Q != "" {
+		parsed, err := search.ParseQuery(req.Q, h.typeRegistry, h.getAllAttributesWithExtensions(ctx), loc)
+		if err == nil {
+			err = typedQuery.Merge(parsed)
+		}
+		if err != nil {
+			h.writeQueryError(w, err)
+			return search.SearchQuery{}, false
+		}
+	}
+
 	// Convert to legacy search query for federator
 	query := typedQuery.ToSearchQuery()
+	query.CollectLate = req.CollectLate
+	return query, true
+}
 
-	// Execute search (get all results from providers)
-	response := h.federator.Search(r.Context(), query)
+// writeQueryError writes the response for a query string that failed to
+// parse, with the position of the error if known.
+func (h *Handlers) writeQueryError(w http.ResponseWriter, err error) {
+	var queryErr *search.QueryError
+	if errors.As(err, &queryErr) {
+		h.writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+			"error":    "invalid query",
+			"reason":   queryErr.Reason,
+			"position": queryErr.Pos,
+			"end":      queryErr.End,
+		})
+		return
+	}
+	h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid query: %v", err))
+}
 
+// searchPage ranks, pages and describes the results of a search for the
+// response: the requested page of entities, plus filters, capabilities and
+// attributes extracted from all results.
+func (h *Handlers) searchPage(ctx context.Context, req SearchRequest, response search.FederatedResponse, start time.Time) SearchResponse {
 	// Use the ranked entities from the Federator (which now includes ranking with scores)
 	result := search.RankedResult{
 		Entities:   response.RankedEntities,
@@ -235,7 +380,7 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 	// Apply pagination after ranking
 	limit := req.Limit
 	if limit == 0 {
-		limit = 24 // Default page size
+		limit = defaultPageSize
 	}
 	offset := req.Offset
 
@@ -261,6 +406,7 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 			Entity:   ranked.Entity,
 			Score:    ranked.Score,
 			Provider: ranked.Provider,
+			Source:   ranked.Source,
 		}
 	}
 
@@ -272,17 +418,17 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Extract filters from search results
-	filterResult := h.filters.ExtractFilters(allEntities, query.Type)
+	filterResult := h.filters.ExtractFilters(allEntities, req.Type)
 
 	// Get capabilities from providers that returned results
-	capabilities := h.getProviderCapabilitiesForResults(r.Context(), response.Results)
+	capabilities := h.getProviderCapabilitiesForResults(ctx, response.Results)
 
 	// Always include type filter capabilities from type registry
 	// Include actual counts from search results
 	h.addTypeFilterCapabilities(capabilities, result.TypeCounts)
 
 	// Fetch pre-obtained filter values for provider-based filters
-	preObtainedValues := h.getPreObtainedFilterValues(r.Context(), capabilities)
+	preObtainedValues := h.getPreObtainedFilterValues(ctx, capabilities)
 
 	// Merge provider values with result counts for provider-based filters
 	// isBlankSearch is false since we're in a search request with results
@@ -292,7 +438,7 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 	attributes := h.typeRegistry.GetAllAttributes()
 
 	// Merge with provider-specific attribute extensions (provider extensions override core)
-	providerExtensions := h.manager.GetAttributeExtensions(r.Context())
+	providerExtensions := h.manager.GetAttributeExtensions(ctx)
 	for name, attrDef := range providerExtensions {
 		attributes[name] = attrDef
 	}
@@ -306,9 +452,69 @@ func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
 		Capabilities: capabilities,
 		Values:       mergedValues,
 		Attributes:   attributes,
+		Providers:    providerReports(response.Reports),
+		LateToken:    response.LateToken,
+		Suggestion:   response.Suggestion,
 	}
+	return resp
+}
 
-	h.writeJSON(w, http.StatusOK, resp)
+// LateResponse represents the late provider results of an earlier search.
+type LateResponse struct {
+	Entities   []EntityWithScore `json:"entities"`
+	TotalCount int               `json:"total_count"`
+	TypeCounts map[string]int    `json:"type_counts"`
+	Duration   float64           `json:"duration_ms"`
+	Providers  []ProviderReport  `json:"providers"`
+	LateToken  string            `json:"late_token,omitempty"` // Set while results are still outstanding
+}
+
+// SearchLate returns results of providers that missed their soft deadline in
+// a search made with collect_late. It waits for the outstanding providers,
+// at most for the "wait" query param if given (e.g. "2s").
+func (h *Handlers) SearchLate(w http.ResponseWriter, r *http.Request) {
+	token := mux.Vars(r)["token"]
+
+	ctx := r.Context()
+	if wait := r.URL.Query().Get("wait"); wait != "" {
+		d, err := time.ParseDuration(wait)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid wait: %v", err))
+			return
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	response, err := h.federator.CollectLate(ctx, token)
+	if err != nil {
+		if errors.Is(err, search.ErrLateResultsNotFound) {
+			h.writeError(w, http.StatusNotFound, "late results not found or expired")
+			return
+		}
+		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to collect late results: %v", err))
+		return
+	}
+
+	entities := make([]EntityWithScore, len(response.RankedEntities))
+	for i, ranked := range response.RankedEntities {
+		entities[i] = EntityWithScore{
+			Entity:   ranked.Entity,
+			Score:    ranked.Score,
+			Provider: ranked.Provider,
+			Source:   ranked.Source,
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, LateResponse{
+		Entities:   entities,
+		TotalCount: len(entities),
+		TypeCounts: response.TypeCounts,
+		Duration:   float64(response.Duration.Microseconds()) / 1000,
+		Providers:  providerReports(response.Reports),
+		LateToken:  response.LateToken,
+	})
 }
 
 // SearchFederatedResponse represents a federated search response.
@@ -318,17 +524,37 @@ type SearchFederatedResponse struct {
 	TypeCounts map[string]int   `json:"type_counts"`
 	HasErrors  bool             `json:"has_errors"`
 	Duration   float64          `json:"duration_ms"`
+	Providers  []ProviderReport `json:"providers"`
+	LateToken  string           `json:"late_token,omitempty"`
 }
 
 // ProviderResult represents results from a single provider.
 type ProviderResult struct {
 	Provider   string         `json:"provider"`
+	Source     string         `json:"source"`
 	Entities   []types.Entity `json:"entities"`
 	Error      string         `json:"error,omitempty"`
 	Duration   float64        `json:"duration_ms"`
 	TypeCounts map[string]int `json:"type_counts"`
 }
 
+// newProviderResult converts a federator result for the response.
+func newProviderResult(result search.FederatedResult) ProviderResult {
+	errMsg := ""
+	if result.Error != nil {
+		errMsg = result.Error.Error()
+	}
+
+	return ProviderResult{
+		Provider:   result.Provider,
+		Source:     result.Source,
+		Entities:   result.Entities,
+		Error:      errMsg,
+		Duration:   float64(result.Duration.Microseconds()) / 1000,
+		TypeCounts: result.TypeCounts,
+	}
+}
+
 // SearchFederated handles federated search requests (returns per-provider results).
 func (h *Handlers) SearchFederated(w http.ResponseWriter, r *http.Request) {
 	var req SearchRequest
@@ -337,12 +563,51 @@ func (h *Handlers) SearchFederated(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	loc, err := time.LoadLocation(req.Timezone)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid timezone: %v", err))
+		return
+	}
+
 	// Build search query
 	query := search.NewSearchQuery(req.Query)
-	query.Filters = req.Filters
-	query.Type = req.Type
+	if req.Q != "" {
+		typed := search.NewTypedSearchQuery(req.Query)
+		typed.Type = req.Type
+		parsed, err := search.ParseQuery(req.Q, h.typeRegistry, h.getAllAttributesWithExtensions(r.Context()), loc)
+		if err == nil {
+			err = typed.Merge(parsed)
+		}
+		if err != nil {
+			h.writeQueryError(w, err)
+			return
+		}
+		query = typed.ToSearchQuery()
+	}
+	for name, value := range req.Filters {
+		if !filters.IsGroupKey(name) {
+			query.Filters[name] = value
+		}
+	}
+	parser := filters.NewParser(h.typeRegistry)
+	parser.SetLocation(loc)
+	filterExpr, err := parser.ParseExpression(req.Filters)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+	query.FilterExpr = filterExpr
+	if req.Type != "" {
+		query.Type = req.Type
+	}
+	query.Sort, err = search.ParseSort(req.Sort, h.typeRegistry)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid sort: %v", err))
+		return
+	}
 	query.Limit = req.Limit
 	query.Offset = req.Offset
+	query.CollectLate = req.CollectLate
 
 	// Execute search
 	response := h.federator.Search(r.Context(), query)
@@ -350,18 +615,7 @@ func (h *Handlers) SearchFederated(w http.ResponseWriter, r *http.Request) {
 	// Build response
 	results := make([]ProviderResult, len(response.Results))
 	for i, result := range response.Results {
-		errMsg := ""
-		if result.Error != nil {
-			errMsg = result.Error.Error()
-		}
-
-		results[i] = ProviderResult{
-			Provider:   result.Provider,
-			Entities:   result.Entities,
-			Error:      errMsg,
-			Duration:   float64(result.Duration.Microseconds()) / 1000,
-			TypeCounts: result.TypeCounts,
-		}
+		results[i] = newProviderResult(result)
 	}
 
 	resp := SearchFederatedResponse{
@@ -370,6 +624,8 @@ func (h *Handlers) SearchFederated(w http.ResponseWriter, r *http.Request) {
 		TypeCounts: response.TypeCounts,
 		HasErrors:  response.HasErrors,
 		Duration:   float64(response.Duration.Microseconds()) / 1000,
+		Providers:  providerReports(response.Reports),
+		LateToken:  response.LateToken,
 	}
 
 	h.writeJSON(w, http.StatusOK, resp)
@@ -382,8 +638,7 @@ func (h *Handlers) GetEntity(w http.ResponseWriter, r *http.Request) {
 
 	entity, err := h.manager.Hydrate(r.Context(), id)
 	if err != nil {
-		if err == provider.ErrNotFound {
-			h.writeError(w, http.StatusNotFound, fmt.Sprintf("entity not found: %s", id))
+		if h.writeNotFoundError(w, id, err) {
 			return
 		}
 		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get entity: %v", err))
@@ -408,8 +663,7 @@ func (h *Handlers) ExpandEntity(w http.ResponseWriter, r *http.Request) {
 
 	expanded, err := h.relationships.Expand(r.Context(), id, maxDepth)
 	if err != nil {
-		if err == provider.ErrNotFound {
-			h.writeError(w, http.StatusNotFound, fmt.Sprintf("entity not found: %s", id))
+		if h.writeNotFoundError(w, id, err) {
 			return
 		}
 		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to expand entity: %v", err))
@@ -435,8 +689,7 @@ func (h *Handlers) GetRelated(w http.ResponseWriter, r *http.Request) {
 
 	entities, err := h.relationships.GetRelated(r.Context(), id, relType, limit)
 	if err != nil {
-		if err == provider.ErrNotFound {
-			h.writeError(w, http.StatusNotFound, fmt.Sprintf("entity not found: %s", id))
+		if h.writeNotFoundError(w, id, err) {
 			return
 		}
 		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get related: %v", err))
@@ -460,6 +713,7 @@ func (h *Handlers) ListTypes(w http.ResponseWriter, r *http.Request) {
 			"name":        t.Name,
 			"parent":      t.Parent,
 			"description": t.Description,
+			"provider":    h.typeRegistry.Owner(t.Name),
 		})
 	}
 
@@ -492,6 +746,7 @@ func (h *Handlers) GetType(w http.ResponseWriter, r *http.Request) {
 		"parent":      typeDef.Parent,
 		"ancestors":   ancestorNames,
 		"description": typeDef.Description,
+		"provider":    h.typeRegistry.Owner(name),
 		"attributes":  typeDef.Attributes,
 		"filters":     typeDef.Filters,
 	})
@@ -902,16 +1157,16 @@ func (h *Handlers) mergeProviderWithEntityCounts(
 	return mergedOptions
 }
 
-// ListProviders returns all registered providers.
+// ListProviders returns all provider instances, one entry per "providerType:instanceID".
 func (h *Handlers) ListProviders(w http.ResponseWriter, r *http.Request) {
-	// Get provider list from registry
-	// TODO: Access registry through manager or dependency
 	statuses := h.federator.GetStatus()
 
 	providers := make([]map[string]interface{}, 0, len(statuses))
 	for _, s := range statuses {
 		providers = append(providers, map[string]interface{}{
 			"name":                 s.Name,
+			"type":                 s.ProviderType,
+			"instance_id":          s.InstanceID,
 			"connected":            s.Connected,
 			"supports_incremental": s.SupportsIncremental,
 		})
@@ -966,6 +1221,9 @@ func (h *Handlers) Index(w http.ResponseWriter, r *http.Request) {
 		"endpoints": map[string]string{
 			"/search":              "POST - Search across all providers",
 			"/search/federated":    "POST - Search with per-provider results",
+			"/search/late/{token}": "GET - Late provider results of a search",
+			"/search/stream":       "GET/POST - Stream per-provider results, then the ranked page (SSE)",
+			"/search/geo":          "GET/POST - Results with coordinates as GeoJSON",
 			"/entity/{id}":         "GET - Get entity by ID",
 			"/entity/{id}/expand":  "GET - Get entity with relationships",
 			"/entity/{id}/related": "GET - Get related entities",
@@ -1003,44 +1261,48 @@ func (h *Handlers) ProxyThumbnail(w http.ResponseWriter, r *http.Request) {
 
 // proxyThumbnailByID looks up an entity and proxies its thumbnail.
 func (h *Handlers) proxyThumbnailByID(w http.ResponseWriter, r *http.Request, entityID string) {
-	// Parse entity ID to get provider name
-	parts := strings.Split(entityID, ":")
-	if len(parts) != 3 {
+	// Parse entity ID to find the owning provider instance
+	id := provider.EntityID(entityID)
+	if !id.IsValid() {
 		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid entity ID format: %s", entityID))
 		return
 	}
-	providerName := parts[0]
+	instanceKey := id.InstanceKey()
 
 	// Check if the provider implements ThumbnailProvider
-	prov, ok := h.manager.Get(providerName)
+	prov, ok := h.manager.Get(instanceKey)
 	if !ok {
-		h.writeError(w, http.StatusNotFound, fmt.Sprintf("provider not found: %s", providerName))
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("provider not found: %s", instanceKey))
 		return
 	}
 
-	if thumbnailProvider, ok := prov.(provider.ThumbnailProvider); ok {
+	if thumbnailProvider, ok := provider.Unwrap(prov).(provider.ThumbnailProvider); ok {
 		// Use the provider's authenticated thumbnail fetching
 		data, contentType, err := thumbnailProvider.GetThumbnail(r.Context(), entityID)
-		if err != nil {
+		var perr *provider.ProviderError
+		switch {
+		case errors.As(err, &perr) && perr.Type == provider.ErrorTypeNotSupported:
+			// Plugin providers implement the interface even without thumbnail
+			// support; fall back to the thumbnail URL below
+		case err != nil:
 			h.writeError(w, http.StatusBadGateway, fmt.Sprintf("failed to fetch thumbnail: %v", err))
 			return
+		default:
+			// Set headers and write response
+			if contentType != "" {
+				w.Header().Set("Content-Type", contentType)
+			}
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+			w.WriteHeader(http.StatusOK)
+			w.Write(data)
+			return
 		}
-
-		// Set headers and write response
-		if contentType != "" {
-			w.Header().Set("Content-Type", contentType)
-		}
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.WriteHeader(http.StatusOK)
-		w.Write(data)
-		return
 	}
 
 	// Fall back to hydrating entity and getting thumbnail URL from attributes
 	entity, err := h.manager.Hydrate(r.Context(), entityID)
 	if err != nil {
-		if err == provider.ErrNotFound {
-			h.writeError(w, http.StatusNotFound, fmt.Sprintf("entity not found: %s", entityID))
+		if h.writeNotFoundError(w, entityID, err) {
 			return
 		}
 		h.writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get entity: %v", err))
@@ -1140,6 +1402,23 @@ func (h *Handlers) writeError(w http.ResponseWriter, status int, message string)
 	})
 }
 
+// writeNotFoundError writes a 404 response if err reports a missing entity or an
+// unknown provider instance, and a 503 response if the entity's instance is
+// configured but unavailable. Returns true if a response was written.
+func (h *Handlers) writeNotFoundError(w http.ResponseWriter, id string, err error) bool {
+	switch {
+	case errors.Is(err, provider.ErrUnknownInstance):
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("provider instance not found for entity: %s", id))
+	case errors.Is(err, provider.ErrInstanceUnavailable):
+		h.writeError(w, http.StatusServiceUnavailable, fmt.Sprintf("provider instance unavailable for entity: %s", id))
+	case errors.Is(err, provider.ErrNotFound):
+		h.writeError(w, http.StatusNotFound, fmt.Sprintf("entity not found: %s", id))
+	default:
+		return false
+	}
+	return true
+}
+
 // formatValidationErrors formats validation errors for API responses.
 func formatValidationErrors(errs []error) []map[string]interface{} {
 	details := make([]map[string]interface{}, 0, len(errs))
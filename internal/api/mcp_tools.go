@@ -3,7 +3,9 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/yourname/mifind/internal/provider"
@@ -49,6 +51,10 @@ func (m *MCPServer) ListTools() []MCPTool {
 						"type":        "string",
 						"description": "The search query string",
 					},
+					"q": map[string]interface{}{
+						"type":        "string",
+						"description": "Query in the search syntax, e.g. type:photo camera:\"Canon EOS\" size>10MB modified:2024-01..2024-06 -extension:gif vacation (optional, instead of or in addition to query)",
+					},
 					"type": map[string]interface{}{
 						"type":        "string",
 						"description": "Filter by entity type (optional)",
@@ -57,12 +63,19 @@ func (m *MCPServer) ListTools() []MCPTool {
 						"type":        "integer",
 						"description": "Maximum number of results to return (optional)",
 					},
+					"sort": map[string]interface{}{
+						"type":        "string",
+						"description": "Sort by attributes instead of relevance, e.g. \"modified desc\" or \"size desc, modified desc\" (optional)",
+					},
 					"filters": map[string]interface{}{
 						"type":        "object",
-						"description": "Attribute filters to apply (optional)",
+						"description": "Attribute filters to apply (optional). \"$or\": [{...}, {...}], \"$and\": [...] and \"$not\": {...} combine filter objects. Dates can be date expressions, e.g. {\"modified\": {\"min\": \"-7d\"}} or {\"created\": {\"eq\": \"last-month\"}}",
+					},
+					"timezone": map[string]interface{}{
+						"type":        "string",
+						"description": "IANA timezone that dates like yesterday or this-year are resolved in, e.g. Europe/Berlin (optional, default UTC)",
 					},
 				},
-				"required": []string{"query"},
 			},
 		},
 		{
@@ -185,13 +198,37 @@ func (m *MCPServer) CallTool(ctx context.Context, name string, args map[string]i
 
 // search_entities implementation
 func (m *MCPServer) searchEntities(ctx context.Context, args map[string]interface{}) (interface{}, error) {
-	query, ok := args["query"].(string)
-	if !ok || query == "" {
-		return nil, fmt.Errorf("query is required")
+	query, _ := args["query"].(string)
+	q, _ := args["q"].(string)
+	if query == "" && q == "" {
+		return nil, fmt.Errorf("query or q is required")
+	}
+
+	timezone, _ := args["timezone"].(string)
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+
+	// Parse the filters, resolving date expressions in the timezone
+	filterArgs, _ := args["filters"].(map[string]interface{})
+	typed, err := search.ParseAndValidate(query, filterArgs, m.handlers.typeRegistry, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filters: %w", err)
+	}
+
+	if q != "" {
+		parsed, err := search.ParseQuery(q, m.handlers.typeRegistry, m.handlers.getAllAttributesWithExtensions(ctx), loc)
+		if err == nil {
+			err = typed.Merge(parsed)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid q: %w", err)
+		}
 	}
 
 	// Build search query
-	searchQuery := search.NewSearchQuery(query)
+	searchQuery := typed.ToSearchQuery()
 
 	if typeName, ok := args["type"].(string); ok {
 		searchQuery.Type = typeName
@@ -201,8 +238,10 @@ func (m *MCPServer) searchEntities(ctx context.Context, args map[string]interfac
 		searchQuery.Limit = int(limit)
 	}
 
-	if filters, ok := args["filters"].(map[string]interface{}); ok {
-		searchQuery.Filters = filters
+	sortSpec, _ := args["sort"].(string)
+	searchQuery.Sort, err = search.ParseSort(sortSpec, m.handlers.typeRegistry)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sort: %w", err)
 	}
 
 	// Execute search
@@ -218,16 +257,32 @@ func (m *MCPServer) searchEntities(ctx context.Context, args map[string]interfac
 			"title":       ranked.Entity.Title,
 			"description": ranked.Entity.Description,
 			"provider":    ranked.Provider,
+			"source":      ranked.Source,
 			"attributes":  ranked.Entity.Attributes,
 			"score":       ranked.Score,
 		})
 	}
 
-	return map[string]interface{}{
+	// Tell the caller which providers are missing from the results
+	var degraded []ProviderReport
+	for _, report := range providerReports(response.Reports) {
+		if report.Status == search.ReportLate || report.Status == search.ReportFailed {
+			degraded = append(degraded, report)
+		}
+	}
+
+	output := map[string]interface{}{
 		"entities":    entities,
 		"total_count": result.TotalCount,
 		"type_counts": result.TypeCounts,
-	}, nil
+	}
+	if len(degraded) > 0 {
+		output["degraded_providers"] = degraded
+	}
+	if response.Suggestion != "" {
+		output["did_you_mean"] = response.Suggestion
+	}
+	return output, nil
 }
 
 // describe_entity implementation
@@ -464,9 +519,12 @@ func NewMCPErrorResponse(code int, message string) *MCPResponse {
 func (m *MCPServer) CallToolWithResponse(ctx context.Context, name string, args map[string]interface{}) *MCPResponse {
 	result, err := m.CallTool(ctx, name, args)
 	if err != nil {
-		if err == provider.ErrNotFound {
+		if errors.Is(err, provider.ErrNotFound) || errors.Is(err, provider.ErrUnknownInstance) {
 			return NewMCPErrorResponse(404, err.Error())
 		}
+		if errors.Is(err, provider.ErrInstanceUnavailable) {
+			return NewMCPErrorResponse(503, err.Error())
+		}
 		return NewMCPErrorResponse(500, err.Error())
 	}
 	return NewMCPResponse(result)
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yourname/mifind/internal/search"
+)
+
+// Server-Sent Events emitted by SearchStream.
+const (
+	// EventProvider carries one provider's results as soon as they arrive
+	EventProvider = "provider"
+
+	// EventResult carries the ranked, merged page once all providers are done
+	EventResult = "result"
+)
+
+// StreamProviderEvent is the data of a "provider" event.
+type StreamProviderEvent struct {
+	ProviderResult
+	Status     string `json:"status"` // "ok", "late", "failed" or "skipped"
+	ErrorClass string `json:"error_class,omitempty"`
+}
+
+// SearchStream handles streaming search requests with Server-Sent Events.
+// Each provider's results are sent in a "provider" event as soon as they
+// arrive, in arrival order, and the ranked page with filters and type counts
+// follows in a final "result" event, like the response of /search.
+//
+// POST takes a SearchRequest body. GET takes the same fields as query params,
+// with filters JSON-encoded, for use with EventSource.
+func (h *Handlers) SearchStream(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	req, err := readSearchRequest(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request: %v", err))
+		return
+	}
+
+	query, ok := h.searchQuery(r.Context(), w, req)
+	if !ok {
+		return
+	}
+
+	// The stream lasts as long as the slowest provider, which may outlast the
+	// server's write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	_ = rc.Flush()
+
+	response := h.federator.SearchStream(r.Context(), query, func(result search.FederatedResult, report search.ProviderReport) {
+		h.writeEvent(w, rc, EventProvider, StreamProviderEvent{
+			ProviderResult: newProviderResult(result),
+			Status:         report.Status,
+			ErrorClass:     report.ErrorClass,
+		})
+	})
+	if r.Context().Err() != nil {
+		// The client is gone
+		return
+	}
+
+	h.writeEvent(w, rc, EventResult, h.searchPage(r.Context(), req, response, start))
+}
+
+// writeEvent writes a Server-Sent Event with JSON data and flushes it.
+func (h *Handlers) writeEvent(w http.ResponseWriter, rc *http.ResponseController, event string, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		h.logger.Error().Err(err).Str("event", event).Msg("failed to encode event")
+		return
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return
+	}
+	_ = rc.Flush()
+}
+
+// readSearchRequest reads a search request from a POST body or from GET query params.
+func readSearchRequest(r *http.Request) (SearchRequest, error) {
+	var req SearchRequest
+	if r.Method == http.MethodPost {
+		err := json.NewDecoder(r.Body).Decode(&req)
+		return req, err
+	}
+
+	params := r.URL.Query()
+	req.Query = params.Get("query")
+	req.Q = params.Get("q")
+	req.Type = params.Get("type")
+	req.Timezone = params.Get("timezone")
+	req.Sort = params.Get("sort")
+	if filters := params.Get("filters"); filters != "" {
+		if err := json.Unmarshal([]byte(filters), &req.Filters); err != nil {
+			return req, fmt.Errorf("filters: %w", err)
+		}
+	}
+	for name, target := range map[string]*int{"limit": &req.Limit, "offset": &req.Offset, "max_depth": &req.MaxDepth} {
+		if value := params.Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return req, fmt.Errorf("%s: %w", name, err)
+			}
+			*target = n
+		}
+	}
+	req.IncludeRelated = params.Get("include_related") == "true"
+	req.CollectLate = params.Get("collect_late") == "true"
+	return req, nil
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/yourname/mifind/internal/api"
+)
+
+// TestSearchGeo tests that results with coordinates are returned as GeoJSON
+// and that radius and bounding box filters apply to them.
+func TestSearchGeo(t *testing.T) {
+	server := newTestServer(t)
+
+	// The mock instances each have one entity with GPS coordinates in San Francisco
+	tests := []struct {
+		name     string
+		filters  string
+		features int
+	}{
+		{name: "all", filters: `{}`, features: 2},
+		{name: "radius", filters: `{"gps": {"radius": {"lat": 37.79, "lng": -122.40, "km": 5}}}`, features: 2},
+		{name: "radius elsewhere", filters: `{"gps": {"radius": {"lat": 52.52, "lng": 13.40, "km": 50}}}`, features: 0},
+		{name: "bbox", filters: `{"gps": {"bbox": {"south": 37.7, "west": -122.5, "north": 37.9, "east": -122.3}}}`, features: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"query": "", "filters": ` + tt.filters + `}`
+			resp, err := http.Post(server.URL+"/api/search/geo", "application/json", strings.NewReader(body))
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected 200, got %d", resp.StatusCode)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != "application/geo+json" {
+				t.Errorf("Expected GeoJSON, got %q", ct)
+			}
+
+			var collection api.GeoFeatureCollection
+			if err := json.NewDecoder(resp.Body).Decode(&collection); err != nil {
+				t.Fatalf("Decoding response failed: %v", err)
+			}
+			if collection.Type != "FeatureCollection" || len(collection.Features) != tt.features {
+				t.Fatalf("Expected a FeatureCollection of %d features, got %q with %d", tt.features, collection.Type, len(collection.Features))
+			}
+			for _, feature := range collection.Features {
+				lng, lat := feature.Geometry.Coordinates[0], feature.Geometry.Coordinates[1]
+				if feature.Geometry.Type != "Point" || lat < 37.7 || lat > 37.9 || lng < -122.5 || lng > -122.3 {
+					t.Errorf("Unexpected geometry %+v for %s", feature.Geometry, feature.ID)
+				}
+			}
+		})
+	}
+
+	// Invalid coordinates are rejected
+	resp, err := http.Post(server.URL+"/api/search/geo", "application/json",
+		strings.NewReader(`{"filters": {"gps": {"radius": {"lat": 137, "lng": 0, "km": 5}}}}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid latitude, got %d", resp.StatusCode)
+	}
+}
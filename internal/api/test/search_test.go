@@ -0,0 +1,52 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/yourname/mifind/internal/api"
+)
+
+// TestSearchCursor tests that next_cursor pages through the stored results
+// of the first request.
+func TestSearchCursor(t *testing.T) {
+	server := newTestServer(t)
+
+	search := func(body string) (api.SearchResponse, int) {
+		resp, err := http.Post(server.URL+"/api/search", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var page api.SearchResponse
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+				t.Fatalf("Decoding response failed: %v", err)
+			}
+		}
+		return page, resp.StatusCode
+	}
+
+	first, status := search(`{"query": "", "limit": 4}`)
+	if status != http.StatusOK || len(first.Entities) != 4 || first.NextCursor == "" {
+		t.Fatalf("Expected a page of 4 with a next cursor, got %d entities (status %d)", len(first.Entities), status)
+	}
+
+	second, status := search(`{"cursor": "` + first.NextCursor + `", "limit": 4}`)
+	if status != http.StatusOK || len(second.Entities) != 2 || second.NextCursor != "" {
+		t.Fatalf("Expected the last 2 entities without a next cursor, got %d (status %d)", len(second.Entities), status)
+	}
+	seen := map[string]bool{}
+	for _, entity := range append(first.Entities, second.Entities...) {
+		if seen[entity.ID] {
+			t.Errorf("Entity %s returned twice", entity.ID)
+		}
+		seen[entity.ID] = true
+	}
+
+	if _, status := search(`{"cursor": "bm9uZTow"}`); status != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown cursor, got %d", status)
+	}
+}
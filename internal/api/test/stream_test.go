@@ -0,0 +1,168 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/api"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// event is a received Server-Sent Event.
+type event struct {
+	name string
+	data string
+}
+
+// newTestServer serves the API over two mock instances.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:    "mock",
+		Factory: func() provider.Provider { return mock.NewMockProvider() },
+	}); err != nil {
+		t.Fatalf("Failed to register mock provider: %v", err)
+	}
+	manager := provider.NewManager(registry, &logger)
+	for _, instanceID := range []string{"docs", "media"} {
+		if err := manager.Initialize(ctx, "mock", map[string]any{"instance_id": instanceID, "entity_count": 3}); err != nil {
+			t.Fatalf("Failed to initialize %s: %v", instanceID, err)
+		}
+	}
+
+	typeRegistry := types.NewTypeRegistry()
+	types.RegisterCoreTypes(typeRegistry)
+
+	federator := search.NewFederator(manager, search.NewInMemoryRanker(search.DefaultRankingConfig()), &logger, 5*time.Second)
+	handlers := api.NewHandlers(manager, federator, search.NewRanker(), search.NewFilters(typeRegistry),
+		search.NewRelationships(manager, &logger), typeRegistry, &logger)
+
+	router := mux.NewRouter()
+	handlers.RegisterRoutes(router)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// readEvents reads Server-Sent Events until the stream ends.
+func readEvents(t *testing.T, resp *http.Response) []event {
+	t.Helper()
+	var events []event
+	var current event
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 1<<20), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			events = append(events, current)
+			current = event{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Reading stream failed: %v", err)
+	}
+	return events
+}
+
+// TestSearchStream tests that each provider's results are streamed before
+// the final ranked page.
+func TestSearchStream(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Post(server.URL+"/api/search/stream", "application/json", strings.NewReader(`{"query": "", "limit": 4}`))
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got %q", ct)
+	}
+
+	events := readEvents(t, resp)
+	if len(events) != 3 {
+		t.Fatalf("Expected 2 provider events and a result, got %d events", len(events))
+	}
+
+	providers := map[string]bool{}
+	for _, e := range events[:2] {
+		if e.name != api.EventProvider {
+			t.Fatalf("Expected provider event, got %q", e.name)
+		}
+		var data api.StreamProviderEvent
+		if err := json.Unmarshal([]byte(e.data), &data); err != nil {
+			t.Fatalf("Decoding provider event failed: %v", err)
+		}
+		if data.Status != search.ReportOK || len(data.Entities) != 3 {
+			t.Errorf("Expected 3 ok results from %s, got %d (%s)", data.Provider, len(data.Entities), data.Status)
+		}
+		providers[data.Provider] = true
+	}
+	if !providers["mock:docs"] || !providers["mock:media"] {
+		t.Errorf("Expected events from both instances, got %v", providers)
+	}
+
+	final := events[2]
+	if final.name != api.EventResult {
+		t.Fatalf("Expected final result event, got %q", final.name)
+	}
+	var page api.SearchResponse
+	if err := json.Unmarshal([]byte(final.data), &page); err != nil {
+		t.Fatalf("Decoding result event failed: %v", err)
+	}
+	if page.TotalCount != 6 || len(page.Entities) != 4 || len(page.Providers) != 2 {
+		t.Errorf("Expected a page of 4 of 6 results from 2 providers, got %d of %d from %d",
+			len(page.Entities), page.TotalCount, len(page.Providers))
+	}
+}
+
+// TestSearchStream_GET tests that GET requests take the query params.
+func TestSearchStream_GET(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/api/search/stream?query=&limit=2")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	events := readEvents(t, resp)
+	if len(events) == 0 || events[len(events)-1].name != api.EventResult {
+		t.Fatalf("Expected the stream to end with a result event, got %v", events)
+	}
+	var page api.SearchResponse
+	if err := json.Unmarshal([]byte(events[len(events)-1].data), &page); err != nil {
+		t.Fatalf("Decoding result event failed: %v", err)
+	}
+	if len(page.Entities) != 2 {
+		t.Errorf("Expected a page of 2, got %d", len(page.Entities))
+	}
+
+	resp, err = http.Get(server.URL + "/api/search/stream?limit=many")
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid limit, got %d", resp.StatusCode)
+	}
+}
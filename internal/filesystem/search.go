@@ -3,6 +3,10 @@ package filesystem
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/meilisearch/meilisearch-go"
@@ -50,7 +54,10 @@ func (s *Search) Search(ctx context.Context, req SearchRequest) (*SearchResult,
 
 	// Add filters if provided
 	if len(req.Filters) > 0 {
-		filterStr := s.buildFilterString(req.Filters)
+		filterStr, err := s.buildFilterString(req.Filters)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filters: %w", err)
+		}
 		if filterStr != "" {
 			searchReq.Filter = filterStr
 		}
@@ -87,50 +94,81 @@ func (s *Search) Search(ctx context.Context, req SearchRequest) (*SearchResult,
 	}, nil
 }
 
+// Keys of boolean groups in search filters: "$and" and "$or" hold lists of
+// filter maps, "$not" a single filter map.
+const (
+	filterAnd = "$and"
+	filterOr  = "$or"
+	filterNot = "$not"
+)
+
+// filterComparisons maps filter operations to Meilisearch comparison operators.
+var filterComparisons = map[string]string{
+	"eq":  "=",
+	"neq": "!=",
+	"gt":  ">",
+	"gte": ">=",
+	"min": ">=",
+	"lt":  "<",
+	"lte": "<=",
+	"max": "<=",
+}
+
 // buildFilterString converts filter map to Meilisearch filter syntax.
-func (s *Search) buildFilterString(filters map[string]any) string {
+// Entries are combined with AND; boolean groups nest further filter maps.
+func (s *Search) buildFilterString(filters map[string]any) (string, error) {
 	var parts []string
 
-	for key, value := range filters {
-		part := s.buildFilterPart(key, value)
+	for _, key := range slices.Sorted(maps.Keys(filters)) {
+		part, err := s.buildFilterPart(key, filters[key])
+		if err != nil {
+			return "", err
+		}
 		if part != "" {
 			parts = append(parts, part)
 		}
 	}
 
 	if len(parts) == 0 {
-		return ""
+		return "", nil
 	}
 
 	// Combine with AND
-	return joinWithAnd(parts)
+	return joinWithAnd(parts), nil
 }
 
-// buildFilterPart builds a single filter part.
-func (s *Search) buildFilterPart(key string, value any) string {
+// buildFilterPart builds a single filter part. Values are matched by
+// equality (any of, for lists), except for maps of operations such as
+// {"gte": 1000} or {"min": 1000, "max": 2000}.
+func (s *Search) buildFilterPart(key string, value any) (string, error) {
+	switch key {
+	case filterAnd, filterOr, filterNot:
+		return s.buildFilterGroup(key, value)
+	}
+
 	switch v := value.(type) {
-	case string:
-		return fmt.Sprintf("%s = \"%s\"", key, v)
-	case int, int64, float64:
-		return fmt.Sprintf("%s = %v", key, v)
-	case bool:
-		return fmt.Sprintf("%s = %t", key, v)
+	case string, int, int64, float64, bool:
+		return buildComparison(key, "=", v)
 	case []string:
 		if len(v) == 0 {
-			return ""
+			return "", nil
 		}
 		if len(v) == 1 {
-			return fmt.Sprintf("%s = \"%s\"", key, v[0])
+			return buildComparison(key, "=", v[0])
 		}
 		// Multiple values: use OR
 		var orParts []string
 		for _, item := range v {
-			orParts = append(orParts, fmt.Sprintf("%s = \"%s\"", key, item))
+			part, err := buildComparison(key, "=", item)
+			if err != nil {
+				return "", err
+			}
+			orParts = append(orParts, part)
 		}
-		return "(" + joinWithOr(orParts) + ")"
+		return "(" + joinWithOr(orParts) + ")", nil
 	case []any:
 		if len(v) == 0 {
-			return ""
+			return "", nil
 		}
 		if len(v) == 1 {
 			return s.buildFilterPart(key, v[0])
@@ -138,11 +176,156 @@ func (s *Search) buildFilterPart(key string, value any) string {
 		// Multiple values: use OR
 		var orParts []string
 		for _, item := range v {
-			orParts = append(orParts, s.buildFilterPart(key, item))
+			part, err := s.buildFilterPart(key, item)
+			if err != nil {
+				return "", err
+			}
+			orParts = append(orParts, part)
 		}
-		return "(" + joinWithOr(orParts) + ")"
+		return "(" + joinWithOr(orParts) + ")", nil
+	case map[string]any:
+		return s.buildFilterOperations(key, v)
 	default:
-		return ""
+		return "", fmt.Errorf("unsupported value for filter %q: %T", key, value)
+	}
+}
+
+// buildFilterGroup builds a boolean group of filter maps.
+func (s *Search) buildFilterGroup(key string, value any) (string, error) {
+	if key == filterNot {
+		filters, ok := value.(map[string]any)
+		if !ok || len(filters) == 0 {
+			return "", fmt.Errorf("%s expects a filter object", key)
+		}
+		part, err := s.buildFilterString(filters)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + part + ")", nil
+	}
+
+	items, ok := value.([]any)
+	if !ok || len(items) == 0 {
+		return "", fmt.Errorf("%s expects a list of filter objects", key)
+	}
+
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		filters, ok := item.(map[string]any)
+		if !ok || len(filters) == 0 {
+			return "", fmt.Errorf("%s expects a list of filter objects", key)
+		}
+		part, err := s.buildFilterString(filters)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "("+part+")")
+	}
+
+	if key == filterOr {
+		return "(" + joinWithOr(parts) + ")", nil
+	}
+	return joinWithAnd(parts), nil
+}
+
+// buildFilterOperations builds the operations of a filter map, combined
+// with AND.
+func (s *Search) buildFilterOperations(key string, operations map[string]any) (string, error) {
+	var parts []string
+
+	for _, op := range slices.Sorted(maps.Keys(operations)) {
+		value := operations[op]
+		values, isList := value.([]any)
+
+		var part string
+		var err error
+		switch {
+		case op == "in":
+			part, err = buildMembership(key, "IN", value)
+		case op == "neq" && isList:
+			part, err = buildMembership(key, "NOT IN", value)
+		case op == "eq" && isList:
+			// A list attribute containing all values
+			var allParts []string
+			for _, item := range values {
+				itemPart, err := buildComparison(key, "=", item)
+				if err != nil {
+					return "", err
+				}
+				allParts = append(allParts, itemPart)
+			}
+			part = joinWithAnd(allParts)
+		case op == "contains":
+			// Requires the containsFilter experimental feature of Meilisearch
+			part, err = buildComparison(key, "CONTAINS", value)
+		case filterComparisons[op] != "":
+			part, err = buildComparison(key, filterComparisons[op], value)
+		default:
+			err = fmt.Errorf("unsupported operation for filter %q: %s", key, op)
+		}
+		if err != nil {
+			return "", err
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return joinWithAnd(parts), nil
+}
+
+// buildComparison builds a "key operator value" filter.
+func buildComparison(key, operator string, value any) (string, error) {
+	formatted, err := formatFilterValue(value)
+	if err != nil {
+		return "", fmt.Errorf("filter %q: %w", key, err)
+	}
+	return fmt.Sprintf("%s %s %s", key, operator, formatted), nil
+}
+
+// buildMembership builds a "key IN [values]" filter.
+func buildMembership(key, operator string, value any) (string, error) {
+	var values []any
+	switch v := value.(type) {
+	case []any:
+		values = v
+	case []string:
+		for _, item := range v {
+			values = append(values, item)
+		}
+	default:
+		values = []any{v}
+	}
+	if len(values) == 0 {
+		return "", fmt.Errorf("filter %q: empty list", key)
+	}
+
+	formatted := make([]string, len(values))
+	for i, item := range values {
+		var err error
+		if formatted[i], err = formatFilterValue(item); err != nil {
+			return "", fmt.Errorf("filter %q: %w", key, err)
+		}
+	}
+	return fmt.Sprintf("%s %s [%s]", key, operator, strings.Join(formatted, ", ")), nil
+}
+
+// formatFilterValue formats a value as a Meilisearch filter literal.
+func formatFilterValue(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v) + `"`, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int, int64:
+		return fmt.Sprintf("%d", v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("unsupported value type %T", value)
 	}
 }
 
@@ -0,0 +1,493 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/yourname/mifind/internal/types"
+)
+
+// drainTimeout bounds how long a reconfigured or removed instance is given to
+// finish the calls it is serving before its provider is shut down.
+const drainTimeout = 10 * time.Second
+
+// ResetHandler drops what was collected for an instance, such as its stored
+// entities or sync state.
+type ResetHandler func(instanceKey string) error
+
+// ReloadResult reports the instance keys touched by Manager.Reload.
+type ReloadResult struct {
+	Added     []string `json:"added"`
+	Updated   []string `json:"updated"`
+	Removed   []string `json:"removed"`
+	Unchanged []string `json:"unchanged"`
+}
+
+// OnReset registers a handler that is called when an instance is removed or
+// its configuration changes, since what was collected from it may no longer
+// apply. Handlers must be registered before instances are reconfigured.
+func (m *Manager) OnReset(handler ResetHandler) {
+	m.resetHandlers = append(m.resetHandlers, handler)
+}
+
+// reset calls the reset handlers for an instance. Failures are logged, the
+// reconfiguration itself has already happened.
+func (m *Manager) reset(key string) {
+	for _, handler := range m.resetHandlers {
+		if err := handler(key); err != nil {
+			m.logger.Warn().
+				Str("provider", key).
+				Err(err).
+				Msg("Failed to reset provider data")
+		}
+	}
+}
+
+// Instances returns the configuration of every instance added through
+// InitializeInstances, AddInstance, UpdateInstance or Reload, ordered by key.
+// Instances that are pending re-initialization are included.
+func (m *Manager) Instances() []InstanceConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	instances := make([]InstanceConfig, 0, len(m.configs))
+	for _, inst := range m.configs {
+		instances = append(instances, inst)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Key() < instances[j].Key()
+	})
+	return instances
+}
+
+// GetInstanceConfig returns the configuration of a single instance.
+func (m *Manager) GetInstanceConfig(key string) (InstanceConfig, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	inst, exists := m.configs[key]
+	return inst, exists
+}
+
+// setInstanceConfig records the configuration an instance was added with.
+func (m *Manager) setInstanceConfig(inst InstanceConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.configs[inst.Key()] = inst
+}
+
+// AddInstance validates and starts a new instance at runtime.
+// Returns ErrInvalidConfig or ErrInstanceExists without changing anything.
+// If the provider fails to initialize, the instance is still added as pending
+// (and retried like at startup) and an ErrInitFailed error is returned.
+func (m *Manager) AddInstance(ctx context.Context, inst InstanceConfig) error {
+	if err := validateInstance(m.registry, inst); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	m.adminMu.Lock()
+	defer m.adminMu.Unlock()
+
+	key := inst.Key()
+	if _, exists := m.GetInstanceConfig(key); exists || m.known(key) {
+		return fmt.Errorf("%w: %q", ErrInstanceExists, key)
+	}
+
+	return m.add(ctx, inst)
+}
+
+// UpdateInstance replaces the configuration of an existing instance at runtime.
+// The new provider is started first and swapped in atomically, so searches
+// never see the instance missing; the old provider is drained and shut down
+// afterwards. If the new configuration fails to initialize, a running instance
+// keeps its old configuration and an ErrInitFailed error is returned.
+func (m *Manager) UpdateInstance(ctx context.Context, inst InstanceConfig) error {
+	if err := validateInstance(m.registry, inst); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	m.adminMu.Lock()
+	defer m.adminMu.Unlock()
+
+	key := inst.Key()
+	if _, exists := m.GetInstanceConfig(key); !exists && !m.known(key) {
+		return fmt.Errorf("%w: %q", ErrUnknownInstance, key)
+	}
+
+	return m.replace(ctx, inst)
+}
+
+// RemoveInstance drains and shuts down an instance and forgets its configuration.
+func (m *Manager) RemoveInstance(ctx context.Context, key string) error {
+	m.adminMu.Lock()
+	defer m.adminMu.Unlock()
+
+	return m.remove(ctx, key)
+}
+
+// Reload reconciles the running instances with the given list: instances that
+// are no longer listed are removed, instances whose config changed are updated
+// and new instances are added. The whole list is validated first, and nothing
+// changes if it is invalid. Instances that fail to start don't stop the
+// reload; their errors are returned together.
+func (m *Manager) Reload(ctx context.Context, instances []InstanceConfig) (ReloadResult, error) {
+	var result ReloadResult
+	if err := ValidateInstances(m.registry, instances); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	m.adminMu.Lock()
+	defer m.adminMu.Unlock()
+
+	wanted := make(map[string]bool, len(instances))
+	for _, inst := range instances {
+		wanted[inst.Key()] = true
+	}
+
+	var errs []error
+	for _, current := range m.Instances() {
+		key := current.Key()
+		if wanted[key] {
+			continue
+		}
+		if err := m.remove(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("remove %q: %w", key, err))
+		}
+		result.Removed = append(result.Removed, key)
+	}
+
+	for _, inst := range instances {
+		key := inst.Key()
+		current, exists := m.GetInstanceConfig(key)
+		switch {
+		case !exists:
+			if err := m.add(ctx, inst); err != nil {
+				errs = append(errs, fmt.Errorf("add %q: %w", key, err))
+			}
+			result.Added = append(result.Added, key)
+		case !sameSettings(current, inst):
+			if err := m.replace(ctx, inst); err != nil {
+				errs = append(errs, fmt.Errorf("update %q: %w", key, err))
+			}
+			result.Updated = append(result.Updated, key)
+		default:
+			result.Unchanged = append(result.Unchanged, key)
+		}
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// known reports whether an instance is running or pending under the given key.
+func (m *Manager) known(key string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, running := m.providers[key]
+	_, pending := m.pending[key]
+	return running || pending
+}
+
+// add records and initializes a new instance. An instance that fails to
+// initialize stays recorded, since the manager keeps retrying it.
+// The caller must hold adminMu.
+func (m *Manager) add(ctx context.Context, inst InstanceConfig) error {
+	m.setInstanceConfig(inst)
+	err := m.Initialize(ctx, inst.Type, inst.Settings())
+	if err != nil && !errors.Is(err, ErrInitFailed) {
+		m.mu.Lock()
+		delete(m.configs, inst.Key())
+		m.mu.Unlock()
+	}
+	return err
+}
+
+// replace starts inst and swaps it in for the instance with the same key.
+// The caller must hold adminMu.
+func (m *Manager) replace(ctx context.Context, inst InstanceConfig) error {
+	key := inst.Key()
+	config, policy, err := m.prepare(key, inst.Type, inst.Settings())
+	if err != nil {
+		return err
+	}
+
+	// What was collected under another configuration may not apply anymore
+	previous, _ := m.GetInstanceConfig(key)
+	changed := !sameSettings(previous, inst)
+
+	next, err := m.start(ctx, inst.Type, inst.InstanceID, config, policy)
+	if err != nil {
+		// A pending instance has nothing to keep, so it takes the new config
+		if errors.Is(err, ErrInitFailed) && !m.isRunning(key) {
+			m.setInstanceConfig(inst)
+			m.addPending(key, inst.Type, inst.InstanceID, config, err)
+			if changed {
+				m.reset(key)
+			}
+		}
+		return err
+	}
+
+	m.mu.Lock()
+	old := m.providers[key]
+	if old != nil && !changed {
+		// Keep the sync history, the instance still represents the same source
+		next.Status.Sync = old.Status.Sync
+		next.Status.EntityCount = old.Status.EntityCount
+		next.lastDiscovery = old.lastDiscovery
+	}
+	m.providers[key] = next
+	delete(m.pending, key)
+	m.configs[key] = inst
+	m.mu.Unlock()
+
+	m.logger.Info().
+		Str("provider", inst.Type).
+		Str("instance", inst.InstanceID).
+		Msg("Provider reconfigured")
+
+	if old != nil {
+		drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), drainTimeout)
+		defer cancel()
+		// The new instance is already serving, a failed shutdown of the old one
+		// is logged by retire and not reported to the caller
+		_ = m.retire(drainCtx, key, old)
+	}
+
+	// Reset once the old instance is gone, so that it can't refill what was
+	// dropped
+	if changed {
+		m.reset(key)
+	}
+	return nil
+}
+
+// remove shuts down an instance and forgets its configuration and the data
+// collected from it.
+// The caller must hold adminMu.
+func (m *Manager) remove(ctx context.Context, key string) error {
+	_, configured := m.GetInstanceConfig(key)
+	if !configured && !m.known(key) {
+		return fmt.Errorf("%w: %q", ErrUnknownInstance, key)
+	}
+
+	m.mu.Lock()
+	delete(m.configs, key)
+	m.mu.Unlock()
+	defer m.reset(key)
+
+	if !m.known(key) {
+		return nil
+	}
+
+	drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), drainTimeout)
+	defer cancel()
+	return m.Shutdown(drainCtx, key)
+}
+
+// isRunning reports whether an instance is initialized, connected or not.
+func (m *Manager) isRunning(key string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, exists := m.providers[key]
+	return exists
+}
+
+// sameSettings reports whether two instance configs are equivalent.
+// Configs are compared in their JSON form, so numbers decoded from YAML and
+// JSON (int vs float64) compare equal.
+func sameSettings(a, b InstanceConfig) bool {
+	if a.Key() != b.Key() {
+		return false
+	}
+	ja, errA := json.Marshal(a.Settings())
+	jb, errB := json.Marshal(b.Settings())
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+// inflightProvider counts the calls in flight on a provider so that it can
+// be drained before it is shut down. The manager wraps every instance in it,
+// outside the middleware chain.
+type inflightProvider struct {
+	wrapper
+
+	mu    sync.Mutex
+	calls int
+	idle  chan struct{}
+}
+
+func newInflightProvider(p Provider) *inflightProvider {
+	return &inflightProvider{wrapper: wrapper{p}}
+}
+
+func (p *inflightProvider) begin() {
+	p.mu.Lock()
+	p.calls++
+	p.mu.Unlock()
+}
+
+func (p *inflightProvider) end() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls--
+	if p.calls == 0 && p.idle != nil {
+		close(p.idle)
+		p.idle = nil
+	}
+}
+
+// active returns the number of calls in flight.
+func (p *inflightProvider) active() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.calls
+}
+
+// drain waits until no calls are in flight or ctx is done.
+func (p *inflightProvider) drain(ctx context.Context) error {
+	p.mu.Lock()
+	if p.calls == 0 {
+		p.mu.Unlock()
+		return nil
+	}
+	if p.idle == nil {
+		p.idle = make(chan struct{})
+	}
+	idle := p.idle
+	p.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *inflightProvider) Search(ctx context.Context, query SearchQuery) ([]types.Entity, error) {
+	p.begin()
+	defer p.end()
+	return p.Provider.Search(ctx, query)
+}
+
+func (p *inflightProvider) Hydrate(ctx context.Context, id string) (types.Entity, error) {
+	p.begin()
+	defer p.end()
+	return p.Provider.Hydrate(ctx, id)
+}
+
+func (p *inflightProvider) GetRelated(ctx context.Context, id string, relType string) ([]types.Entity, error) {
+	p.begin()
+	defer p.end()
+	return p.Provider.GetRelated(ctx, id, relType)
+}
+
+func (p *inflightProvider) Discover(ctx context.Context) ([]types.Entity, error) {
+	p.begin()
+	defer p.end()
+	return p.Provider.Discover(ctx)
+}
+
+func (p *inflightProvider) DiscoverSince(ctx context.Context, since time.Time) ([]types.Entity, error) {
+	p.begin()
+	defer p.end()
+	return p.Provider.DiscoverSince(ctx, since)
+}
+
+// health probes the provider's backend as a call in flight, so that the
+// instance isn't shut down under the probe. The provider must implement
+// HealthChecker.
+func (p *inflightProvider) health(ctx context.Context) error {
+	p.begin()
+	defer p.end()
+	return Unwrap(p.Provider).(HealthChecker).Health(ctx)
+}
+
+// InstanceStore persists the provider instance list after runtime changes.
+type InstanceStore interface {
+	// Load returns the persisted instance list. ok is false if nothing has
+	// been persisted yet.
+	Load() (instances []InstanceConfig, ok bool, err error)
+
+	// Save persists the instance list.
+	Save(instances []InstanceConfig) error
+}
+
+// FileInstanceStore is an InstanceStore backed by a JSON file on local disk.
+type FileInstanceStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileInstanceStore creates an instance store that reads and writes the given file.
+// The file and its parent directory are created on first save.
+func NewFileInstanceStore(path string) *FileInstanceStore {
+	return &FileInstanceStore{path: path}
+}
+
+// Path returns the file the store writes to.
+func (s *FileInstanceStore) Path() string {
+	return s.path
+}
+
+// Load reads the instance file. A missing file is not an error.
+func (s *FileInstanceStore) Load() ([]InstanceConfig, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read provider instances: %w", err)
+	}
+
+	var file struct {
+		Providers []InstanceConfig `json:"providers"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, false, fmt.Errorf("failed to parse provider instances %s: %w", s.path, err)
+	}
+	return file.Providers, true, nil
+}
+
+// Save rewrites the instance file atomically.
+func (s *FileInstanceStore) Save(instances []InstanceConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file := struct {
+		Providers []InstanceConfig `json:"providers"`
+	}{Providers: instances}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode provider instances: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create provider instances directory: %w", err)
+	}
+
+	// Write to a temp file and rename so a crash never leaves a truncated file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write provider instances: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace provider instances: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package provider
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Backoff bounds for instances that failed to initialize or sync.
+const (
+	reinitBaseDelay = 30 * time.Second
+	reinitMaxDelay  = 10 * time.Minute
+)
+
+// HealthConfig configures active health checking of provider instances.
+type HealthConfig struct {
+	// Enabled turns on periodic health probes and re-initialization of failed instances
+	Enabled bool `mapstructure:"enabled"`
+
+	// Interval is how often every instance is probed
+	Interval time.Duration `mapstructure:"interval"`
+
+	// Timeout bounds a single probe
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// FailureThreshold is the number of consecutive failed probes after which
+	// the instance is taken out of federation
+	FailureThreshold int `mapstructure:"failure_threshold"`
+}
+
+// DefaultHealthConfig returns the default health check configuration.
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		Enabled:          true,
+		Interval:         30 * time.Second,
+		Timeout:          5 * time.Second,
+		FailureThreshold: 3,
+	}
+}
+
+// HealthMonitor periodically probes provider instances that implement
+// HealthChecker and acts as a circuit breaker: an instance that fails
+// FailureThreshold probes in a row is marked as not connected, so the
+// federator stops sending it searches, and is brought back on the first
+// successful probe. Instances that failed to initialize are retried with
+// exponential backoff on the same schedule.
+type HealthMonitor struct {
+	manager *Manager
+	config  HealthConfig
+	logger  *zerolog.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewHealthMonitor creates a new health monitor for the given manager.
+func NewHealthMonitor(manager *Manager, config HealthConfig, logger *zerolog.Logger) *HealthMonitor {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 1
+	}
+	return &HealthMonitor{
+		manager: manager,
+		config:  config,
+		logger:  logger,
+	}
+}
+
+// Start starts the background prober.
+func (h *HealthMonitor) Start(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	h.cancel = cancel
+
+	h.wg.Add(1)
+	go h.loop(ctx)
+
+	h.logger.Info().
+		Dur("interval", h.config.Interval).
+		Int("failure_threshold", h.config.FailureThreshold).
+		Msg("Provider health monitor started")
+}
+
+// Stop stops the prober and waits for in-flight probes to finish.
+func (h *HealthMonitor) Stop() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+	h.wg.Wait()
+}
+
+// loop runs a check on every tick.
+func (h *HealthMonitor) loop(ctx context.Context) {
+	defer h.wg.Done()
+
+	interval := h.config.Interval
+	if interval <= 0 {
+		interval = DefaultHealthConfig().Interval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.CheckNow(ctx)
+		}
+	}
+}
+
+// CheckNow retries pending instances and probes all initialized instances once.
+func (h *HealthMonitor) CheckNow(ctx context.Context) {
+	for _, key := range h.manager.RetryPending(ctx) {
+		h.logger.Info().
+			Str("provider", key).
+			Msg("Provider re-initialized after failed startup")
+	}
+
+	var wg sync.WaitGroup
+	for _, key := range h.manager.List() {
+		target, generation, ok := h.manager.healthTarget(key)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(key string, target *inflightProvider, generation uint64) {
+			defer wg.Done()
+			h.probe(ctx, key, target, generation)
+		}(key, target, generation)
+	}
+	wg.Wait()
+}
+
+// probe runs a single health check and logs circuit transitions. The result
+// is dropped if the instance is replaced during the probe.
+func (h *HealthMonitor) probe(ctx context.Context, key string, target *inflightProvider, generation uint64) {
+	probeCtx := ctx
+	if h.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		probeCtx, cancel = context.WithTimeout(ctx, h.config.Timeout)
+		defer cancel()
+	}
+
+	err := target.health(probeCtx)
+
+	// Don't count probes interrupted by shutdown as failures
+	if ctx.Err() != nil {
+		return
+	}
+
+	opened, closed := h.manager.recordHealth(key, generation, err, h.config.FailureThreshold)
+	switch {
+	case opened:
+		h.logger.Warn().
+			Str("provider", key).
+			Err(err).
+			Msg("Provider unhealthy, removed from federation")
+	case closed:
+		h.logger.Info().
+			Str("provider", key).
+			Msg("Provider healthy again, restored to federation")
+	case err != nil:
+		h.logger.Debug().
+			Str("provider", key).
+			Err(err).
+			Msg("Provider health check failed")
+	}
+}
+
+// reinitBackoff returns the delay before the next initialization attempt or
+// sync run after the given number of consecutive failures.
+func reinitBackoff(failures int) time.Duration {
+	delay := reinitBaseDelay
+	for i := 1; i < failures && delay < reinitMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > reinitMaxDelay {
+		delay = reinitMaxDelay
+	}
+	return delay
+}
@@ -11,6 +11,10 @@ import (
 
 // Entity ID format: "providerType:instanceID:entityID"
 // Example: "filesystem:myfs:abc123"
+//
+// The provider type and instance ID never contain the separator; the entity
+// part is opaque to the core and may itself contain the separator
+// (e.g. "mock:default:entity:1").
 
 const (
 	// EntityIDSeparator is the separator used in entity IDs
@@ -30,7 +34,7 @@ func NewEntityID(providerType, instanceID, entityID string) EntityID {
 
 // ParseEntityID parses an EntityID from a string.
 func ParseEntityID(s string) (EntityID, error) {
-	parts := strings.Split(s, EntityIDSeparator)
+	parts := strings.SplitN(s, EntityIDSeparator, NumIDParts)
 	if len(parts) != NumIDParts {
 		return EntityID(""), fmt.Errorf("invalid entity ID format: expected %d parts separated by %q, got %d", NumIDParts, EntityIDSeparator, len(parts))
 	}
@@ -53,7 +57,7 @@ func (e EntityID) String() string {
 
 // ProviderType returns the provider type part of the ID.
 func (e EntityID) ProviderType() string {
-	parts := strings.Split(string(e), EntityIDSeparator)
+	parts := strings.SplitN(string(e), EntityIDSeparator, NumIDParts)
 	if len(parts) == NumIDParts {
 		return parts[0]
 	}
@@ -62,7 +66,7 @@ func (e EntityID) ProviderType() string {
 
 // InstanceID returns the instance ID part of the ID.
 func (e EntityID) InstanceID() string {
-	parts := strings.Split(string(e), EntityIDSeparator)
+	parts := strings.SplitN(string(e), EntityIDSeparator, NumIDParts)
 	if len(parts) == NumIDParts {
 		return parts[1]
 	}
@@ -71,7 +75,7 @@ func (e EntityID) InstanceID() string {
 
 // ResourceID returns the resource/entity ID part of the ID.
 func (e EntityID) ResourceID() string {
-	parts := strings.Split(string(e), EntityIDSeparator)
+	parts := strings.SplitN(string(e), EntityIDSeparator, NumIDParts)
 	if len(parts) == NumIDParts {
 		return parts[2]
 	}
@@ -80,22 +84,48 @@ func (e EntityID) ResourceID() string {
 
 // Parts returns all three parts of the ID (providerType, instanceID, resourceID).
 func (e EntityID) Parts() (string, string, string) {
-	parts := strings.Split(string(e), EntityIDSeparator)
+	parts := strings.SplitN(string(e), EntityIDSeparator, NumIDParts)
 	if len(parts) == NumIDParts {
 		return parts[0], parts[1], parts[2]
 	}
 	return "", "", ""
 }
 
+// InstanceKey returns the "providerType:instanceID" key of the provider
+// instance that owns this entity, or an empty string if the ID is malformed.
+func (e EntityID) InstanceKey() string {
+	providerType, instanceID, _ := e.Parts()
+	if providerType == "" || instanceID == "" {
+		return ""
+	}
+	return InstanceKey(providerType, instanceID)
+}
+
 // IsValid checks if the EntityID has a valid format.
 func (e EntityID) IsValid() bool {
-	parts := strings.Split(string(e), EntityIDSeparator)
+	parts := strings.SplitN(string(e), EntityIDSeparator, NumIDParts)
 	return len(parts) == NumIDParts &&
 		parts[0] != "" &&
 		parts[1] != "" &&
 		parts[2] != ""
 }
 
+// InstanceKey builds the key that identifies a provider instance, using the
+// same "providerType:instanceID" prefix that EntityID encodes.
+// Example: "filesystem:myfs"
+func InstanceKey(providerType, instanceID string) string {
+	return providerType + EntityIDSeparator + instanceID
+}
+
+// ParseInstanceKey splits an instance key into its provider type and instance ID.
+func ParseInstanceKey(key string) (string, string, error) {
+	parts := strings.Split(key, EntityIDSeparator)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid instance key %q: expected \"providerType%sinstanceID\"", key, EntityIDSeparator)
+	}
+	return parts[0], parts[1], nil
+}
+
 // BuildEntityID creates an EntityID from components.
 // This is a convenience function equivalent to NewEntityID.
 func BuildEntityID(providerType, instanceID, entityID string) EntityID {
@@ -120,6 +150,42 @@ type ThumbnailProvider interface {
 	GetThumbnail(ctx context.Context, id string) ([]byte, string, error)
 }
 
+// HealthChecker is an optional interface that providers can implement to let
+// the HealthMonitor probe their backend periodically.
+type HealthChecker interface {
+	// Health returns nil if the provider's backend is reachable and usable.
+	Health(ctx context.Context) error
+}
+
+// TypeProvider is an optional interface that providers can implement to
+// declare the entity types they emit. Federated searches for a type are only
+// sent to providers that emit it or one of its descendants.
+type TypeProvider interface {
+	// EntityTypes returns the type names of the entities the provider returns.
+	// An empty slice means the provider can return entities of any type.
+	EntityTypes() []string
+}
+
+// TypeDefinitionsProvider is an optional interface that providers can implement
+// to contribute their own entity types to the TypeRegistry. The definitions are
+// registered when an instance is initialized; definitions that conflict with
+// types registered by the core or another provider fail the initialization.
+type TypeDefinitionsProvider interface {
+	// TypeDefinitions returns the definitions of the provider's own types.
+	// Parents must be registered types or other returned definitions.
+	TypeDefinitions() []types.TypeDefinition
+}
+
+// FilterExpressionProvider is an optional interface that providers can implement
+// to evaluate boolean filter groups themselves. Such providers receive the groups
+// in SearchQuery.Filters under the "$and", "$or" and "$not" keys, holding filter
+// maps in the explicit operation format ({"size": {"gte": 1000}}). Groups are only
+// sent when the provider's FilterCapabilities support all of their conditions.
+type FilterExpressionProvider interface {
+	// SupportsFilterExpressions returns true if the provider evaluates filter groups.
+	SupportsFilterExpressions() bool
+}
+
 // FilterCapability describes how a provider supports filtering on a specific attribute.
 // This is runtime-discoverable and provider-specific, allowing each provider to declare
 // which attributes can be filtered on and how.
@@ -145,6 +211,10 @@ type FilterCapability struct {
 	// SupportsContains indicates if substring matching is supported (e.g., "title~vacation")
 	SupportsContains bool
 
+	// SupportsGeo indicates if radius and bounding box filtering is supported
+	// (e.g., "gps within 10km of 48.14,11.58")
+	SupportsGeo bool
+
 	// Min is the minimum value for range filters (nil if no minimum)
 	Min *float64
 
@@ -271,6 +341,41 @@ type SearchQuery struct {
 	Offset int
 }
 
+// Paginate returns the page of entities the query's offset and limit select,
+// for providers that can't page their results themselves.
+func (q SearchQuery) Paginate(entities []types.Entity) []types.Entity {
+	if q.Offset >= len(entities) {
+		return []types.Entity{}
+	}
+	entities = entities[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(entities) {
+		entities = entities[:q.Limit]
+	}
+	return entities
+}
+
+// StringList converts a filter value to strings: a list becomes its items and
+// a single value a list of one. Items that aren't strings are formatted with
+// fmt.Sprint, so 2024 and "2024" mean the same. A nil value returns nil.
+func StringList(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []any:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			list = append(list, fmt.Sprint(item))
+		}
+		return list
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
 // SearchResult contains search results with metadata.
 type SearchResult struct {
 	// Entities is the list of matching entities
@@ -286,25 +391,94 @@ type SearchResult struct {
 	HasMore bool
 }
 
-// ProviderStatus represents the current status of a provider.
+// ProviderStatus represents the current status of a provider instance.
 type ProviderStatus struct {
-	// Name is the provider name
-	Name string
+	// Name is the instance key ("providerType:instanceID")
+	Name string `json:"name"`
+
+	// ProviderType is the registered provider type (e.g., "filesystem")
+	ProviderType string `json:"provider_type"`
+
+	// InstanceID is the configured instance ID (e.g., "myfs")
+	InstanceID string `json:"instance_id"`
 
 	// Connected indicates whether the provider is connected
-	Connected bool
+	Connected bool `json:"connected"`
 
 	// LastDiscovery is the timestamp of the last successful discovery
-	LastDiscovery time.Time
+	LastDiscovery time.Time `json:"last_discovery"`
 
 	// LastError is the last error encountered (if any)
-	LastError string
+	LastError string `json:"last_error"`
 
 	// EntityCount is the number of entities known from this provider
-	EntityCount int
+	EntityCount int `json:"entity_count"`
 
 	// SupportsIncremental indicates if provider supports incremental updates
-	SupportsIncremental bool
+	SupportsIncremental bool `json:"supports_incremental"`
+
+	// Sync reports background sync progress for this instance
+	Sync SyncStatus `json:"sync"`
+
+	// Health reports active health checking for this instance
+	Health HealthStatus `json:"health"`
+}
+
+// Health states reported in HealthStatus.State.
+const (
+	// HealthStateUnknown means the instance hasn't been probed yet or has no health check
+	HealthStateUnknown = "unknown"
+
+	// HealthStateHealthy means the last probe succeeded
+	HealthStateHealthy = "healthy"
+
+	// HealthStateUnhealthy means the circuit is open and the instance is out of federation
+	HealthStateUnhealthy = "unhealthy"
+
+	// HealthStateInitFailed means initialization failed and is retried in the background
+	HealthStateInitFailed = "init_failed"
+)
+
+// HealthStatus reports the health check state of a provider instance.
+type HealthStatus struct {
+	// State is one of the HealthState constants
+	State string `json:"state"`
+
+	// LastCheck is the time of the last probe or initialization attempt
+	LastCheck time.Time `json:"last_check"`
+
+	// ConsecutiveFailures is the number of failed probes or initialization attempts in a row
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// LastError is the error from the last failed probe (cleared on success)
+	LastError string `json:"last_error,omitempty"`
+}
+
+// SyncStatus reports the background sync state of a provider instance.
+type SyncStatus struct {
+	// Running indicates whether a sync is currently in progress
+	Running bool `json:"running"`
+
+	// Mode is the mode of the current or last sync run ("full" or "incremental")
+	Mode string `json:"mode,omitempty"`
+
+	// Watermark is the high-water mark used for the next incremental sync
+	Watermark time.Time `json:"watermark"`
+
+	// LastFullSync is the completion time of the last successful full sync
+	LastFullSync time.Time `json:"last_full_sync"`
+
+	// LastIncrementalSync is the completion time of the last successful incremental sync
+	LastIncrementalSync time.Time `json:"last_incremental_sync"`
+
+	// LastRunEntities is the number of entities returned by the last successful run
+	LastRunEntities int `json:"last_run_entities"`
+
+	// LastError is the error from the last failed run (cleared on success)
+	LastError string `json:"last_error,omitempty"`
+
+	// ConsecutiveFailures counts failed runs since the last success
+	ConsecutiveFailures int `json:"consecutive_failures"`
 }
 
 // ProviderFactory creates new provider instances.
@@ -339,6 +513,52 @@ type ConfigField struct {
 
 	// Default is the default value (optional)
 	Default any
+
+	// Secret marks credentials that must not be echoed back by the admin API
+	Secret bool
+}
+
+// accepts checks whether a config value matches the field type.
+// Numbers decoded from JSON or YAML may arrive as float64, and lists as []any.
+func (f ConfigField) accepts(value any) bool {
+	switch f.Type {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "bool":
+		_, ok := value.(bool)
+		return ok
+	case "int":
+		switch v := value.(type) {
+		case int, int32, int64:
+			return true
+		case float64:
+			return v == float64(int64(v))
+		}
+		return false
+	case "float":
+		_, err := floatValue(value)
+		return err == nil
+	case "duration":
+		_, err := durationValue(value)
+		return err == nil
+	case "[]string":
+		switch v := value.(type) {
+		case []string:
+			return true
+		case []any:
+			for _, item := range v {
+				if _, ok := item.(string); !ok {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	default:
+		// Unknown types are not checked
+		return true
+	}
 }
 
 // ProviderOption is a functional option for configuring provider behavior.
@@ -361,6 +581,10 @@ type ProviderConfig struct {
 	// CacheTTL is the cache TTL for this provider
 	CacheTTL time.Duration
 
+	// SoftDeadline is how long federated searches wait for this provider
+	// (0 = the federator's default)
+	SoftDeadline time.Duration
+
 	// Custom contains provider-specific custom configuration
 	Custom map[string]any
 }
@@ -576,6 +800,25 @@ var (
 	// ErrNotFound is returned when an entity is not found.
 	ErrNotFound = &ProviderError{Type: ErrorTypeNotFound, Message: "entity not found"}
 
+	// ErrUnknownInstance is returned when an entity ID names a provider instance
+	// ("providerType:instanceID") that is not managed.
+	ErrUnknownInstance = &ProviderError{Type: ErrorTypeNotFound, Message: "provider instance not found"}
+
+	// ErrInstanceUnavailable is returned when an entity ID names a provider
+	// instance that is configured but not connected, because it failed to
+	// initialize or its circuit is open.
+	ErrInstanceUnavailable = &ProviderError{Type: ErrorTypeTemporary, Message: "provider instance unavailable"}
+
+	// ErrInstanceExists is returned when adding a provider instance whose key is already in use.
+	ErrInstanceExists = &ProviderError{Type: ErrorTypeConfig, Message: "provider instance already exists"}
+
+	// ErrInitFailed is returned when a provider fails to initialize, usually
+	// because its backend is unreachable.
+	ErrInitFailed = &ProviderError{Type: ErrorTypeUnknown, Message: "provider initialization failed"}
+
+	// ErrInvalidConfig is returned when provider instance configuration fails validation.
+	ErrInvalidConfig = &ProviderError{Type: ErrorTypeConfig, Message: "invalid provider configuration"}
+
 	// ErrIncrementalNotSupported is returned when DiscoverSince is called on a provider
 	// that doesn't support incremental updates.
 	ErrIncrementalNotSupported = &ProviderError{Type: ErrorTypeNotSupported, Message: "incremental discovery not supported"}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// InstanceConfig is one entry of the "providers" config list.
+type InstanceConfig struct {
+	// Type is the registered provider type (e.g., "immich")
+	Type string `mapstructure:"type" json:"type"`
+
+	// InstanceID identifies the instance among instances of the same type
+	InstanceID string `mapstructure:"instance_id" json:"instance_id"`
+
+	// Config holds the provider-specific settings, validated against the
+	// provider's ConfigSchema, plus the optional middleware settings
+	Config map[string]any `mapstructure:"config" json:"config"`
+}
+
+// Key returns the instance key ("providerType:instanceID").
+func (c InstanceConfig) Key() string {
+	return InstanceKey(c.Type, c.InstanceID)
+}
+
+// Settings returns the config map passed to Manager.Initialize, including instance_id.
+func (c InstanceConfig) Settings() map[string]any {
+	settings := make(map[string]any, len(c.Config)+1)
+	for key, value := range c.Config {
+		settings[key] = value
+	}
+	settings["instance_id"] = c.InstanceID
+	return settings
+}
+
+// RedactedValue replaces the values of secret config fields in admin API responses.
+const RedactedValue = "********"
+
+// Redact returns a copy of inst with the values of secret schema fields
+// replaced by RedactedValue.
+func (r *Registry) Redact(inst InstanceConfig) InstanceConfig {
+	meta := r.Get(inst.Type)
+	if meta == nil {
+		return inst
+	}
+
+	config := make(map[string]any, len(inst.Config))
+	for key, value := range inst.Config {
+		if meta.ConfigSchema[key].Secret {
+			value = RedactedValue
+		}
+		config[key] = value
+	}
+	inst.Config = config
+	return inst
+}
+
+// KeepSecrets returns a copy of inst in which secret fields set to
+// RedactedValue take their value from current, so that a config read from
+// the admin API can be sent back with only the non-secret fields changed.
+func (r *Registry) KeepSecrets(inst, current InstanceConfig) InstanceConfig {
+	meta := r.Get(inst.Type)
+	if meta == nil {
+		return inst
+	}
+
+	config := make(map[string]any, len(inst.Config))
+	for key, value := range inst.Config {
+		if meta.ConfigSchema[key].Secret && value == RedactedValue {
+			if currentValue, ok := current.Config[key]; ok {
+				value = currentValue
+			}
+		}
+		config[key] = value
+	}
+	inst.Config = config
+	return inst
+}
+
+// ValidateInstances checks that every entry names a registered provider type,
+// has a unique instance ID and a config that is valid against the provider's
+// schema once defaults are applied. All problems are reported together.
+func ValidateInstances(registry *Registry, instances []InstanceConfig) error {
+	var errs []error
+	seen := make(map[string]bool)
+
+	for i, inst := range instances {
+		if err := validateInstance(registry, inst); err != nil {
+			errs = append(errs, fmt.Errorf("providers[%d]: %w", i, err))
+			continue
+		}
+		if seen[inst.Key()] {
+			errs = append(errs, fmt.Errorf("providers[%d]: duplicate instance %q", i, inst.Key()))
+		}
+		seen[inst.Key()] = true
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateInstance validates a single entry.
+func validateInstance(registry *Registry, inst InstanceConfig) error {
+	if inst.Type == "" {
+		return fmt.Errorf("type is required")
+	}
+	if !registry.Exists(inst.Type) {
+		return fmt.Errorf("unknown provider type %q (registered: %v)", inst.Type, registry.List())
+	}
+	if inst.InstanceID == "" {
+		return fmt.Errorf("%s: instance_id is required", inst.Type)
+	}
+
+	settings, err := registry.ApplyDefaults(inst.Type, inst.Settings())
+	if err != nil {
+		return err
+	}
+	if err := registry.ValidateConfig(inst.Type, settings); err != nil {
+		return fmt.Errorf("%s: %w", inst.Key(), err)
+	}
+	if _, err := ProviderConfigFromMap(settings); err != nil {
+		return fmt.Errorf("%s: %w", inst.Key(), err)
+	}
+	return nil
+}
+
+// InitializeInstances validates all entries and initializes them.
+// Invalid configuration aborts before any instance is started. Instances that
+// fail to start are logged and kept pending (see RetryPending); they don't
+// make InitializeInstances fail.
+func (m *Manager) InitializeInstances(ctx context.Context, instances []InstanceConfig) error {
+	if err := ValidateInstances(m.registry, instances); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+
+	m.adminMu.Lock()
+	defer m.adminMu.Unlock()
+
+	for _, inst := range instances {
+		m.setInstanceConfig(inst)
+		if err := m.Initialize(ctx, inst.Type, inst.Settings()); err != nil {
+			m.logger.Warn().
+				Str("provider", inst.Type).
+				Str("instance", inst.InstanceID).
+				Err(err).
+				Msg("Failed to initialize provider")
+		}
+	}
+
+	return nil
+}
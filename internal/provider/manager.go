@@ -2,8 +2,11 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -11,11 +14,28 @@ import (
 )
 
 // Manager manages the lifecycle of multiple provider instances.
+// Instances are keyed by "providerType:instanceID" (see InstanceKey), so
+// several instances of the same provider type can be managed side by side.
 type Manager struct {
 	mu        sync.RWMutex
 	providers map[string]*ProviderInstance
+	pending   map[string]*pendingInstance
+	configs   map[string]InstanceConfig
 	registry  *Registry
 	logger    *zerolog.Logger
+
+	// types receives the type definitions contributed by providers
+	types *types.TypeRegistry
+
+	// adminMu serializes runtime reconfiguration (see admin.go)
+	adminMu sync.Mutex
+
+	// resetHandlers drop what was collected for removed or reconfigured
+	// instances (see OnReset)
+	resetHandlers []ResetHandler
+
+	// generations numbers the started instances (see ProviderInstance.generation)
+	generations atomic.Uint64
 }
 
 // ProviderInstance represents an active provider instance with its state.
@@ -23,88 +43,324 @@ type ProviderInstance struct {
 	Provider       Provider
 	Config         map[string]any
 	Status         ProviderStatus
+	policy         *ProviderConfig
+	inflight       *inflightProvider
 	lastDiscovery  time.Time
 	discoveryMutex sync.Mutex
+
+	// generation tells apart the instances started under the same key, so
+	// work begun on a replaced or removed instance can be dropped
+	generation uint64
+}
+
+// pendingInstance is a configured instance whose initialization failed.
+type pendingInstance struct {
+	providerType string
+	config       map[string]any
+	status       ProviderStatus
+	nextAttempt  time.Time
 }
 
 // NewManager creates a new provider manager.
 func NewManager(registry *Registry, logger *zerolog.Logger) *Manager {
 	return &Manager{
 		providers: make(map[string]*ProviderInstance),
+		pending:   make(map[string]*pendingInstance),
+		configs:   make(map[string]InstanceConfig),
 		registry:  registry,
 		logger:    logger,
 	}
 }
 
+// Registry returns the provider type registry the manager creates instances from.
+func (m *Manager) Registry() *Registry {
+	return m.registry
+}
+
+// SetTypeRegistry sets the registry that providers implementing
+// TypeDefinitionsProvider register their types in when initialized.
+func (m *Manager) SetTypeRegistry(registry *types.TypeRegistry) {
+	m.types = registry
+}
+
 // Initialize initializes a provider from the registry with the given configuration.
-// The provider instance is stored and managed by the manager.
-func (m *Manager) Initialize(ctx context.Context, name string, config map[string]any) error {
+// The config must include an "instance_id" field; the provider instance is stored
+// and managed by the manager under the key "providerType:instanceID".
+//
+// If the provider itself fails to initialize (e.g. its backend is down), the
+// instance is kept as pending and RetryPending tries again later. Invalid
+// configuration is not retried.
+func (m *Manager) Initialize(ctx context.Context, providerType string, config map[string]any) error {
+	instanceID, _ := config["instance_id"].(string)
+	if instanceID == "" {
+		return fmt.Errorf("provider %q: config field \"instance_id\" is required", providerType)
+	}
+	key := InstanceKey(providerType, instanceID)
+
+	// Check if already initialized
+	m.mu.RLock()
+	_, exists := m.providers[key]
+	m.mu.RUnlock()
+	if exists {
+		return fmt.Errorf("provider instance %q already initialized", key)
+	}
+
+	config, policy, err := m.prepare(key, providerType, config)
+	if err != nil {
+		return err
+	}
+
+	inst, err := m.start(ctx, providerType, instanceID, config, policy)
+	if err != nil {
+		if errors.Is(err, ErrInitFailed) {
+			m.addPending(key, providerType, instanceID, config, err)
+		}
+		return err
+	}
+
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Check if already initialized
-	if _, exists := m.providers[name]; exists {
-		return fmt.Errorf("provider %q already initialized", name)
+	if _, exists := m.providers[key]; exists {
+		// Lost a race with a concurrent Initialize of the same instance
+		_ = inst.Provider.Shutdown(ctx)
+		return fmt.Errorf("provider instance %q already initialized", key)
+	}
+	delete(m.pending, key)
+
+	// Store the provider instance
+	m.providers[key] = inst
+
+	m.logger.Info().
+		Str("provider", providerType).
+		Str("instance", instanceID).
+		Msg("Provider initialized")
+
+	return nil
+}
+
+// prepare fills in schema defaults, validates the config against the provider's
+// schema and parses the per-instance middleware settings.
+func (m *Manager) prepare(key, providerType string, config map[string]any) (map[string]any, *ProviderConfig, error) {
+	config, err := m.registry.ApplyDefaults(providerType, config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("config validation failed: %w", err)
+	}
+	if err := m.registry.ValidateConfig(providerType, config); err != nil {
+		return nil, nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
-	// Validate config against schema
-	if err := m.registry.ValidateConfig(name, config); err != nil {
-		return fmt.Errorf("config validation failed: %w", err)
+	// Per-instance timeout, retry, rate limit and caching settings
+	policy, err := ProviderConfigFromMap(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("provider %q: %w", key, err)
 	}
+	return config, policy, nil
+}
 
-	// Create provider instance
-	prov, err := m.registry.Create(name)
+// start creates and initializes a provider and wraps it in its middleware chain.
+// Initialization usually talks to the backend, so it must not run under m.mu.
+func (m *Manager) start(ctx context.Context, providerType, instanceID string, config map[string]any, policy *ProviderConfig) (*ProviderInstance, error) {
+	prov, err := m.registry.Create(providerType)
 	if err != nil {
-		return fmt.Errorf("failed to create provider: %w", err)
+		return nil, fmt.Errorf("failed to create provider: %w", err)
 	}
 
-	// Initialize the provider
 	if err := prov.Initialize(ctx, config); err != nil {
-		return fmt.Errorf("provider initialization failed: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInitFailed, err)
 	}
 
-	// Store the provider instance
-	m.providers[name] = &ProviderInstance{
-		Provider: prov,
-		Config:   config,
+	// Register the provider's own types; conflicting definitions are a
+	// configuration problem, so they aren't retried
+	if defined, ok := prov.(TypeDefinitionsProvider); ok && m.types != nil {
+		if err := m.types.RegisterProvided(providerType, defined.TypeDefinitions()); err != nil {
+			_ = prov.Shutdown(ctx)
+			return nil, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+		}
+	}
+
+	// Wrap the provider in the middleware chain described by its config, and
+	// count in-flight calls on the outside so the instance can be drained
+	inflight := newInflightProvider(Chain(prov, policy.Middleware()...))
+
+	return &ProviderInstance{
+		Provider:   inflight,
+		Config:     config,
+		policy:     policy,
+		inflight:   inflight,
+		generation: m.generations.Add(1),
 		Status: ProviderStatus{
-			Name:                name,
+			Name:                InstanceKey(providerType, instanceID),
+			ProviderType:        providerType,
+			InstanceID:          instanceID,
 			Connected:           true,
 			EntityCount:         0,
 			SupportsIncremental: prov.SupportsIncremental(),
+			Health:              HealthStatus{State: HealthStateUnknown},
 		},
+	}, nil
+}
+
+// addPending records an instance whose initialization failed so it can be retried.
+func (m *Manager) addPending(key, providerType, instanceID string, config map[string]any, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pending, exists := m.pending[key]
+	if !exists {
+		pending = &pendingInstance{
+			providerType: providerType,
+			status: ProviderStatus{
+				Name:         key,
+				ProviderType: providerType,
+				InstanceID:   instanceID,
+				Connected:    false,
+			},
+		}
+		m.pending[key] = pending
 	}
+	pending.config = config
 
-	m.logger.Info().
-		Str("provider", name).
-		Msg("Provider initialized")
+	now := time.Now()
+	pending.status.LastError = err.Error()
+	pending.status.Health = HealthStatus{
+		State:               HealthStateInitFailed,
+		LastCheck:           now,
+		ConsecutiveFailures: pending.status.Health.ConsecutiveFailures + 1,
+		LastError:           err.Error(),
+	}
+	pending.nextAttempt = now.Add(reinitBackoff(pending.status.Health.ConsecutiveFailures))
+}
 
-	return nil
+// RetryPending retries the initialization of pending instances whose backoff
+// has elapsed. Returns the keys of the instances that came up. It holds
+// adminMu, so an instance removed or reconfigured at runtime isn't brought
+// back with its old configuration.
+func (m *Manager) RetryPending(ctx context.Context) []string {
+	m.adminMu.Lock()
+	defer m.adminMu.Unlock()
+
+	now := time.Now()
+
+	m.mu.RLock()
+	due := make(map[string]*pendingInstance)
+	for key, pending := range m.pending {
+		if !now.Before(pending.nextAttempt) {
+			due[key] = pending
+		}
+	}
+	m.mu.RUnlock()
+
+	var recovered []string
+	for key, pending := range due {
+		if err := m.Initialize(ctx, pending.providerType, pending.config); err != nil {
+			m.logger.Debug().
+				Str("provider", key).
+				Err(err).
+				Msg("Provider re-initialization failed")
+			continue
+		}
+		recovered = append(recovered, key)
+	}
+	sort.Strings(recovered)
+	return recovered
 }
 
-// Shutdown shuts down a provider and removes it from the manager.
-func (m *Manager) Shutdown(ctx context.Context, name string) error {
+// ListPending returns the instance keys of instances whose initialization failed.
+func (m *Manager) ListPending() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0, len(m.pending))
+	for key := range m.pending {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// recordHealth records the outcome of a health probe or sync run and opens or
+// closes the circuit of an instance. The circuit opens (the instance is marked
+// as not connected and leaves federation) after threshold consecutive
+// failures, and closes again on the first success. Outcomes for an earlier
+// generation of the instance are ignored.
+func (m *Manager) recordHealth(key string, generation uint64, err error, threshold int) (opened, closed bool) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	inst, exists := m.providers[name]
+	inst, exists := m.providers[key]
+	if !exists || inst.generation != generation {
+		return false, false
+	}
+
+	health := &inst.Status.Health
+	health.LastCheck = time.Now()
+
+	if err == nil {
+		closed = !inst.Status.Connected
+		inst.Status.Connected = true
+		health.State = HealthStateHealthy
+		health.ConsecutiveFailures = 0
+		health.LastError = ""
+		return false, closed
+	}
+
+	health.ConsecutiveFailures++
+	health.LastError = err.Error()
+	inst.Status.LastError = err.Error()
+	if health.ConsecutiveFailures >= threshold && inst.Status.Connected {
+		inst.Status.Connected = false
+		health.State = HealthStateUnhealthy
+		opened = true
+	}
+	return opened, false
+}
+
+// Shutdown shuts down a provider instance and removes it from the manager.
+// The key is the instance key ("providerType:instanceID"). The instance stops
+// receiving new calls immediately; calls already in flight are given until
+// ctx is done to finish before the provider is shut down.
+func (m *Manager) Shutdown(ctx context.Context, key string) error {
+	m.mu.Lock()
+	inst, exists := m.providers[key]
 	if !exists {
-		return fmt.Errorf("provider %q not initialized", name)
+		defer m.mu.Unlock()
+
+		// A pending instance has nothing to shut down, just stop retrying it
+		if _, pending := m.pending[key]; pending {
+			delete(m.pending, key)
+			return nil
+		}
+		return fmt.Errorf("%w: %q", ErrUnknownInstance, key)
+	}
+	delete(m.providers, key)
+	m.mu.Unlock()
+
+	return m.retire(ctx, key, inst)
+}
+
+// retire drains an instance that has already been removed from the manager
+// and shuts its provider down.
+func (m *Manager) retire(ctx context.Context, key string, inst *ProviderInstance) error {
+	if inst.inflight != nil {
+		if err := inst.inflight.drain(ctx); err != nil {
+			m.logger.Warn().
+				Str("provider", key).
+				Int("in_flight", inst.inflight.active()).
+				Msg("Provider drain timed out, shutting down with calls in flight")
+		}
 	}
 
-	// Shutdown the provider
 	if err := inst.Provider.Shutdown(ctx); err != nil {
 		m.logger.Error().
-			Str("provider", name).
+			Str("provider", key).
 			Err(err).
 			Msg("Provider shutdown failed")
 		return err
 	}
 
-	delete(m.providers, name)
-
 	m.logger.Info().
-		Str("provider", name).
+		Str("provider", key).
 		Msg("Provider shut down")
 
 	return nil
@@ -113,22 +369,18 @@ func (m *Manager) Shutdown(ctx context.Context, name string) error {
 // ShutdownAll shuts down all managed providers.
 func (m *Manager) ShutdownAll(ctx context.Context) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	providers := m.providers
+	m.providers = make(map[string]*ProviderInstance)
+	m.pending = make(map[string]*pendingInstance)
+	m.mu.Unlock()
 
 	var errs []error
-
-	for name, inst := range m.providers {
-		if err := inst.Provider.Shutdown(ctx); err != nil {
-			m.logger.Error().
-				Str("provider", name).
-				Err(err).
-				Msg("Provider shutdown failed")
+	for name, inst := range providers {
+		if err := m.retire(ctx, name, inst); err != nil {
 			errs = append(errs, fmt.Errorf("%q: %w", name, err))
 		}
 	}
 
-	m.providers = make(map[string]*ProviderInstance)
-
 	if len(errs) > 0 {
 		return fmt.Errorf("shutdown errors: %v", errs)
 	}
@@ -136,51 +388,105 @@ func (m *Manager) ShutdownAll(ctx context.Context) error {
 	return nil
 }
 
-// Get retrieves a managed provider instance by name.
-func (m *Manager) Get(name string) (Provider, bool) {
+// Get retrieves a managed provider instance by its instance key.
+func (m *Manager) Get(key string) (Provider, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	inst, exists := m.providers[name]
+	inst, exists := m.providers[key]
 	if !exists {
 		return nil, false
 	}
 	return inst.Provider, true
 }
 
-// List returns the names of all managed providers.
+// generation returns the generation of the instance running under key, or 0
+// if there is none.
+func (m *Manager) generation(key string) uint64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if inst, exists := m.providers[key]; exists {
+		return inst.generation
+	}
+	return 0
+}
+
+// healthTarget returns the in-flight wrapper and generation of the instance
+// running under key, if it implements HealthChecker.
+func (m *Manager) healthTarget(key string) (*inflightProvider, uint64, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	inst, exists := m.providers[key]
+	if !exists || inst.inflight == nil {
+		return nil, 0, false
+	}
+	if _, ok := Unwrap(inst.Provider).(HealthChecker); !ok {
+		return nil, 0, false
+	}
+	return inst.inflight, inst.generation, true
+}
+
+// List returns the instance keys of all managed providers.
 func (m *Manager) List() []string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	names := make([]string, 0, len(m.providers))
-	for name := range m.providers {
-		names = append(names, name)
+	keys := make([]string, 0, len(m.providers))
+	for key := range m.providers {
+		keys = append(keys, key)
 	}
-	return names
+	sort.Strings(keys)
+	return keys
 }
 
-// Status returns the status of all managed providers.
+// ListByType returns the instance keys of all managed instances of the given provider type.
+func (m *Manager) ListByType(providerType string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var keys []string
+	for key, inst := range m.providers {
+		if inst.Status.ProviderType == providerType {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Status returns the status of all managed provider instances, ordered by instance key.
+// Instances whose initialization failed are included as not connected.
 func (m *Manager) Status() []ProviderStatus {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	statuses := make([]ProviderStatus, 0, len(m.providers))
+	statuses := make([]ProviderStatus, 0, len(m.providers)+len(m.pending))
 	for _, inst := range m.providers {
 		status := inst.Status
 		status.LastDiscovery = inst.lastDiscovery
 		statuses = append(statuses, status)
 	}
+	for _, pending := range m.pending {
+		statuses = append(statuses, pending.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
 	return statuses
 }
 
-// GetStatus returns the status of a specific provider.
-func (m *Manager) GetStatus(name string) (ProviderStatus, bool) {
+// GetStatus returns the status of a specific provider instance.
+func (m *Manager) GetStatus(key string) (ProviderStatus, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	inst, exists := m.providers[name]
+	inst, exists := m.providers[key]
 	if !exists {
+		if pending, ok := m.pending[key]; ok {
+			return pending.status, true
+		}
 		return ProviderStatus{}, false
 	}
 
@@ -237,7 +543,7 @@ func (m *Manager) DiscoverAll(ctx context.Context) ([]types.Entity, error) {
 	return allEntities, nil
 }
 
-// Discover runs discovery on a specific provider.
+// Discover runs discovery on a specific provider instance.
 func (m *Manager) Discover(ctx context.Context, name string) ([]types.Entity, error) {
 	m.mu.RLock()
 	inst, exists := m.providers[name]
@@ -259,11 +565,10 @@ func (m *Manager) Discover(ctx context.Context, name string) ([]types.Entity, er
 	duration := time.Since(start)
 
 	if err != nil {
-		// Update status with error
+		// Update status with error. Whether the instance stays in federation
+		// is up to its health (see recordHealth).
 		m.mu.Lock()
 		inst.Status.LastError = err.Error()
-		inst.Status.Connected = false
-		m.providers[name] = inst
 		m.mu.Unlock()
 
 		return nil, fmt.Errorf("discovery failed: %w", err)
@@ -274,8 +579,6 @@ func (m *Manager) Discover(ctx context.Context, name string) ([]types.Entity, er
 	inst.lastDiscovery = time.Now()
 	inst.Status.EntityCount = len(entities)
 	inst.Status.LastError = ""
-	inst.Status.Connected = true
-	m.providers[name] = inst
 	m.mu.Unlock()
 
 	m.logger.Info().
@@ -287,7 +590,7 @@ func (m *Manager) Discover(ctx context.Context, name string) ([]types.Entity, er
 	return entities, nil
 }
 
-// DiscoverSince runs incremental discovery on a specific provider.
+// DiscoverSince runs incremental discovery on a specific provider instance.
 // Returns an error if the provider doesn't support incremental updates.
 func (m *Manager) DiscoverSince(ctx context.Context, name string, since time.Time) ([]types.Entity, error) {
 	m.mu.RLock()
@@ -315,8 +618,6 @@ func (m *Manager) DiscoverSince(ctx context.Context, name string, since time.Tim
 		// Update status with error
 		m.mu.Lock()
 		inst.Status.LastError = err.Error()
-		inst.Status.Connected = false
-		m.providers[name] = inst
 		m.mu.Unlock()
 
 		return nil, fmt.Errorf("incremental discovery failed: %w", err)
@@ -327,8 +628,6 @@ func (m *Manager) DiscoverSince(ctx context.Context, name string, since time.Tim
 	inst.lastDiscovery = time.Now()
 	inst.Status.EntityCount += len(entities)
 	inst.Status.LastError = ""
-	inst.Status.Connected = true
-	m.providers[name] = inst
 	m.mu.Unlock()
 
 	m.logger.Info().
@@ -339,6 +638,33 @@ func (m *Manager) DiscoverSince(ctx context.Context, name string, since time.Tim
 	return entities, nil
 }
 
+// updateSyncStatus applies an update to the sync status of an instance.
+func (m *Manager) updateSyncStatus(key string, update func(*SyncStatus)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if inst, exists := m.providers[key]; exists {
+		update(&inst.Status.Sync)
+	}
+}
+
+// restoreSyncState applies persisted sync state to an instance after a restart.
+func (m *Manager) restoreSyncState(key string, state SyncState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	inst, exists := m.providers[key]
+	if !exists {
+		return
+	}
+
+	inst.Status.Sync.Watermark = state.Watermark
+	inst.Status.Sync.LastFullSync = state.LastFullSync
+	if inst.lastDiscovery.IsZero() {
+		inst.lastDiscovery = state.Watermark
+	}
+}
+
 // SearchAll runs a search query across all managed providers concurrently.
 func (m *Manager) SearchAll(ctx context.Context, query SearchQuery) map[string][]types.Entity {
 	m.mu.RLock()
@@ -378,61 +704,78 @@ func (m *Manager) SearchAll(ctx context.Context, query SearchQuery) map[string][
 	return results
 }
 
-// Hydrate retrieves full entity details by ID from the appropriate provider.
-// The provider name is inferred from the entity ID prefix or looked up.
+// Hydrate retrieves full entity details by ID from the provider instance that owns it.
+// The owning instance is resolved from the "providerType:instanceID" prefix of the ID.
 func (m *Manager) Hydrate(ctx context.Context, id string) (types.Entity, error) {
-	// Parse provider from ID (format: "provider:rest-of-id")
-	// For now, we'll iterate through providers
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	for name, inst := range m.providers {
-		if !inst.Status.Connected {
-			continue
-		}
+	inst, key, err := m.instanceForEntity(id)
+	if err != nil {
+		return types.Entity{}, err
+	}
 
-		entity, err := inst.Provider.Hydrate(ctx, id)
-		if err == nil {
-			return entity, nil
-		}
-		if err != ErrNotFound {
+	entity, err := inst.Provider.Hydrate(ctx, id)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
 			m.logger.Warn().
-				Str("provider", name).
+				Str("provider", key).
 				Str("id", id).
 				Err(err).
 				Msg("Provider hydrate failed")
 		}
+		return types.Entity{}, err
 	}
 
-	return types.Entity{}, ErrNotFound
+	return entity, nil
 }
 
-// GetRelated retrieves related entities from the appropriate provider.
+// GetRelated retrieves related entities from the provider instance that owns the entity.
 func (m *Manager) GetRelated(ctx context.Context, id string, relType string) ([]types.Entity, error) {
-	// Find the provider that owns this entity
-	// For now, iterate through providers
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	for name, inst := range m.providers {
-		if !inst.Status.Connected {
-			continue
-		}
+	inst, key, err := m.instanceForEntity(id)
+	if err != nil {
+		return nil, err
+	}
 
-		related, err := inst.Provider.GetRelated(ctx, id, relType)
-		if err == nil {
-			return related, nil
-		}
-		if err != ErrNotFound {
+	related, err := inst.Provider.GetRelated(ctx, id, relType)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
 			m.logger.Warn().
-				Str("provider", name).
+				Str("provider", key).
 				Str("id", id).
 				Err(err).
 				Msg("Provider getRelated failed")
 		}
+		return nil, err
 	}
 
-	return nil, ErrNotFound
+	return related, nil
+}
+
+// instanceForEntity resolves the provider instance that owns the given entity ID.
+// Returns ErrUnknownInstance if the ID names an instance that is not managed,
+// and ErrNotFound if the ID is malformed.
+func (m *Manager) instanceForEntity(id string) (*ProviderInstance, string, error) {
+	entityID := EntityID(id)
+	if !entityID.IsValid() {
+		return nil, "", fmt.Errorf("%w: invalid entity ID %q", ErrNotFound, id)
+	}
+	key := entityID.InstanceKey()
+
+	m.mu.RLock()
+	inst, exists := m.providers[key]
+	_, pending := m.pending[key]
+	var connected bool
+	if exists {
+		connected = inst.Status.Connected
+	}
+	m.mu.RUnlock()
+
+	if !exists && !pending {
+		return nil, key, fmt.Errorf("%w: %q", ErrUnknownInstance, key)
+	}
+	if !connected {
+		return nil, key, fmt.Errorf("%w: %q", ErrInstanceUnavailable, key)
+	}
+
+	return inst, key, nil
 }
 
 // Count returns the number of managed providers.
@@ -443,7 +786,7 @@ func (m *Manager) Count() int {
 	return len(m.providers)
 }
 
-// IsConnected checks if a specific provider is connected.
+// IsConnected checks if a specific provider instance is connected.
 func (m *Manager) IsConnected(name string) bool {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -452,6 +795,51 @@ func (m *Manager) IsConnected(name string) bool {
 	return exists && inst.Status.Connected
 }
 
+// SoftDeadline returns how long federated searches should wait for an
+// instance, or 0 if the instance doesn't set its own soft deadline.
+func (m *Manager) SoftDeadline(name string) time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	inst, exists := m.providers[name]
+	if !exists || inst.policy == nil {
+		return 0
+	}
+	return inst.policy.SoftDeadline
+}
+
+// EntityTypes returns the entity types an instance declares through
+// TypeProvider. It returns false if the instance doesn't declare them.
+func (m *Manager) EntityTypes(name string) ([]string, bool) {
+	m.mu.RLock()
+	inst, exists := m.providers[name]
+	m.mu.RUnlock()
+	if !exists {
+		return nil, false
+	}
+
+	typed, ok := Unwrap(inst.Provider).(TypeProvider)
+	if !ok {
+		return nil, false
+	}
+	entityTypes := typed.EntityTypes()
+	return entityTypes, len(entityTypes) > 0
+}
+
+// SupportsFilterExpressions reports whether a provider instance evaluates
+// boolean filter groups itself.
+func (m *Manager) SupportsFilterExpressions(name string) bool {
+	m.mu.RLock()
+	inst, exists := m.providers[name]
+	m.mu.RUnlock()
+	if !exists {
+		return false
+	}
+
+	exprs, ok := Unwrap(inst.Provider).(FilterExpressionProvider)
+	return ok && exprs.SupportsFilterExpressions()
+}
+
 // FilterCapabilities returns aggregated filter capabilities from all connected providers.
 // The returned map is keyed by attribute name, with values representing
 // the union of capabilities across all providers (an attribute is filterable
@@ -474,6 +862,7 @@ func (m *Manager) FilterCapabilities(ctx context.Context) (map[string]FilterCapa
 		if err != nil {
 			m.logger.Warn().
 				Str("provider", prov.Name()).
+				Str("instance", prov.InstanceID()).
 				Err(err).
 				Msg("Failed to get filter capabilities")
 			continue
@@ -546,11 +935,12 @@ func (m *Manager) GetFilterValues(ctx context.Context, filterName string) ([]Fil
 
 	var allOptions []FilterOption
 	for _, prov := range providerList {
-		if fv, ok := prov.(FilterValuesProvider); ok {
+		if fv, ok := Unwrap(prov).(FilterValuesProvider); ok {
 			options, err := fv.FilterValues(ctx, filterName)
 			if err != nil {
 				m.logger.Warn().
 					Str("provider", prov.Name()).
+					Str("instance", prov.InstanceID()).
 					Str("filter", filterName).
 					Err(err).
 					Msg("Failed to get filter values")
@@ -578,7 +968,7 @@ func (m *Manager) GetAttributeExtensions(ctx context.Context) map[string]types.A
 
 	extensions := make(map[string]types.AttributeDef)
 	for _, prov := range providerList {
-		if attrExt, ok := prov.(AttributeExtensionsProvider); ok {
+		if attrExt, ok := Unwrap(prov).(AttributeExtensionsProvider); ok {
 			provExts := attrExt.AttributeExtensions(ctx)
 			for name, attrDef := range provExts {
 				extensions[name] = attrDef
@@ -0,0 +1,501 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/yourname/mifind/internal/types"
+	"golang.org/x/time/rate"
+)
+
+// Instance config keys read by ProviderConfigFromMap.
+// They sit next to the provider-specific keys in an instance's config map.
+const (
+	ConfigKeyTimeout       = "timeout"
+	ConfigKeyRetryCount    = "retry_count"
+	ConfigKeyRateLimit     = "rate_limit"
+	ConfigKeyEnableCaching = "enable_caching"
+	ConfigKeyCacheTTL      = "cache_ttl"
+	ConfigKeySoftDeadline  = "soft_deadline"
+)
+
+// PolicyConfigFields returns the schema of the middleware settings that
+// every provider instance accepts in addition to its own config fields.
+func PolicyConfigFields() map[string]ConfigField {
+	return map[string]ConfigField{
+		ConfigKeyTimeout: {
+			Type:        "duration",
+			Description: "Per-call timeout for search, hydrate and related lookups (default 30s)",
+		},
+		ConfigKeyRetryCount: {
+			Type:        "int",
+			Description: "Retries for temporary and rate-limit errors (default 3)",
+		},
+		ConfigKeyRateLimit: {
+			Type:        "float",
+			Description: "Maximum requests per second to the backend (default 0 = unlimited)",
+		},
+		ConfigKeyEnableCaching: {
+			Type:        "bool",
+			Description: "Cache search and hydrate results for cache_ttl (default false)",
+		},
+		ConfigKeyCacheTTL: {
+			Type:        "duration",
+			Description: "TTL of cached results (default 5m)",
+		},
+		ConfigKeySoftDeadline: {
+			Type:        "duration",
+			Description: "How long a federated search waits for this instance before answering without it (default: the search soft_deadline)",
+		},
+	}
+}
+
+// Retry backoff bounds.
+const (
+	retryBaseDelay = 200 * time.Millisecond
+	retryMaxDelay  = 5 * time.Second
+)
+
+// Middleware decorates a Provider with cross-cutting behavior.
+type Middleware func(Provider) Provider
+
+// Chain wraps a provider with the given middleware. The first middleware is
+// the outermost, so it sees every call first.
+func Chain(p Provider, middleware ...Middleware) Provider {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		p = middleware[i](p)
+	}
+	return p
+}
+
+// Unwrap returns the innermost provider of a middleware chain.
+// Use it before type-asserting optional interfaces such as ThumbnailProvider,
+// which the middleware wrappers don't forward.
+func Unwrap(p Provider) Provider {
+	for {
+		wrapper, ok := p.(interface{ Unwrap() Provider })
+		if !ok {
+			return p
+		}
+		p = wrapper.Unwrap()
+	}
+}
+
+// ProviderConfigFromMap builds a ProviderConfig from an instance config map,
+// starting from NewProviderConfig defaults. Caching is off unless the instance
+// enables it, so searches see fresh results by default. Durations may be
+// given as duration strings ("10s") or as a number of seconds.
+func ProviderConfigFromMap(config map[string]any) (*ProviderConfig, error) {
+	c := NewProviderConfig()
+	c.EnableCaching = false
+
+	if v, ok := config[ConfigKeyTimeout]; ok {
+		d, err := durationValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ConfigKeyTimeout, err)
+		}
+		c.Timeout = d
+	}
+	if v, ok := config[ConfigKeyRetryCount]; ok {
+		n, err := floatValue(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s: %v", ConfigKeyRetryCount, v)
+		}
+		c.RetryCount = int(n)
+	}
+	if v, ok := config[ConfigKeyRateLimit]; ok {
+		n, err := floatValue(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s: %v", ConfigKeyRateLimit, v)
+		}
+		c.RateLimit = n
+	}
+	if v, ok := config[ConfigKeyEnableCaching]; ok {
+		enabled, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s: %v", ConfigKeyEnableCaching, v)
+		}
+		c.EnableCaching = enabled
+	}
+	if v, ok := config[ConfigKeyCacheTTL]; ok {
+		d, err := durationValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ConfigKeyCacheTTL, err)
+		}
+		c.CacheTTL = d
+	}
+	if v, ok := config[ConfigKeySoftDeadline]; ok {
+		d, err := durationValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", ConfigKeySoftDeadline, err)
+		}
+		c.SoftDeadline = d
+	}
+
+	return c, nil
+}
+
+// Middleware returns the middleware chain described by the config, outermost first:
+// caching, retries, rate limiting and the per-call timeout.
+// Caching sits outside the rate limiter so cache hits don't use tokens, and
+// each retry attempt gets its own token and its own timeout.
+func (c *ProviderConfig) Middleware() []Middleware {
+	var chain []Middleware
+	if c.EnableCaching && c.CacheTTL > 0 {
+		chain = append(chain, CacheMiddleware(c.CacheTTL))
+	}
+	if c.RetryCount > 0 {
+		chain = append(chain, RetryMiddleware(c.RetryCount))
+	}
+	if c.RateLimit > 0 {
+		chain = append(chain, RateLimitMiddleware(c.RateLimit))
+	}
+	if c.Timeout > 0 {
+		chain = append(chain, TimeoutMiddleware(c.Timeout))
+	}
+	return chain
+}
+
+// wrapper forwards every Provider method to the wrapped provider.
+// Middleware embed it and override the calls they decorate.
+type wrapper struct {
+	Provider
+}
+
+// Unwrap returns the wrapped provider.
+func (w wrapper) Unwrap() Provider {
+	return w.Provider
+}
+
+// TimeoutMiddleware bounds Search, Hydrate and GetRelated calls by the given timeout.
+// Discovery is not bounded, since a full Discover can legitimately take much longer
+// than an interactive call.
+func TimeoutMiddleware(timeout time.Duration) Middleware {
+	return func(p Provider) Provider {
+		return &timeoutProvider{wrapper: wrapper{p}, timeout: timeout}
+	}
+}
+
+type timeoutProvider struct {
+	wrapper
+	timeout time.Duration
+}
+
+func (p *timeoutProvider) Search(ctx context.Context, query SearchQuery) ([]types.Entity, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return p.Provider.Search(ctx, query)
+}
+
+func (p *timeoutProvider) Hydrate(ctx context.Context, id string) (types.Entity, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return p.Provider.Hydrate(ctx, id)
+}
+
+func (p *timeoutProvider) GetRelated(ctx context.Context, id string, relType string) ([]types.Entity, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	return p.Provider.GetRelated(ctx, id, relType)
+}
+
+// RetryMiddleware retries calls that fail with a temporary or rate_limit
+// ProviderError, up to retries extra attempts with exponential backoff.
+func RetryMiddleware(retries int) Middleware {
+	return func(p Provider) Provider {
+		return &retryProvider{wrapper: wrapper{p}, retries: retries}
+	}
+}
+
+type retryProvider struct {
+	wrapper
+	retries int
+}
+
+func (p *retryProvider) Search(ctx context.Context, query SearchQuery) ([]types.Entity, error) {
+	var entities []types.Entity
+	err := p.do(ctx, func() (err error) {
+		entities, err = p.Provider.Search(ctx, query)
+		return err
+	})
+	return entities, err
+}
+
+func (p *retryProvider) Hydrate(ctx context.Context, id string) (types.Entity, error) {
+	var entity types.Entity
+	err := p.do(ctx, func() (err error) {
+		entity, err = p.Provider.Hydrate(ctx, id)
+		return err
+	})
+	return entity, err
+}
+
+func (p *retryProvider) GetRelated(ctx context.Context, id string, relType string) ([]types.Entity, error) {
+	var entities []types.Entity
+	err := p.do(ctx, func() (err error) {
+		entities, err = p.Provider.GetRelated(ctx, id, relType)
+		return err
+	})
+	return entities, err
+}
+
+func (p *retryProvider) Discover(ctx context.Context) ([]types.Entity, error) {
+	var entities []types.Entity
+	err := p.do(ctx, func() (err error) {
+		entities, err = p.Provider.Discover(ctx)
+		return err
+	})
+	return entities, err
+}
+
+func (p *retryProvider) DiscoverSince(ctx context.Context, since time.Time) ([]types.Entity, error) {
+	var entities []types.Entity
+	err := p.do(ctx, func() (err error) {
+		entities, err = p.Provider.DiscoverSince(ctx, since)
+		return err
+	})
+	return entities, err
+}
+
+// do runs call until it succeeds, fails with a non-retryable error,
+// runs out of attempts or the context is done.
+func (p *retryProvider) do(ctx context.Context, call func() error) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = call(); err == nil || !IsRetryable(err) || attempt >= p.retries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff(attempt)):
+		}
+	}
+}
+
+// IsRetryable reports whether err is a ProviderError of type temporary or rate_limit.
+func IsRetryable(err error) bool {
+	var perr *ProviderError
+	if !errors.As(err, &perr) {
+		return false
+	}
+	return perr.Type == ErrorTypeTemporary || perr.Type == ErrorTypeRateLimit
+}
+
+// backoff returns the delay before the given retry attempt (0-based).
+func backoff(attempt int) time.Duration {
+	delay := time.Duration(float64(retryBaseDelay) * math.Pow(2, float64(attempt)))
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// RateLimitMiddleware limits calls that reach the provider's backend to rps
+// requests per second using a token bucket. Calls wait for a token until their
+// context is done, in which case a rate_limit ProviderError is returned.
+func RateLimitMiddleware(rps float64) Middleware {
+	return func(p Provider) Provider {
+		burst := int(math.Ceil(rps))
+		return &rateLimitProvider{wrapper: wrapper{p}, limiter: rate.NewLimiter(rate.Limit(rps), burst)}
+	}
+}
+
+type rateLimitProvider struct {
+	wrapper
+	limiter *rate.Limiter
+}
+
+func (p *rateLimitProvider) wait(ctx context.Context) error {
+	if err := p.limiter.Wait(ctx); err != nil {
+		return NewProviderError(ErrorTypeRateLimit, ErrRateLimited.Message, err)
+	}
+	return nil
+}
+
+func (p *rateLimitProvider) Search(ctx context.Context, query SearchQuery) ([]types.Entity, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.Provider.Search(ctx, query)
+}
+
+func (p *rateLimitProvider) Hydrate(ctx context.Context, id string) (types.Entity, error) {
+	if err := p.wait(ctx); err != nil {
+		return types.Entity{}, err
+	}
+	return p.Provider.Hydrate(ctx, id)
+}
+
+func (p *rateLimitProvider) GetRelated(ctx context.Context, id string, relType string) ([]types.Entity, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.Provider.GetRelated(ctx, id, relType)
+}
+
+func (p *rateLimitProvider) Discover(ctx context.Context) ([]types.Entity, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.Provider.Discover(ctx)
+}
+
+func (p *rateLimitProvider) DiscoverSince(ctx context.Context, since time.Time) ([]types.Entity, error) {
+	if err := p.wait(ctx); err != nil {
+		return nil, err
+	}
+	return p.Provider.DiscoverSince(ctx, since)
+}
+
+// CacheMiddleware caches successful Search and Hydrate results for the given TTL.
+func CacheMiddleware(ttl time.Duration) Middleware {
+	return func(p Provider) Provider {
+		return &cacheProvider{
+			wrapper:  wrapper{p},
+			ttl:      ttl,
+			searches: make(map[string]cachedSearch),
+			entities: make(map[string]cachedEntity),
+		}
+	}
+}
+
+type cachedSearch struct {
+	entities  []types.Entity
+	expiresAt time.Time
+}
+
+type cachedEntity struct {
+	entity    types.Entity
+	expiresAt time.Time
+}
+
+type cacheProvider struct {
+	wrapper
+	ttl time.Duration
+
+	mu        sync.Mutex
+	searches  map[string]cachedSearch
+	entities  map[string]cachedEntity
+	lastPrune time.Time
+}
+
+func (p *cacheProvider) Search(ctx context.Context, query SearchQuery) ([]types.Entity, error) {
+	// Queries that can't be encoded are simply not cached
+	data, err := json.Marshal(query)
+	if err != nil {
+		return p.Provider.Search(ctx, query)
+	}
+	key := string(data)
+
+	p.mu.Lock()
+	cached, ok := p.searches[key]
+	p.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cloneEntities(cached.entities), nil
+	}
+
+	entities, err := p.Provider.Search(ctx, query)
+	if err != nil {
+		return entities, err
+	}
+
+	p.mu.Lock()
+	p.pruneLocked()
+	p.searches[key] = cachedSearch{
+		entities:  cloneEntities(entities),
+		expiresAt: time.Now().Add(p.ttl),
+	}
+	p.mu.Unlock()
+
+	return entities, nil
+}
+
+func (p *cacheProvider) Hydrate(ctx context.Context, id string) (types.Entity, error) {
+	p.mu.Lock()
+	cached, ok := p.entities[id]
+	p.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.entity.Clone(), nil
+	}
+
+	entity, err := p.Provider.Hydrate(ctx, id)
+	if err != nil {
+		return entity, err
+	}
+
+	p.mu.Lock()
+	p.pruneLocked()
+	p.entities[id] = cachedEntity{entity: entity.Clone(), expiresAt: time.Now().Add(p.ttl)}
+	p.mu.Unlock()
+
+	return entity, nil
+}
+
+// cloneEntities deep copies entities, so that callers modifying the results
+// don't modify the cache.
+func cloneEntities(entities []types.Entity) []types.Entity {
+	clones := make([]types.Entity, len(entities))
+	for i, entity := range entities {
+		clones[i] = entity.Clone()
+	}
+	return clones
+}
+
+// pruneLocked drops expired entries, at most once per TTL. Must be called with mu held.
+func (p *cacheProvider) pruneLocked() {
+	now := time.Now()
+	if now.Sub(p.lastPrune) < p.ttl {
+		return
+	}
+	p.lastPrune = now
+
+	for key, cached := range p.searches {
+		if now.After(cached.expiresAt) {
+			delete(p.searches, key)
+		}
+	}
+	for id, cached := range p.entities {
+		if now.After(cached.expiresAt) {
+			delete(p.entities, id)
+		}
+	}
+}
+
+// durationValue converts a config value to a duration.
+func durationValue(v any) (time.Duration, error) {
+	switch val := v.(type) {
+	case time.Duration:
+		return val, nil
+	case string:
+		return time.ParseDuration(val)
+	default:
+		seconds, err := floatValue(v)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+}
+
+// floatValue converts a numeric config value to a float64.
+func floatValue(v any) (float64, error) {
+	switch val := v.(type) {
+	case int:
+		return float64(val), nil
+	case int64:
+		return float64(val), nil
+	case float64:
+		return val, nil
+	case float32:
+		return float64(val), nil
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", v)
+	}
+}
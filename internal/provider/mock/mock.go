@@ -3,6 +3,7 @@ package mock
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -88,6 +89,7 @@ func (m *MockProvider) Discover(ctx context.Context) ([]types.Entity, error) {
 	for _, entity := range m.entities {
 		entities = append(entities, entity)
 	}
+	sortByID(entities)
 
 	return entities, nil
 }
@@ -114,6 +116,10 @@ func (m *MockProvider) DiscoverSince(ctx context.Context, since time.Time) ([]ty
 
 // Hydrate returns the full entity by ID.
 func (m *MockProvider) Hydrate(ctx context.Context, id string) (types.Entity, error) {
+	if err := ctx.Err(); err != nil {
+		return types.Entity{}, err
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -149,8 +155,13 @@ func (m *MockProvider) GetRelated(ctx context.Context, id string, relType string
 	return related, nil
 }
 
-// Search performs a search over mock entities.
+// Search performs a search over mock entities. Results are ordered by ID so
+// that pages are stable.
 func (m *MockProvider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -166,17 +177,17 @@ func (m *MockProvider) Search(ctx context.Context, query provider.SearchQuery) (
 		if query.Query != "" {
 			matched := false
 			// Search in title
-			if contains(query.Query, entity.Title) {
+			if contains(entity.Title, query.Query) {
 				matched = true
 			}
 			// Search in description
-			if contains(query.Query, entity.Description) {
+			if contains(entity.Description, query.Query) {
 				matched = true
 			}
 			// Search in attributes
 			for key, val := range entity.Attributes {
 				valStr := fmt.Sprintf("%v", val)
-				if contains(query.Query, key) || contains(query.Query, valStr) {
+				if contains(key, query.Query) || contains(valStr, query.Query) {
 					matched = true
 					break
 				}
@@ -193,6 +204,7 @@ func (m *MockProvider) Search(ctx context.Context, query provider.SearchQuery) (
 
 		results = append(results, entity)
 	}
+	sortByID(results)
 
 	// Apply pagination
 	if query.Offset >= len(results) {
@@ -282,11 +294,6 @@ func (m *MockProvider) FilterCapabilities(ctx context.Context) (map[string]provi
 			SupportsContains: true,
 			Description:      "Camera make/model",
 		},
-		types.AttrGPS: {
-			Type:          types.AttributeTypeGPS,
-			SupportsRange: true,
-			Description:   "GPS coordinates",
-		},
 	}, nil
 }
 
@@ -381,17 +388,22 @@ func (m *MockProvider) generateMockEntity(index int) types.Entity {
 	return entity
 }
 
-// matchesFilters checks if an entity matches the given filters.
+// matchesFilters checks if an entity matches the given filters. The "type"
+// filter matches the entity type, range filters ({"min", "max"}) match
+// numeric attributes within the bounds, and list filters match if any of
+// their values does. List attributes match if any element does.
 func (m *MockProvider) matchesFilters(entity types.Entity, filters map[string]any) bool {
 	for key, filterVal := range filters {
-		entityVal, exists := entity.Attributes[key]
-		if !exists {
-			return false
+		var entityVal any = entity.Type
+		if key != types.AttrType {
+			val, exists := entity.Attributes[key]
+			if !exists {
+				return false
+			}
+			entityVal = val
 		}
 
-		// Simple equality check
-		// In a real implementation, this would handle different filter types
-		if fmt.Sprintf("%v", filterVal) != fmt.Sprintf("%v", entityVal) {
+		if !matchesFilter(entityVal, filterVal) {
 			return false
 		}
 	}
@@ -399,6 +411,68 @@ func (m *MockProvider) matchesFilters(entity types.Entity, filters map[string]an
 	return true
 }
 
+// matchesFilter checks a single attribute value against a filter value.
+func matchesFilter(entityVal, filterVal any) bool {
+	if list, ok := entityVal.([]string); ok {
+		for _, item := range list {
+			if matchesFilter(item, filterVal) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch f := filterVal.(type) {
+	case map[string]any:
+		val, ok := toFloat(entityVal)
+		if !ok {
+			return false
+		}
+		if min, ok := toFloat(f["min"]); ok && val < min {
+			return false
+		}
+		if max, ok := toFloat(f["max"]); ok && val > max {
+			return false
+		}
+		return true
+	case []string:
+		for _, item := range f {
+			if matchesFilter(entityVal, item) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if a, ok := toFloat(entityVal); ok {
+		if b, ok := toFloat(filterVal); ok {
+			return a == b
+		}
+	}
+	return fmt.Sprintf("%v", filterVal) == fmt.Sprintf("%v", entityVal)
+}
+
+// toFloat converts numeric values to float64.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// sortByID orders entities by ID.
+func sortByID(entities []types.Entity) {
+	sort.Slice(entities, func(i, j int) bool {
+		return entities[i].ID < entities[j].ID
+	})
+}
+
 // contains checks if a string contains a substring (case-insensitive).
 func contains(s, substr string) bool {
 	return containsIgnoreCase(s, substr)
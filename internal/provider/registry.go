@@ -113,7 +113,25 @@ func (r *Registry) Exists(name string) bool {
 	return exists
 }
 
+// RegisterFactory registers a provider type using the metadata (name,
+// description and config schema) that the provider itself declares.
+// The factory is called once to read the metadata.
+func (r *Registry) RegisterFactory(factory ProviderFactory) error {
+	prov := factory()
+
+	meta := ProviderMetadata{Name: prov.Name()}
+	if described, ok := prov.(interface{ Metadata() ProviderMetadata }); ok {
+		meta = described.Metadata()
+	}
+	meta.Factory = factory
+
+	return r.Register(meta)
+}
+
 // ValidateConfig validates configuration against a provider's config schema.
+// Required fields must be present and fields must have the declared type.
+// If the provider declares a schema, unknown fields are rejected; the
+// middleware settings (see PolicyConfigFields) are accepted for every provider.
 func (r *Registry) ValidateConfig(providerName string, config map[string]any) error {
 	meta := r.Get(providerName)
 	if meta == nil {
@@ -129,9 +147,51 @@ func (r *Registry) ValidateConfig(providerName string, config map[string]any) er
 		}
 	}
 
+	policyFields := PolicyConfigFields()
+	for key, value := range config {
+		field, known := meta.ConfigSchema[key]
+		if !known {
+			field, known = policyFields[key]
+		}
+		if !known {
+			if len(meta.ConfigSchema) > 0 {
+				return fmt.Errorf("provider %q: unknown config field %q", providerName, key)
+			}
+			continue
+		}
+		if !field.accepts(value) {
+			return fmt.Errorf("provider %q: config field %q must be of type %s, got %T", providerName, key, field.Type, value)
+		}
+	}
+
 	return nil
 }
 
+// ApplyDefaults returns a copy of config with the schema defaults filled in
+// for fields that are not set. Whole numbers in int fields are converted to
+// int, since JSON decodes every number as float64.
+func (r *Registry) ApplyDefaults(providerName string, config map[string]any) (map[string]any, error) {
+	meta := r.Get(providerName)
+	if meta == nil {
+		return nil, fmt.Errorf("provider %q not registered", providerName)
+	}
+
+	result := make(map[string]any, len(config))
+	for key, value := range config {
+		result[key] = value
+	}
+	for key, field := range meta.ConfigSchema {
+		if _, exists := result[key]; !exists && field.Default != nil {
+			result[key] = field.Default
+		}
+		if f, ok := result[key].(float64); ok && field.Type == "int" && f == float64(int64(f)) {
+			result[key] = int(f)
+		}
+	}
+
+	return result, nil
+}
+
 // Count returns the number of registered provider types.
 func (r *Registry) Count() int {
 	r.mu.RLock()
@@ -0,0 +1,446 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// Sync modes reported in SyncStatus.Mode.
+const (
+	SyncModeFull        = "full"
+	SyncModeIncremental = "incremental"
+)
+
+// SyncConfig configures the background sync scheduler.
+type SyncConfig struct {
+	// Enabled turns on periodic background discovery
+	Enabled bool `mapstructure:"enabled"`
+
+	// FullInterval is how often a full Discover runs per instance (0 = only on first sync)
+	FullInterval time.Duration `mapstructure:"full_interval"`
+
+	// IncrementalInterval is how often DiscoverSince runs per instance (0 = disabled).
+	// Only used for providers that support incremental discovery.
+	IncrementalInterval time.Duration `mapstructure:"incremental_interval"`
+
+	// StatePath is the file used to persist per-instance watermarks
+	StatePath string `mapstructure:"state_path"`
+}
+
+// DefaultSyncConfig returns the default sync configuration.
+func DefaultSyncConfig() SyncConfig {
+	return SyncConfig{
+		Enabled:             false,
+		FullInterval:        24 * time.Hour,
+		IncrementalInterval: 15 * time.Minute,
+		StatePath:           "data/sync-state.json",
+	}
+}
+
+// SyncHandler receives entities discovered by a sync run.
+// full is true for full discovery runs and false for incremental runs.
+// Returning an error marks the run as failed so the watermark is not advanced.
+type SyncHandler func(ctx context.Context, instanceKey string, entities []types.Entity, full bool) error
+
+// Syncer periodically runs full and incremental discovery on every managed
+// provider instance and persists the per-instance high-water mark, so that
+// incremental syncs resume where they left off after a restart. Failed runs
+// count towards the instance's health like failed probes, and are retried
+// with exponential backoff.
+type Syncer struct {
+	manager   *Manager
+	config    SyncConfig
+	store     WatermarkStore
+	logger    *zerolog.Logger
+	handlers  []SyncHandler
+	threshold int
+
+	mu              sync.Mutex
+	states          map[string]SyncState
+	restored        map[string]bool
+	running         map[string]bool
+	lastIncremental map[string]time.Time
+
+	// retryAt holds when instances whose last run failed are due again
+	retryAt map[string]time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSyncer creates a new sync scheduler for the given manager.
+// store may be nil, in which case watermarks are kept in memory only.
+func NewSyncer(manager *Manager, config SyncConfig, store WatermarkStore, logger *zerolog.Logger) *Syncer {
+	return &Syncer{
+		manager:         manager,
+		config:          config,
+		store:           store,
+		logger:          logger,
+		states:          make(map[string]SyncState),
+		restored:        make(map[string]bool),
+		running:         make(map[string]bool),
+		lastIncremental: make(map[string]time.Time),
+		retryAt:         make(map[string]time.Time),
+		threshold:       DefaultHealthConfig().FailureThreshold,
+	}
+}
+
+// SetFailureThreshold sets the number of consecutive failures, of sync runs
+// and health probes alike, after which an instance is taken out of
+// federation (see HealthConfig.FailureThreshold).
+func (s *Syncer) SetFailureThreshold(threshold int) {
+	s.threshold = max(threshold, 1)
+}
+
+// OnSync registers a handler that receives the entities of every successful run.
+// Handlers must be registered before Start.
+func (s *Syncer) OnSync(handler SyncHandler) {
+	s.handlers = append(s.handlers, handler)
+}
+
+// Start loads persisted watermarks and starts the background scheduler.
+func (s *Syncer) Start(ctx context.Context) error {
+	if s.store != nil {
+		states, err := s.store.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load sync state: %w", err)
+		}
+		s.mu.Lock()
+		s.states = states
+		s.mu.Unlock()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go s.loop(ctx)
+
+	s.logger.Info().
+		Dur("full_interval", s.config.FullInterval).
+		Dur("incremental_interval", s.config.IncrementalInterval).
+		Msg("Provider sync scheduler started")
+
+	return nil
+}
+
+// Stop stops the scheduler and waits for in-flight runs to finish.
+func (s *Syncer) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+// SyncNow runs a sync for one instance synchronously.
+// If full is false and the provider doesn't support incremental discovery,
+// a full sync is run instead.
+func (s *Syncer) SyncNow(ctx context.Context, key string, full bool) error {
+	prov, ok := s.manager.Get(key)
+	if !ok {
+		return fmt.Errorf("provider instance %q not initialized", key)
+	}
+	if !prov.SupportsIncremental() {
+		full = true
+	}
+
+	s.restore(key)
+	if !s.markRunning(key) {
+		return fmt.Errorf("sync already running for %q", key)
+	}
+	defer s.clearRunning(key)
+
+	return s.run(ctx, key, full)
+}
+
+// Reset forgets the sync state of an instance, so that its next sync is a
+// full sync. Register it with Manager.OnReset.
+func (s *Syncer) Reset(key string) error {
+	s.mu.Lock()
+	delete(s.states, key)
+	delete(s.lastIncremental, key)
+	delete(s.retryAt, key)
+	s.mu.Unlock()
+
+	if s.store != nil {
+		if err := s.store.Delete(key); err != nil {
+			return fmt.Errorf("failed to delete sync state of %q: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// loop checks which instances are due on every tick.
+func (s *Syncer) loop(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.tickInterval())
+	defer ticker.Stop()
+
+	s.runDue(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.runDue(ctx, now)
+		}
+	}
+}
+
+// tickInterval returns how often the scheduler checks for due instances.
+func (s *Syncer) tickInterval() time.Duration {
+	tick := time.Minute
+	for _, interval := range []time.Duration{s.config.FullInterval, s.config.IncrementalInterval} {
+		if interval > 0 && interval < tick {
+			tick = interval
+		}
+	}
+	return tick
+}
+
+// runDue starts a sync for every instance that is due and not already running.
+func (s *Syncer) runDue(ctx context.Context, now time.Time) {
+	for _, key := range s.manager.List() {
+		prov, ok := s.manager.Get(key)
+		if !ok {
+			continue
+		}
+
+		s.restore(key)
+
+		full, due := s.due(key, prov.SupportsIncremental(), now)
+		if !due || !s.markRunning(key) {
+			continue
+		}
+
+		s.wg.Add(1)
+		go func(key string, full bool) {
+			defer s.wg.Done()
+			defer s.clearRunning(key)
+
+			// Errors are recorded in the instance status by run
+			_ = s.run(ctx, key, full)
+		}(key, full)
+	}
+}
+
+// due decides whether an instance needs a sync and which mode to use.
+func (s *Syncer) due(key string, supportsIncremental bool, now time.Time) (full bool, due bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.states[key]
+
+	if now.Before(s.retryAt[key]) {
+		return false, false
+	}
+	if state.LastFullSync.IsZero() {
+		return true, true
+	}
+	if s.config.FullInterval > 0 && now.Sub(state.LastFullSync) >= s.config.FullInterval {
+		return true, true
+	}
+	if supportsIncremental && s.config.IncrementalInterval > 0 {
+		last := s.lastIncremental[key]
+		if last.IsZero() || now.Sub(last) >= s.config.IncrementalInterval {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// run executes a single sync run and records its outcome.
+func (s *Syncer) run(ctx context.Context, key string, full bool) error {
+	mode := SyncModeIncremental
+	if full {
+		mode = SyncModeFull
+	}
+
+	s.mu.Lock()
+	state := s.states[key]
+	s.mu.Unlock()
+
+	// A run on an instance that is replaced or removed meanwhile is dropped,
+	// since its entities and watermark belong to the old configuration
+	generation := s.manager.generation(key)
+	replaced := func() bool {
+		if s.manager.generation(key) == generation {
+			return false
+		}
+		s.manager.updateSyncStatus(key, func(status *SyncStatus) {
+			status.Running = false
+		})
+		s.logger.Info().
+			Str("provider", key).
+			Str("mode", mode).
+			Msg("Provider reconfigured during sync, dropping the run")
+		return true
+	}
+
+	s.manager.updateSyncStatus(key, func(status *SyncStatus) {
+		status.Running = true
+		status.Mode = mode
+	})
+
+	// The watermark is the start of the run so that changes made while
+	// discovery is in progress are picked up by the next incremental run.
+	start := time.Now()
+
+	var entities []types.Entity
+	var err error
+	if full {
+		entities, err = s.manager.Discover(ctx, key)
+	} else {
+		entities, err = s.manager.DiscoverSince(ctx, key, state.Watermark)
+	}
+
+	if err == nil && replaced() {
+		return nil
+	}
+
+	if err == nil {
+		for _, handler := range s.handlers {
+			if err = handler(ctx, key, entities, full); err != nil {
+				err = fmt.Errorf("sync handler failed: %w", err)
+				break
+			}
+		}
+	}
+
+	if err != nil && replaced() {
+		return err
+	}
+	if err != nil {
+		failures := 0
+		s.manager.updateSyncStatus(key, func(status *SyncStatus) {
+			status.Running = false
+			status.LastError = err.Error()
+			status.ConsecutiveFailures++
+			failures = status.ConsecutiveFailures
+		})
+
+		// Runs interrupted by shutdown don't count as failures
+		if ctx.Err() == nil {
+			s.mu.Lock()
+			s.retryAt[key] = time.Now().Add(reinitBackoff(failures))
+			s.mu.Unlock()
+			s.recordHealth(key, generation, err)
+		}
+
+		s.logger.Warn().
+			Str("provider", key).
+			Str("mode", mode).
+			Int("consecutive_failures", failures).
+			Err(err).
+			Msg("Provider sync failed")
+		return err
+	}
+	if replaced() {
+		return nil
+	}
+	s.recordHealth(key, generation, nil)
+
+	completed := time.Now()
+	state.Watermark = start
+	if full {
+		state.LastFullSync = completed
+	}
+
+	// A full run also covers the incremental window
+	s.mu.Lock()
+	s.states[key] = state
+	s.lastIncremental[key] = start
+	delete(s.retryAt, key)
+	s.mu.Unlock()
+
+	if s.store != nil {
+		if err := s.store.Save(key, state); err != nil {
+			s.logger.Error().
+				Str("provider", key).
+				Err(err).
+				Msg("Failed to persist sync watermark")
+		}
+	}
+
+	s.manager.updateSyncStatus(key, func(status *SyncStatus) {
+		status.Running = false
+		status.Watermark = state.Watermark
+		status.LastRunEntities = len(entities)
+		status.LastError = ""
+		status.ConsecutiveFailures = 0
+		if full {
+			status.LastFullSync = completed
+		} else {
+			status.LastIncrementalSync = completed
+		}
+	})
+
+	s.logger.Info().
+		Str("provider", key).
+		Str("mode", mode).
+		Int("count", len(entities)).
+		Dur("duration", completed.Sub(start)).
+		Msg("Provider sync completed")
+
+	return nil
+}
+
+// recordHealth counts the outcome of a run towards the health of an instance
+// and logs circuit transitions.
+func (s *Syncer) recordHealth(key string, generation uint64, err error) {
+	opened, closed := s.manager.recordHealth(key, generation, err, s.threshold)
+	switch {
+	case opened:
+		s.logger.Warn().
+			Str("provider", key).
+			Err(err).
+			Msg("Provider sync keeps failing, removed from federation")
+	case closed:
+		s.logger.Info().
+			Str("provider", key).
+			Msg("Provider synced again, restored to federation")
+	}
+}
+
+// restore applies the persisted state of an instance to the manager the first
+// time the instance is seen, so status and LastDiscovery survive restarts.
+func (s *Syncer) restore(key string) {
+	s.mu.Lock()
+	if s.restored[key] {
+		s.mu.Unlock()
+		return
+	}
+	s.restored[key] = true
+	state, ok := s.states[key]
+	s.mu.Unlock()
+
+	if ok {
+		s.manager.restoreSyncState(key, state)
+	}
+}
+
+// markRunning marks an instance as running. Returns false if it already was.
+func (s *Syncer) markRunning(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[key] {
+		return false
+	}
+	s.running[key] = true
+	return true
+}
+
+// clearRunning clears the running flag for an instance.
+func (s *Syncer) clearRunning(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.running, key)
+}
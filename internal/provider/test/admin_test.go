@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// blockingProvider is a mock provider whose searches block until release is closed.
+type blockingProvider struct {
+	*mock.MockProvider
+	started  chan struct{}
+	release  chan struct{}
+	shutdown chan struct{}
+}
+
+func (p *blockingProvider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	close(p.started)
+	<-p.release
+	return p.MockProvider.Search(ctx, query)
+}
+
+func (p *blockingProvider) Shutdown(ctx context.Context) error {
+	close(p.shutdown)
+	return nil
+}
+
+// TestManager_Reconfigure tests that an updated instance is swapped in while
+// the old one drains its in-flight search, and that Reload adds and removes
+// instances to match the given list.
+func TestManager_Reconfigure(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	var created []*blockingProvider
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:         "mock",
+		ConfigSchema: mock.NewMockProvider().Metadata().ConfigSchema,
+		Factory: func() provider.Provider {
+			p := &blockingProvider{
+				MockProvider: mock.NewMockProvider(),
+				started:      make(chan struct{}),
+				release:      make(chan struct{}),
+				shutdown:     make(chan struct{}),
+			}
+			created = append(created, p)
+			return p
+		},
+	}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	manager := provider.NewManager(registry, &logger)
+	if err := manager.AddInstance(ctx, provider.InstanceConfig{Type: "mock", InstanceID: "a"}); err != nil {
+		t.Fatalf("AddInstance failed: %v", err)
+	}
+	err := manager.AddInstance(ctx, provider.InstanceConfig{Type: "mock", InstanceID: "a"})
+	if !errors.Is(err, provider.ErrInstanceExists) {
+		t.Errorf("Expected ErrInstanceExists, got %v", err)
+	}
+	err = manager.AddInstance(ctx, provider.InstanceConfig{Type: "mock", InstanceID: "b", Config: map[string]any{"entity_count": "x"}})
+	if !errors.Is(err, provider.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig, got %v", err)
+	}
+
+	// Start a search on the original instance and keep it in flight
+	old := created[0]
+	prov, _ := manager.Get("mock:a")
+	searchDone := make(chan struct{})
+	go func() {
+		_, _ = prov.Search(ctx, provider.SearchQuery{})
+		close(searchDone)
+	}()
+	<-old.started
+
+	updated := make(chan error, 1)
+	go func() {
+		updated <- manager.UpdateInstance(ctx, provider.InstanceConfig{
+			Type:       "mock",
+			InstanceID: "a",
+			Config:     map[string]any{"entity_count": float64(3)},
+		})
+	}()
+
+	// The update must wait for the in-flight search before shutting down the old provider
+	select {
+	case <-old.shutdown:
+		t.Fatal("Old provider shut down with a search in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(old.release)
+	if err := <-updated; err != nil {
+		t.Fatalf("UpdateInstance failed: %v", err)
+	}
+	<-searchDone
+	<-old.shutdown
+
+	// The whole-number float from JSON reaches the provider as an int
+	entities, err := manager.Discover(ctx, "mock:a")
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(entities) != 3 {
+		t.Errorf("Expected the updated instance to return 3 entities, got %d", len(entities))
+	}
+
+	result, err := manager.Reload(ctx, []provider.InstanceConfig{
+		{Type: "mock", InstanceID: "a", Config: map[string]any{"entity_count": 3}},
+		{Type: "mock", InstanceID: "b"},
+	})
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if len(result.Added) != 1 || len(result.Unchanged) != 1 || len(result.Updated) != 0 {
+		t.Errorf("Unexpected reload result: %+v", result)
+	}
+
+	result, err = manager.Reload(ctx, []provider.InstanceConfig{{Type: "mock", InstanceID: "b"}})
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if len(result.Removed) != 1 || manager.IsConnected("mock:a") {
+		t.Errorf("Expected mock:a to be removed, got %+v", result)
+	}
+	if instances := manager.Instances(); len(instances) != 1 || instances[0].Key() != "mock:b" {
+		t.Errorf("Expected only mock:b to be configured, got %v", instances)
+	}
+}
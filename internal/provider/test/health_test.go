@@ -0,0 +1,149 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+)
+
+// checkedProvider is a mock provider with a switchable health check.
+type checkedProvider struct {
+	*mock.MockProvider
+	healthErr error
+}
+
+func (p *checkedProvider) Health(ctx context.Context) error {
+	return p.healthErr
+}
+
+// TestHealthMonitor_CircuitBreaker tests that an instance leaves federation after
+// FailureThreshold failed probes and comes back on the first successful probe.
+func TestHealthMonitor_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	checked := &checkedProvider{MockProvider: mock.NewMockProvider()}
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:    "mock",
+		Factory: func() provider.Provider { return checked },
+	}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+
+	manager := provider.NewManager(registry, &logger)
+	if err := manager.Initialize(ctx, "mock", map[string]any{"instance_id": "default"}); err != nil {
+		t.Fatalf("Failed to initialize provider: %v", err)
+	}
+
+	config := provider.DefaultHealthConfig()
+	config.FailureThreshold = 2
+	monitor := provider.NewHealthMonitor(manager, config, &logger)
+
+	checked.healthErr = errors.New("connection refused")
+	monitor.CheckNow(ctx)
+	if !manager.IsConnected("mock:default") {
+		t.Fatal("Expected instance to stay connected below the failure threshold")
+	}
+
+	monitor.CheckNow(ctx)
+	if manager.IsConnected("mock:default") {
+		t.Fatal("Expected instance to be disconnected after reaching the failure threshold")
+	}
+	status, _ := manager.GetStatus("mock:default")
+	if status.Health.State != provider.HealthStateUnhealthy {
+		t.Errorf("Expected health state %q, got %q", provider.HealthStateUnhealthy, status.Health.State)
+	}
+	if _, err := manager.Hydrate(ctx, "mock:default:entity:1"); !errors.Is(err, provider.ErrInstanceUnavailable) {
+		t.Errorf("Expected ErrInstanceUnavailable while the circuit is open, got %v", err)
+	}
+
+	checked.healthErr = nil
+	monitor.CheckNow(ctx)
+	if !manager.IsConnected("mock:default") {
+		t.Fatal("Expected instance to reconnect after a successful probe")
+	}
+}
+
+// stalledCheckProvider fails its health check once released.
+type stalledCheckProvider struct {
+	*mock.MockProvider
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *stalledCheckProvider) Health(ctx context.Context) error {
+	close(p.started)
+	<-p.release
+	return errors.New("connection refused")
+}
+
+// TestHealthMonitor_DropsReplacedProbe tests that an instance isn't shut down
+// during a probe, and that the probe's result doesn't apply to the instance
+// that replaces it.
+func TestHealthMonitor_DropsReplacedProbe(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	stalled := &stalledCheckProvider{
+		MockProvider: mock.NewMockProvider(),
+		started:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	created := 0
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name: "mock",
+		Factory: func() provider.Provider {
+			created++
+			if created == 1 {
+				return stalled
+			}
+			return mock.NewMockProvider()
+		},
+	}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	manager := provider.NewManager(registry, &logger)
+
+	inst := provider.InstanceConfig{Type: "mock", InstanceID: "default", Config: map[string]any{"entity_count": 5}}
+	if err := manager.AddInstance(ctx, inst); err != nil {
+		t.Fatalf("AddInstance failed: %v", err)
+	}
+
+	config := provider.DefaultHealthConfig()
+	config.FailureThreshold = 1
+	monitor := provider.NewHealthMonitor(manager, config, &logger)
+
+	checked := make(chan struct{})
+	go func() {
+		monitor.CheckNow(ctx)
+		close(checked)
+	}()
+	<-stalled.started
+
+	inst.Config = map[string]any{"entity_count": 3}
+	updated := make(chan error)
+	go func() {
+		updated <- manager.UpdateInstance(ctx, inst)
+	}()
+	select {
+	case err := <-updated:
+		t.Fatalf("Expected the update to wait for the probe, got %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(stalled.release)
+
+	<-checked
+	if err := <-updated; err != nil {
+		t.Fatalf("UpdateInstance failed: %v", err)
+	}
+	if !manager.IsConnected("mock:default") {
+		t.Error("Expected the failed probe of the old instance not to disconnect the new one")
+	}
+}
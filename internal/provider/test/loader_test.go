@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+)
+
+// TestInitializeInstances tests that provider list entries are validated against
+// the provider's config schema and initialized with schema defaults applied.
+func TestInitializeInstances(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	registry := provider.NewRegistry()
+	if err := registry.RegisterFactory(func() provider.Provider { return mock.NewMockProvider() }); err != nil {
+		t.Fatalf("RegisterFactory failed: %v", err)
+	}
+
+	invalid := map[string][]provider.InstanceConfig{
+		"unknown type":  {{Type: "nope", InstanceID: "a"}},
+		"missing id":    {{Type: "mock"}},
+		"unknown field": {{Type: "mock", InstanceID: "a", Config: map[string]any{"entity_cnt": 5}}},
+		"wrong type":    {{Type: "mock", InstanceID: "a", Config: map[string]any{"entity_count": "five"}}},
+		"bad policy":    {{Type: "mock", InstanceID: "a", Config: map[string]any{"timeout": "soon"}}},
+		"duplicate":     {{Type: "mock", InstanceID: "a"}, {Type: "mock", InstanceID: "a"}},
+	}
+	for name, instances := range invalid {
+		if err := provider.ValidateInstances(registry, instances); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+
+	manager := provider.NewManager(registry, &logger)
+	err := manager.InitializeInstances(ctx, []provider.InstanceConfig{
+		{Type: "mock", InstanceID: "a"},
+		{Type: "mock", InstanceID: "b", Config: map[string]any{"entity_count": 3, "retry_count": 0}},
+	})
+	if err != nil {
+		t.Fatalf("InitializeInstances failed: %v", err)
+	}
+
+	// The schema default entity_count is 10
+	entities, err := manager.Discover(ctx, "mock:a")
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(entities) != 10 {
+		t.Errorf("Expected default of 10 entities, got %d", len(entities))
+	}
+
+	entities, err = manager.Discover(ctx, "mock:b")
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(entities) != 3 {
+		t.Errorf("Expected 3 entities, got %d", len(entities))
+	}
+}
@@ -0,0 +1,213 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// newTestManager creates a manager with the mock provider registered.
+func newTestManager(t *testing.T) *provider.Manager {
+	t.Helper()
+
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:        "mock",
+		Description: "Mock provider for testing",
+		Factory:     func() provider.Provider { return mock.NewMockProvider() },
+	}); err != nil {
+		t.Fatalf("Failed to register mock provider: %v", err)
+	}
+
+	logger := zerolog.Nop()
+	return provider.NewManager(registry, &logger)
+}
+
+// TestManager_MultipleInstances tests that several instances of the same provider type can coexist.
+func TestManager_MultipleInstances(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestManager(t)
+
+	for _, instanceID := range []string{"docs", "media"} {
+		config := map[string]any{"instance_id": instanceID, "entity_count": 2}
+		if err := manager.Initialize(ctx, "mock", config); err != nil {
+			t.Fatalf("Failed to initialize instance %q: %v", instanceID, err)
+		}
+	}
+
+	keys := manager.List()
+	if len(keys) != 2 || keys[0] != "mock:docs" || keys[1] != "mock:media" {
+		t.Errorf("Expected [mock:docs mock:media], got %v", keys)
+	}
+
+	// Initializing the same instance twice should fail
+	if err := manager.Initialize(ctx, "mock", map[string]any{"instance_id": "docs"}); err == nil {
+		t.Error("Expected error when initializing duplicate instance")
+	}
+
+	// Status should report one entry per instance
+	statuses := manager.Status()
+	if len(statuses) != 2 {
+		t.Fatalf("Expected 2 statuses, got %d", len(statuses))
+	}
+	if statuses[0].ProviderType != "mock" || statuses[0].InstanceID != "docs" {
+		t.Errorf("Unexpected status: %+v", statuses[0])
+	}
+
+	// Each instance should be addressable by its key
+	prov, ok := manager.Get("mock:media")
+	if !ok {
+		t.Fatal("Expected to find mock:media")
+	}
+	if prov.InstanceID() != "media" {
+		t.Errorf("Expected instance ID media, got %s", prov.InstanceID())
+	}
+
+	// Shutting down one instance should leave the other running
+	if err := manager.Shutdown(ctx, "mock:docs"); err != nil {
+		t.Fatalf("Failed to shut down mock:docs: %v", err)
+	}
+	if manager.Count() != 1 {
+		t.Errorf("Expected 1 remaining instance, got %d", manager.Count())
+	}
+}
+
+// TestManager_RequiresInstanceID tests that instances must declare an instance_id.
+func TestManager_RequiresInstanceID(t *testing.T) {
+	manager := newTestManager(t)
+
+	if err := manager.Initialize(context.Background(), "mock", map[string]any{}); err == nil {
+		t.Error("Expected error when instance_id is missing")
+	}
+}
+
+// TestManager_HydrateRoutesByEntityID tests that Hydrate and GetRelated go to the owning instance.
+func TestManager_HydrateRoutesByEntityID(t *testing.T) {
+	ctx := context.Background()
+	manager := newTestManager(t)
+
+	for _, instanceID := range []string{"a", "b"} {
+		config := map[string]any{"instance_id": instanceID, "entity_count": 3}
+		if err := manager.Initialize(ctx, "mock", config); err != nil {
+			t.Fatalf("Failed to initialize instance %q: %v", instanceID, err)
+		}
+	}
+
+	id := provider.BuildEntityID("mock", "b", "entity:1").String()
+	entity, err := manager.Hydrate(ctx, id)
+	if err != nil {
+		t.Fatalf("Hydrate failed: %v", err)
+	}
+	if entity.ID != id {
+		t.Errorf("Expected entity %s, got %s", id, entity.ID)
+	}
+
+	if _, err := manager.GetRelated(ctx, id, ""); err != nil {
+		t.Errorf("GetRelated failed: %v", err)
+	}
+
+	// Unknown instance should produce a distinct error
+	_, err = manager.Hydrate(ctx, "mock:missing:entity:1")
+	if !errors.Is(err, provider.ErrUnknownInstance) {
+		t.Errorf("Expected ErrUnknownInstance, got %v", err)
+	}
+
+	// Missing entity on a known instance should be ErrNotFound
+	_, err = manager.Hydrate(ctx, "mock:a:entity:99")
+	if !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+// definingProvider contributes the given type definitions.
+type definingProvider struct {
+	*mock.MockProvider
+	defs []types.TypeDefinition
+}
+
+func (p *definingProvider) TypeDefinitions() []types.TypeDefinition {
+	return p.defs
+}
+
+// TestManager_RegistersProviderTypes tests that provider type definitions are
+// registered at initialization and that conflicting ones are rejected.
+func TestManager_RegistersProviderTypes(t *testing.T) {
+	ctx := context.Background()
+
+	tracker := []types.TypeDefinition{
+		{Name: "code.tracker.issue", Parent: "code.tracker"},
+		{Name: "code.tracker", Parent: types.TypeItem, Attributes: map[string]types.AttributeDef{
+			"state": {Name: "state", Type: types.AttributeTypeString, Filterable: true},
+		}},
+	}
+	conflicting := []types.TypeDefinition{
+		{Name: "code.tracker", Parent: types.TypeItem, Attributes: map[string]types.AttributeDef{
+			"state": {Name: "state", Type: types.AttributeTypeInt},
+		}},
+	}
+	retyped := []types.TypeDefinition{
+		{Name: "code.board", Parent: types.TypeItem, Attributes: map[string]types.AttributeDef{
+			"state": {Name: "state", Type: types.AttributeTypeInt},
+		}},
+	}
+	definitions := map[string][]types.TypeDefinition{
+		"tracker":  tracker,
+		"tracker2": tracker,
+		"other":    conflicting,
+		"board":    retyped,
+	}
+
+	registry := provider.NewRegistry()
+	for name, defs := range definitions {
+		if err := registry.Register(provider.ProviderMetadata{
+			Name:    name,
+			Factory: func() provider.Provider { return &definingProvider{MockProvider: mock.NewMockProvider(), defs: defs} },
+		}); err != nil {
+			t.Fatalf("Failed to register %s provider: %v", name, err)
+		}
+	}
+	typeRegistry := types.NewTypeRegistry()
+	types.RegisterCoreTypes(typeRegistry)
+	logger := zerolog.Nop()
+	manager := provider.NewManager(registry, &logger)
+	manager.SetTypeRegistry(typeRegistry)
+
+	if err := manager.Initialize(ctx, "tracker", map[string]any{"instance_id": "a"}); err != nil {
+		t.Fatalf("Failed to initialize tracker: %v", err)
+	}
+	if !typeRegistry.IsA("code.tracker.issue", types.TypeItem) {
+		t.Error("Expected code.tracker.issue to be registered under item")
+	}
+	if owner := typeRegistry.Owner("code.tracker"); owner != "tracker" {
+		t.Errorf("Expected code.tracker to be owned by tracker, got %q", owner)
+	}
+
+	// The same definitions from another provider are accepted
+	if err := manager.Initialize(ctx, "tracker2", map[string]any{"instance_id": "b"}); err != nil {
+		t.Errorf("Expected identical definitions to be accepted, got %v", err)
+	}
+
+	err := manager.Initialize(ctx, "other", map[string]any{"instance_id": "c"})
+	if !errors.Is(err, provider.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for conflicting definitions, got %v", err)
+	}
+	if manager.IsConnected("other:c") {
+		t.Error("Expected the conflicting instance not to be connected")
+	}
+	if attr := typeRegistry.Get("code.tracker").Attributes["state"]; attr.Type != types.AttributeTypeString {
+		t.Errorf("Expected the original definition to be kept, got %v", attr.Type)
+	}
+
+	// An attribute keeps its type across differently named types too
+	if err := manager.Initialize(ctx, "board", map[string]any{"instance_id": "d"}); !errors.Is(err, provider.ErrInvalidConfig) {
+		t.Errorf("Expected ErrInvalidConfig for a retyped attribute, got %v", err)
+	}
+	if attr := typeRegistry.GetAllAttributes()["state"]; attr.Type != types.AttributeTypeString {
+		t.Errorf("Expected state to stay a string, got %v", attr.Type)
+	}
+}
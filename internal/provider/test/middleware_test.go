@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// flakyProvider fails the first failures Search calls with the given error.
+type flakyProvider struct {
+	*mock.MockProvider
+	failures int
+	err      error
+	calls    int
+}
+
+func (p *flakyProvider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		return nil, p.err
+	}
+	return p.MockProvider.Search(ctx, query)
+}
+
+// TestMiddleware_RetryAndCache tests that temporary errors are retried,
+// other errors are not, and cached searches don't reach the provider or
+// share entities with callers.
+func TestMiddleware_RetryAndCache(t *testing.T) {
+	ctx := context.Background()
+
+	inner := mock.NewMockProvider()
+	if err := inner.Initialize(ctx, map[string]any{"instance_id": "default", "entity_count": 3}); err != nil {
+		t.Fatalf("Failed to initialize mock provider: %v", err)
+	}
+
+	flaky := &flakyProvider{MockProvider: inner, failures: 2, err: provider.ErrTemporary}
+	config, err := provider.ProviderConfigFromMap(map[string]any{"retry_count": 2, "enable_caching": true, "cache_ttl": "1m"})
+	if err != nil {
+		t.Fatalf("ProviderConfigFromMap failed: %v", err)
+	}
+	prov := provider.Chain(flaky, config.Middleware()...)
+
+	results, err := prov.Search(ctx, provider.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Expected search to succeed after retries, got %v", err)
+	}
+	if flaky.calls != 3 {
+		t.Errorf("Expected 3 calls (2 retries), got %d", flaky.calls)
+	}
+
+	// Modifying the results doesn't modify the cache
+	results[0].Attributes["modified"] = true
+
+	cached, err := prov.Search(ctx, provider.SearchQuery{})
+	if err != nil {
+		t.Fatalf("Cached search failed: %v", err)
+	}
+	if flaky.calls != 3 {
+		t.Errorf("Expected cached search not to call the provider, got %d calls", flaky.calls)
+	}
+	if _, ok := cached[0].Attributes["modified"]; ok {
+		t.Error("Expected cached results not to share attributes with earlier results")
+	}
+
+	if provider.Unwrap(prov) != flaky {
+		t.Error("Expected Unwrap to return the innermost provider")
+	}
+
+	// Auth errors are not retried
+	failing := &flakyProvider{MockProvider: inner, failures: 10, err: provider.ErrAuthenticationFailed}
+	prov = provider.Chain(failing, provider.RetryMiddleware(3))
+	if _, err := prov.Search(ctx, provider.SearchQuery{}); err == nil {
+		t.Fatal("Expected auth error")
+	}
+	if failing.calls != 1 {
+		t.Errorf("Expected auth error not to be retried, got %d calls", failing.calls)
+	}
+}
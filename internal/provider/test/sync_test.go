@@ -0,0 +1,295 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// TestSyncer_PersistsWatermark tests that a sync run persists the watermark and
+// that a new syncer restores it into the instance status.
+func TestSyncer_PersistsWatermark(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	statePath := filepath.Join(t.TempDir(), "sync-state.json")
+
+	manager := newTestManager(t)
+	if err := manager.Initialize(ctx, "mock", map[string]any{"instance_id": "default", "entity_count": 5}); err != nil {
+		t.Fatalf("Failed to initialize mock provider: %v", err)
+	}
+
+	syncer := provider.NewSyncer(manager, provider.DefaultSyncConfig(), provider.NewFileWatermarkStore(statePath), &logger)
+
+	received := 0
+	syncer.OnSync(func(ctx context.Context, key string, entities []types.Entity, full bool) error {
+		received += len(entities)
+		return nil
+	})
+
+	if err := syncer.SyncNow(ctx, "mock:default", true); err != nil {
+		t.Fatalf("SyncNow failed: %v", err)
+	}
+	if received != 5 {
+		t.Errorf("Expected handler to receive 5 entities, got %d", received)
+	}
+
+	status, ok := manager.GetStatus("mock:default")
+	if !ok {
+		t.Fatal("Expected status for mock:default")
+	}
+	if status.Sync.Watermark.IsZero() || status.Sync.LastFullSync.IsZero() {
+		t.Errorf("Expected watermark and last full sync to be set, got %+v", status.Sync)
+	}
+	if status.Sync.Running || status.Sync.LastError != "" {
+		t.Errorf("Unexpected sync status after success: %+v", status.Sync)
+	}
+
+	// A fresh store should load the persisted watermark
+	states, err := provider.NewFileWatermarkStore(statePath).Load()
+	if err != nil {
+		t.Fatalf("Failed to load sync state: %v", err)
+	}
+	state, ok := states["mock:default"]
+	if !ok {
+		t.Fatal("Expected persisted state for mock:default")
+	}
+	if !state.Watermark.Equal(status.Sync.Watermark) {
+		t.Errorf("Expected persisted watermark %v, got %v", status.Sync.Watermark, state.Watermark)
+	}
+}
+
+// TestSyncer_ResetOnReconfigure tests that changing or removing an instance
+// forgets its sync state, so the next sync is a full one.
+func TestSyncer_ResetOnReconfigure(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	statePath := filepath.Join(t.TempDir(), "sync-state.json")
+
+	manager := newTestManager(t)
+	syncer := provider.NewSyncer(manager, provider.DefaultSyncConfig(), provider.NewFileWatermarkStore(statePath), &logger)
+	manager.OnReset(syncer.Reset)
+
+	persisted := func() bool {
+		t.Helper()
+		states, err := provider.NewFileWatermarkStore(statePath).Load()
+		if err != nil {
+			t.Fatalf("Failed to load sync state: %v", err)
+		}
+		_, ok := states["mock:default"]
+		return ok
+	}
+	synced := func() bool {
+		t.Helper()
+		if err := syncer.SyncNow(ctx, "mock:default", true); err != nil {
+			t.Fatalf("SyncNow failed: %v", err)
+		}
+		return persisted()
+	}
+
+	inst := provider.InstanceConfig{Type: "mock", InstanceID: "default", Config: map[string]any{"entity_count": 5}}
+	if err := manager.AddInstance(ctx, inst); err != nil {
+		t.Fatalf("AddInstance failed: %v", err)
+	}
+	if !synced() {
+		t.Fatal("Expected persisted state after a sync")
+	}
+
+	// The same configuration keeps the sync state
+	if err := manager.UpdateInstance(ctx, inst); err != nil {
+		t.Fatalf("UpdateInstance failed: %v", err)
+	}
+	if !persisted() {
+		t.Error("Expected an unchanged instance to keep its sync state")
+	}
+
+	inst.Config = map[string]any{"entity_count": 3}
+	if err := manager.UpdateInstance(ctx, inst); err != nil {
+		t.Fatalf("UpdateInstance failed: %v", err)
+	}
+	if persisted() {
+		t.Error("Expected a reconfigured instance to lose its sync state")
+	}
+	if status, _ := manager.GetStatus("mock:default"); !status.Sync.LastFullSync.IsZero() {
+		t.Errorf("Expected no last full sync after reconfiguring, got %v", status.Sync.LastFullSync)
+	}
+
+	if !synced() {
+		t.Fatal("Expected persisted state after a sync")
+	}
+	if err := manager.RemoveInstance(ctx, "mock:default"); err != nil {
+		t.Fatalf("RemoveInstance failed: %v", err)
+	}
+	if persisted() {
+		t.Error("Expected a removed instance to lose its sync state")
+	}
+}
+
+// unreachableProvider fails discovery while fail is set, and counts its runs.
+type unreachableProvider struct {
+	*mock.MockProvider
+	fail atomic.Bool
+	runs atomic.Int32
+}
+
+func (p *unreachableProvider) Discover(ctx context.Context) ([]types.Entity, error) {
+	p.runs.Add(1)
+	if p.fail.Load() {
+		return nil, errors.New("backend unavailable")
+	}
+	return p.MockProvider.Discover(ctx)
+}
+
+// TestSyncer_Failures tests that failed runs count towards the failure
+// threshold instead of disconnecting the instance right away, and that the
+// scheduler backs off instead of retrying on every tick.
+func TestSyncer_Failures(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	backend := &unreachableProvider{MockProvider: mock.NewMockProvider()}
+	backend.fail.Store(true)
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:    "unreachable",
+		Factory: func() provider.Provider { return backend },
+	}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	manager := provider.NewManager(registry, &logger)
+	if err := manager.Initialize(ctx, "unreachable", map[string]any{"instance_id": "a"}); err != nil {
+		t.Fatalf("Failed to initialize provider: %v", err)
+	}
+
+	config := provider.DefaultSyncConfig()
+	config.FullInterval = 10 * time.Millisecond
+	syncer := provider.NewSyncer(manager, config, nil, &logger)
+	syncer.SetFailureThreshold(2)
+
+	connected := func() bool {
+		status, _ := manager.GetStatus("unreachable:a")
+		return status.Connected
+	}
+
+	// The first scheduled run fails, and isn't retried on the next ticks
+	if err := syncer.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	syncer.Stop()
+	if runs := backend.runs.Load(); runs != 1 {
+		t.Errorf("Expected one run while backing off, got %d", runs)
+	}
+	if !connected() {
+		t.Error("Expected the instance to stay connected below the failure threshold")
+	}
+
+	if err := syncer.SyncNow(ctx, "unreachable:a", true); err == nil {
+		t.Fatal("Expected the sync to fail")
+	}
+	if connected() {
+		t.Error("Expected the instance to be disconnected at the failure threshold")
+	}
+
+	backend.fail.Store(false)
+	if err := syncer.SyncNow(ctx, "unreachable:a", true); err != nil {
+		t.Fatalf("SyncNow failed: %v", err)
+	}
+	if !connected() {
+		t.Error("Expected the instance to be reconnected after a successful sync")
+	}
+}
+
+// stalledProvider holds discovery until released.
+type stalledProvider struct {
+	*mock.MockProvider
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *stalledProvider) Discover(ctx context.Context) ([]types.Entity, error) {
+	close(p.started)
+	<-p.release
+	return p.MockProvider.Discover(ctx)
+}
+
+// TestSyncer_DropsReplacedRun tests that a run still in progress on an
+// instance that is reconfigured doesn't refill the data the reset dropped.
+func TestSyncer_DropsReplacedRun(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+	statePath := filepath.Join(t.TempDir(), "sync-state.json")
+
+	stalled := &stalledProvider{
+		MockProvider: mock.NewMockProvider(),
+		started:      make(chan struct{}),
+		release:      make(chan struct{}),
+	}
+	created := 0
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name: "mock",
+		Factory: func() provider.Provider {
+			created++
+			if created == 1 {
+				return stalled
+			}
+			return mock.NewMockProvider()
+		},
+	}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	manager := provider.NewManager(registry, &logger)
+	syncer := provider.NewSyncer(manager, provider.DefaultSyncConfig(), provider.NewFileWatermarkStore(statePath), &logger)
+	manager.OnReset(syncer.Reset)
+
+	received := 0
+	syncer.OnSync(func(ctx context.Context, key string, entities []types.Entity, full bool) error {
+		received += len(entities)
+		return nil
+	})
+
+	inst := provider.InstanceConfig{Type: "mock", InstanceID: "default", Config: map[string]any{"entity_count": 5}}
+	if err := manager.AddInstance(ctx, inst); err != nil {
+		t.Fatalf("AddInstance failed: %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- syncer.SyncNow(ctx, "mock:default", true)
+	}()
+	<-stalled.started
+
+	// The old instance is drained only once its discovery returns
+	inst.Config = map[string]any{"entity_count": 3}
+	updated := make(chan error)
+	go func() {
+		updated <- manager.UpdateInstance(ctx, inst)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(stalled.release)
+
+	if err := <-done; err != nil {
+		t.Fatalf("SyncNow failed: %v", err)
+	}
+	if err := <-updated; err != nil {
+		t.Fatalf("UpdateInstance failed: %v", err)
+	}
+	if received != 0 {
+		t.Errorf("Expected the replaced run not to reach the handlers, got %d entities", received)
+	}
+	states, err := provider.NewFileWatermarkStore(statePath).Load()
+	if err != nil {
+		t.Fatalf("Failed to load sync state: %v", err)
+	}
+	if _, ok := states["mock:default"]; ok {
+		t.Error("Expected the replaced run not to persist a watermark")
+	}
+}
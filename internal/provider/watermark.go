@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SyncState is the persisted sync state of a single provider instance.
+type SyncState struct {
+	// Watermark is the high-water mark for the next incremental sync
+	Watermark time.Time `json:"watermark"`
+
+	// LastFullSync is the completion time of the last successful full sync
+	LastFullSync time.Time `json:"last_full_sync"`
+}
+
+// WatermarkStore persists per-instance sync state across restarts.
+// Keys are instance keys ("providerType:instanceID").
+type WatermarkStore interface {
+	// Load returns the persisted state for all instances.
+	Load() (map[string]SyncState, error)
+
+	// Save persists the state for a single instance.
+	Save(key string, state SyncState) error
+
+	// Delete forgets the state of a single instance.
+	Delete(key string) error
+}
+
+// FileWatermarkStore is a WatermarkStore backed by a JSON file on local disk.
+type FileWatermarkStore struct {
+	mu     sync.Mutex
+	path   string
+	states map[string]SyncState
+}
+
+// NewFileWatermarkStore creates a watermark store that reads and writes the given file.
+// The file and its parent directory are created on first save.
+func NewFileWatermarkStore(path string) *FileWatermarkStore {
+	return &FileWatermarkStore{
+		path:   path,
+		states: make(map[string]SyncState),
+	}
+}
+
+// Load reads the state file. A missing file is not an error.
+func (s *FileWatermarkStore) Load() (map[string]SyncState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return make(map[string]SyncState), nil
+		}
+		return nil, fmt.Errorf("failed to read sync state: %w", err)
+	}
+
+	states := make(map[string]SyncState)
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state %s: %w", s.path, err)
+	}
+	s.states = states
+
+	// Return a copy so callers can't mutate the cached state
+	result := make(map[string]SyncState, len(states))
+	for k, v := range states {
+		result[k] = v
+	}
+	return result, nil
+}
+
+// Save updates the state for one instance and rewrites the file atomically.
+func (s *FileWatermarkStore) Save(key string, state SyncState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[key] = state
+	return s.write()
+}
+
+// Delete removes the state of one instance and rewrites the file atomically.
+func (s *FileWatermarkStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.states[key]; !ok {
+		return nil
+	}
+	delete(s.states, key)
+	return s.write()
+}
+
+// write rewrites the state file. The caller must hold mu.
+func (s *FileWatermarkStore) write() error {
+	data, err := json.MarshalIndent(s.states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode sync state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create sync state directory: %w", err)
+	}
+
+	// Write to a temp file and rename so a crash never leaves a truncated file
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sync state: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace sync state: %w", err)
+	}
+
+	return nil
+}
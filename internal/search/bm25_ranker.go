@@ -0,0 +1,234 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/yourname/mifind/internal/types"
+)
+
+// Fields scored by the BM25 ranker, as used in BM25Config.FieldBoosts.
+const (
+	FieldTitle        = "title"
+	FieldDescription  = "description"
+	FieldSearchTokens = "search_tokens"
+	FieldAttributes   = "attributes"
+)
+
+// bm25Fields are the scored fields in a fixed order.
+var bm25Fields = []string{FieldTitle, FieldDescription, FieldSearchTokens, FieldAttributes}
+
+// BM25Config configures the BM25 ranking strategy.
+type BM25Config struct {
+	// K1 controls how quickly repeated terms stop adding to the score
+	K1 float64 `mapstructure:"k1"`
+
+	// B controls how strongly scores are normalized by field length (0-1)
+	B float64 `mapstructure:"b"`
+
+	// FieldBoosts weights term occurrences by field: title, description,
+	// search_tokens and attributes (string attribute values)
+	FieldBoosts map[string]float64 `mapstructure:"field_boosts"`
+}
+
+// DefaultBM25Config returns the default BM25 configuration.
+func DefaultBM25Config() BM25Config {
+	return BM25Config{
+		K1: 1.2,
+		B:  0.75,
+		FieldBoosts: map[string]float64{
+			FieldTitle:        3.0,
+			FieldDescription:  1.0,
+			FieldSearchTokens: 2.0,
+			FieldAttributes:   0.5,
+		},
+	}
+}
+
+// BM25Ranker ranks entities by BM25F text relevance over their title,
+// description, search tokens and string attributes. Corpus statistics
+// (document frequencies, average field lengths) are computed per query over
+// the federated result set, so scores don't depend on any index. Type and
+// provider weights and recency are added as in the InMemoryRanker.
+type BM25Ranker struct {
+	config RankingConfig
+	base   *InMemoryRanker
+}
+
+// NewBM25Ranker creates a new BM25 ranker with the given config. Unset BM25
+// parameters take their defaults.
+func NewBM25Ranker(config RankingConfig) *BM25Ranker {
+	defaults := DefaultBM25Config()
+	if config.BM25.K1 <= 0 {
+		config.BM25.K1 = defaults.K1
+	}
+	if config.BM25.B < 0 || config.BM25.B > 1 {
+		config.BM25.B = defaults.B
+	}
+	boosts := make(map[string]float64, len(defaults.FieldBoosts))
+	for field, boost := range defaults.FieldBoosts {
+		boosts[field] = boost
+	}
+	for field, boost := range config.BM25.FieldBoosts {
+		boosts[field] = boost
+	}
+	config.BM25.FieldBoosts = boosts
+
+	return &BM25Ranker{
+		config: config,
+		base:   NewInMemoryRanker(config),
+	}
+}
+
+// Name returns the name of this ranking strategy.
+func (r *BM25Ranker) Name() string {
+	return "bm25"
+}
+
+// bm25Document holds the term frequencies and lengths of an entity's fields.
+type bm25Document struct {
+	frequencies [4]map[string]int
+	lengths     [4]int
+}
+
+// Rank scores and orders entities by BM25 relevance to the query text.
+func (r *BM25Ranker) Rank(ctx context.Context, entities []EntityWithProvider, query SearchQuery) ([]RankedEntity, error) {
+	queryTerms := uniqueTerms(tokenize(query.Query))
+
+	// Corpus statistics of the result set
+	docs := make([]bm25Document, len(entities))
+	var totalLengths [4]float64
+	documentFrequency := make(map[string]int, len(queryTerms))
+	for i, entity := range entities {
+		docs[i] = newBM25Document(entity.Entity)
+		for field := range bm25Fields {
+			totalLengths[field] += float64(docs[i].lengths[field])
+		}
+		for _, term := range queryTerms {
+			for field := range bm25Fields {
+				if docs[i].frequencies[field][term] > 0 {
+					documentFrequency[term]++
+					break
+				}
+			}
+		}
+	}
+
+	var avgLengths [4]float64
+	for field := range bm25Fields {
+		if len(entities) > 0 {
+			avgLengths[field] = totalLengths[field] / float64(len(entities))
+		}
+	}
+
+	// Text scores are normalized by the best match, like the in-memory text score
+	textScores := make([]float64, len(entities))
+	maxScore := 0.0
+	for i := range entities {
+		textScores[i] = r.score(docs[i], queryTerms, documentFrequency, avgLengths, len(entities))
+		maxScore = math.Max(maxScore, textScores[i])
+	}
+
+	ranked := make([]RankedEntity, len(entities))
+	for i, entity := range entities {
+		text := 0.5 // Neutral score for empty queries, as in the in-memory ranker
+		if len(queryTerms) > 0 {
+			text = 0
+			if maxScore > 0 {
+				text = textScores[i] / maxScore
+			}
+		}
+		ranked[i] = RankedEntity{
+			Entity:   entity.Entity,
+			Score:    text + r.base.boostScore(entity, query),
+			Provider: entity.Provider,
+		}
+	}
+
+	deduped := r.base.deduplicate(ranked)
+	sort.Slice(deduped, func(i, j int) bool {
+		if deduped[i].Score != deduped[j].Score {
+			return deduped[i].Score > deduped[j].Score
+		}
+		return deduped[i].Entity.Timestamp.After(deduped[j].Entity.Timestamp)
+	})
+
+	return deduped, nil
+}
+
+// score computes the BM25F score of a document: term frequencies are
+// normalized by field length and weighted by field boost before saturation.
+func (r *BM25Ranker) score(doc bm25Document, terms []string, documentFrequency map[string]int, avgLengths [4]float64, corpusSize int) float64 {
+	k1, b := r.config.BM25.K1, r.config.BM25.B
+
+	score := 0.0
+	for _, term := range terms {
+		df := documentFrequency[term]
+		if df == 0 {
+			continue
+		}
+
+		weighted := 0.0
+		for field, name := range bm25Fields {
+			tf := doc.frequencies[field][term]
+			if tf == 0 {
+				continue
+			}
+			norm := 1.0
+			if avgLengths[field] > 0 {
+				norm = 1 - b + b*float64(doc.lengths[field])/avgLengths[field]
+			}
+			weighted += r.config.BM25.FieldBoosts[name] * float64(tf) / norm
+		}
+
+		idf := math.Log(1 + (float64(corpusSize)-float64(df)+0.5)/(float64(df)+0.5))
+		score += idf * weighted / (k1 + weighted)
+	}
+	return score
+}
+
+// newBM25Document tokenizes the scored fields of an entity.
+func newBM25Document(entity types.Entity) bm25Document {
+	var attributes []string
+	for _, value := range entity.Attributes {
+		switch v := value.(type) {
+		case string:
+			attributes = append(attributes, v)
+		case []string:
+			attributes = append(attributes, v...)
+		}
+	}
+
+	texts := [4]string{
+		entity.Title,
+		entity.Description,
+		strings.Join(entity.SearchTokens, " "),
+		strings.Join(attributes, " "),
+	}
+
+	var doc bm25Document
+	for field, text := range texts {
+		terms := tokenize(text)
+		doc.lengths[field] = len(terms)
+		doc.frequencies[field] = make(map[string]int, len(terms))
+		for _, term := range terms {
+			doc.frequencies[field][term]++
+		}
+	}
+	return doc
+}
+
+// uniqueTerms returns terms without duplicates, in order.
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
@@ -2,12 +2,17 @@ package search
 
 import (
 	"context"
-	"fmt"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/search/filters"
 	"github.com/yourname/mifind/internal/types"
 )
 
@@ -17,6 +22,87 @@ type Federator struct {
 	ranker  RankingStrategy
 	logger  *zerolog.Logger
 	timeout time.Duration
+	cache   EntityCache
+	filters *Filters
+
+	// types resolves type ancestry when routing typed queries
+	types *types.TypeRegistry
+
+	// softDefault is the soft deadline of instances without their own
+	softDefault time.Duration
+
+	// late holds the results of late providers by token, for CollectLate
+	lateTTL time.Duration
+	lateMu  sync.Mutex
+	late    map[string]*lateResults
+
+	// snapshots holds ranked result sets by token, for cursor pagination
+	snapshots  *snapshotStore
+	pageWindow int
+
+	// vocabulary holds the words of recently seen entities, for spelling
+	// suggestions (nil = no suggestions)
+	vocabulary *vocabulary
+}
+
+// Result sources reported in FederatedResult.Source and RankedEntity.Source.
+const (
+	// SourceLive marks results returned by the provider itself
+	SourceLive = "live"
+
+	// SourceCached marks results served from the local entity store
+	SourceCached = "cached"
+)
+
+// EntityCache is a local store of previously discovered entities.
+// The federator falls back to it when a provider instance is unavailable,
+// fails or times out.
+type EntityCache interface {
+	// Search returns stored entities of an instance matching the query text and type.
+	Search(instanceKey string, query provider.SearchQuery) ([]types.Entity, error)
+}
+
+// FederatorConfig configures federated search.
+type FederatorConfig struct {
+	// Timeout is the hard limit for provider searches
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// SoftDeadline is how long a search waits for a provider instance before
+	// answering without it. Instances can override it with soft_deadline.
+	SoftDeadline time.Duration `mapstructure:"soft_deadline"`
+
+	// LateResultsTTL is how long results collected from late providers are
+	// kept for a follow-up request
+	LateResultsTTL time.Duration `mapstructure:"late_results_ttl"`
+
+	// SnapshotTTL is how long the ranked results of a search are kept for
+	// cursor pagination after they were last read
+	SnapshotTTL time.Duration `mapstructure:"snapshot_ttl"`
+
+	// MaxSnapshots is how many searches keep their ranked results for cursor
+	// pagination; the least recently read are dropped first (0 = none)
+	MaxSnapshots int `mapstructure:"max_snapshots"`
+
+	// PageWindow is how many results are requested from each provider for
+	// the first pages of a search; later pages fetch more (0 = all at once)
+	PageWindow int `mapstructure:"page_window"`
+
+	// VocabularySize is how many words of recently seen entity titles and
+	// search tokens are kept for "did you mean" suggestions (0 = none)
+	VocabularySize int `mapstructure:"vocabulary_size"`
+}
+
+// DefaultFederatorConfig returns the default federated search configuration.
+func DefaultFederatorConfig() FederatorConfig {
+	return FederatorConfig{
+		Timeout:        30 * time.Second,
+		SoftDeadline:   5 * time.Second,
+		LateResultsTTL: time.Minute,
+		SnapshotTTL:    5 * time.Minute,
+		MaxSnapshots:   1000,
+		PageWindow:     100,
+		VocabularySize: 10000,
+	}
 }
 
 // NewFederator creates a new search federator.
@@ -29,12 +115,35 @@ func NewFederator(manager *provider.Manager, ranker RankingStrategy, logger *zer
 		ranker:  ranker,
 		logger:  logger,
 		timeout: timeout,
+		filters: NewFilters(nil),
+		types:   types.NewTypeRegistry(),
+		lateTTL: time.Minute,
+		late:    make(map[string]*lateResults),
+
+		snapshots:  newSnapshotStore(5*time.Minute, 1000),
+		pageWindow: 100,
+
+		vocabulary: newVocabulary(10000),
 	}
 }
 
-// FederatedResult contains results from a single provider.
+// FederatedResult contains results from a single provider instance.
 type FederatedResult struct {
-	Provider   string
+	// Provider is the instance key ("providerType:instanceID")
+	Provider string
+
+	// Source is SourceLive, or SourceCached when the live search failed and
+	// the entities were served from the local entity store
+	Source string
+
+	// Skipped is set when the provider doesn't support any of the query's filters
+	Skipped bool
+
+	// HasMore is set when the provider returned as many results as the query's
+	// limit, so it may have more beyond them, or when results left after the
+	// residual filters go beyond the page
+	HasMore bool
+
 	Entities   []types.Entity
 	Error      error
 	Duration   time.Duration
@@ -49,18 +158,124 @@ type FederatedResponse struct {
 	TypeCounts     map[string]int
 	HasErrors      bool
 	Duration       time.Duration
+
+	// Reports describes how each provider instance fared
+	Reports []ProviderReport
+
+	// LateToken is set when results of late providers are still being
+	// collected; pass it to CollectLate to fetch them
+	LateToken string
+
+	// Snapshot is the token the ranked results are stored under by
+	// SearchSnapshot, to read further pages with ReadSnapshot
+	Snapshot string
+
+	// HasMore is set when providers may have results beyond RankedEntities,
+	// which ReadSnapshot fetches as they are needed
+	HasMore bool
+
+	// Suggestion is the query with misspelled words replaced by known words
+	// of recently seen entities, or "" if all words are known
+	Suggestion string
+}
+
+// Provider report statuses.
+const (
+	// ReportOK marks a provider that answered within its soft deadline
+	ReportOK = "ok"
+
+	// ReportLate marks a provider that missed its soft deadline
+	ReportLate = "late"
+
+	// ReportFailed marks a provider that returned an error
+	ReportFailed = "failed"
+
+	// ReportSkipped marks a provider that doesn't support the query's filters
+	ReportSkipped = "skipped"
+)
+
+// Error classes reported for failures that aren't a provider.ErrorType.
+const (
+	ErrorClassTimeout     = "timeout"
+	ErrorClassCanceled    = "canceled"
+	ErrorClassUnavailable = "unavailable"
+)
+
+var (
+	// ErrLateResultsNotFound is returned by CollectLate for unknown or expired tokens.
+	ErrLateResultsNotFound = errors.New("late results not found")
+
+	// ErrSnapshotNotFound is returned by ReadSnapshot for unknown or expired tokens.
+	ErrSnapshotNotFound = errors.New("result snapshot not found")
+
+	errNotConnected     = errors.New("provider not connected")
+	errProviderNotFound = errors.New("provider not found")
+	errSoftDeadline     = errors.New("provider missed its soft deadline")
+)
+
+// ProviderReport describes how a provider instance fared in a search.
+type ProviderReport struct {
+	// Provider is the instance key ("providerType:instanceID")
+	Provider string
+
+	// Status is ReportOK, ReportLate, ReportFailed or ReportSkipped
+	Status string
+
+	// ErrorClass categorizes the error of late and failed providers: a
+	// provider.ErrorType, ErrorClassTimeout, ErrorClassCanceled or
+	// ErrorClassUnavailable
+	ErrorClass string
+
+	// Error is the error message of failed providers
+	Error string
+
+	// Duration is the time from the start of the search until the provider
+	// answered, or until it was given up on
+	Duration time.Duration
+}
+
+// lateResults collects the results of providers that missed their soft
+// deadline, for a follow-up CollectLate call.
+type lateResults struct {
+	query SearchQuery
+	start time.Time
+
+	mu      sync.Mutex
+	results []FederatedResult
+	done    chan struct{}
 }
 
 // Search broadcasts a search query to all providers and aggregates results.
+// It waits for each provider up to the provider's soft deadline, so results
+// from fast providers aren't held back by slow ones. Providers that miss it
+// are reported as late and served from the entity store. With
+// query.CollectLate, their results are still collected until the hard
+// timeout and can be fetched with CollectLate. If ctx is canceled, providers
+// that haven't answered yet are reported as canceled.
 func (f *Federator) Search(ctx context.Context, query SearchQuery) FederatedResponse {
-	start := time.Now()
+	return f.SearchStream(ctx, query, nil)
+}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(ctx, f.timeout)
-	defer cancel()
+// SearchStream works like Search, and also passes each provider's result and
+// report to onResult as soon as it is available, before the results are
+// merged and ranked. Failed and late providers are passed their entity store
+// fallback. onResult is called from the calling goroutine, one result at a
+// time.
+func (f *Federator) SearchStream(ctx context.Context, query SearchQuery, onResult func(FederatedResult, ProviderReport)) FederatedResponse {
+	start := time.Now()
+	if onResult == nil {
+		onResult = func(FederatedResult, ProviderReport) {}
+	}
 
-	// Get all provider names
-	providerNames := f.manager.List()
+	// Get all provider instance keys. Instances that failed to initialize are
+	// included so they are reported as not connected (and can be served from
+	// the entity store).
+	providerNames := append(f.manager.List(), f.manager.ListPending()...)
+	if query.instances != nil {
+		providerNames = slices.DeleteFunc(providerNames, func(name string) bool {
+			return !query.instances[name]
+		})
+	}
 
 	// If no providers, return empty response
 	if len(providerNames) == 0 {
@@ -71,46 +286,315 @@ func (f *Federator) Search(ctx context.Context, query SearchQuery) FederatedResp
 			TypeCounts:     make(map[string]int),
 			HasErrors:      false,
 			Duration:       time.Since(start),
+			Reports:        []ProviderReport{},
 		}
 	}
 
+	// Provider calls are bounded by the hard timeout. Late results that are
+	// collected for a follow-up request must outlive this one.
+	searchCtx := ctx
+	if query.CollectLate {
+		searchCtx = context.WithoutCancel(ctx)
+	}
+	searchCtx, cancel := context.WithTimeout(searchCtx, f.timeout)
+
 	// Search all providers concurrently
 	results := make(chan FederatedResult, len(providerNames))
-	var wg sync.WaitGroup
-
+	deadlines := make(map[string]time.Time, len(providerNames))
 	for _, name := range providerNames {
-		wg.Add(1)
+		deadlines[name] = start.Add(f.softDeadline(name))
 		go func(providerName string) {
-			defer wg.Done()
-
-			result := f.searchProvider(ctx, providerName, query)
-			results <- result
+			results <- f.searchProvider(searchCtx, providerName, query)
 		}(name)
 	}
 
-	// Wait for all searches to complete
+	var collected []FederatedResult
+	reports := make([]ProviderReport, 0, len(providerNames))
+	report := func(result FederatedResult, providerReport ProviderReport) {
+		collected = append(collected, result)
+		reports = append(reports, providerReport)
+		onResult(result, providerReport)
+	}
+
+	// receive reports a provider that answered by its soft deadline
+	receive := func(result FederatedResult) {
+		answer := newProviderReport(result)
+		if result.Error != nil && f.cache != nil {
+			result = f.searchCache(result, query)
+		}
+		report(result, answer)
+	}
+
+	// expire reports a provider that missed its soft deadline as late and
+	// falls back to its stored entities
+	late := 0
+	expire := func(name string) {
+		late++
+		delete(deadlines, name)
+		duration := time.Since(start)
+		result := FederatedResult{
+			Provider:   name,
+			Source:     SourceLive,
+			Entities:   []types.Entity{},
+			Error:      errSoftDeadline,
+			Duration:   duration,
+			TypeCounts: map[string]int{},
+		}
+		if f.cache != nil {
+			result = f.searchCache(result, query)
+		}
+		report(result, ProviderReport{
+			Provider:   name,
+			Status:     ReportLate,
+			ErrorClass: ErrorClassTimeout,
+			Error:      errSoftDeadline.Error(),
+			Duration:   duration,
+		})
+	}
+
+	// Wait until every provider has answered or missed its own soft deadline.
+	// Results that arrive after a provider's deadline are kept for CollectLate.
+	var arrivedLate []FederatedResult
+	canceled := false
+	for len(deadlines) > 0 {
+		next := time.Time{}
+		for _, deadline := range deadlines {
+			if next.IsZero() || deadline.Before(next) {
+				next = deadline
+			}
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case result := <-results:
+			if deadline, pending := deadlines[result.Provider]; pending && time.Now().Before(deadline) {
+				delete(deadlines, result.Provider)
+				receive(result)
+			} else {
+				if pending {
+					expire(result.Provider)
+				}
+				arrivedLate = append(arrivedLate, result)
+			}
+		case <-timer.C:
+		case <-ctx.Done():
+			// The caller is gone, don't wait for anyone else
+			canceled = true
+		}
+		timer.Stop()
+
+		if canceled {
+			break
+		}
+		now := time.Now()
+		for _, name := range providerNames {
+			if deadline, pending := deadlines[name]; pending && !now.Before(deadline) {
+				expire(name)
+			}
+		}
+	}
+
+	// Providers still outstanding when the caller went away are reported as
+	// canceled. Nobody reads their fallback, so the entity store isn't searched.
+	for _, name := range providerNames {
+		if _, pending := deadlines[name]; !pending {
+			continue
+		}
+		duration := time.Since(start)
+		report(FederatedResult{
+			Provider:   name,
+			Source:     SourceLive,
+			Entities:   []types.Entity{},
+			Error:      ctx.Err(),
+			Duration:   duration,
+			TypeCounts: map[string]int{},
+		}, ProviderReport{
+			Provider:   name,
+			Status:     ReportFailed,
+			ErrorClass: ErrorClassCanceled,
+			Error:      ctx.Err().Error(),
+			Duration:   duration,
+		})
+	}
+
+	response := f.aggregate(ctx, collected, query)
+	response.Reports = reports
+	response.Duration = time.Since(start)
+
+	if late > 0 {
+		f.logger.Debug().
+			Int("late_providers", late).
+			Bool("collect_late", query.CollectLate).
+			Msg("Answering search without late providers")
+	}
+	if late > 0 && query.CollectLate && !canceled {
+		response.LateToken = f.collectLate(results, arrivedLate, late-len(arrivedLate), cancel, query, start)
+	} else {
+		cancel()
+	}
+
+	return response
+}
+
+// softDeadline returns how long a search waits for a provider instance,
+// bounded by the hard timeout.
+func (f *Federator) softDeadline(providerName string) time.Duration {
+	deadline := f.manager.SoftDeadline(providerName)
+	if deadline <= 0 {
+		deadline = f.softDefault
+	}
+	if deadline <= 0 || deadline > f.timeout {
+		deadline = f.timeout
+	}
+	return deadline
+}
+
+// collectLate keeps collecting the results of late providers in the
+// background and returns the token they can be fetched with. arrived are the
+// late results already received, and count the number still outstanding.
+// cancel is called once they have all answered.
+func (f *Federator) collectLate(results <-chan FederatedResult, arrived []FederatedResult, count int, cancel context.CancelFunc, query SearchQuery, start time.Time) string {
+	token := newToken()
+	batch := &lateResults{
+		query:   query,
+		start:   start,
+		results: arrived,
+		done:    make(chan struct{}),
+	}
+
+	f.lateMu.Lock()
+	f.late[token] = batch
+	f.lateMu.Unlock()
+
 	go func() {
-		wg.Wait()
-		close(results)
+		defer cancel()
+		for i := 0; i < count; i++ {
+			result := <-results
+			batch.mu.Lock()
+			batch.results = append(batch.results, result)
+			batch.mu.Unlock()
+		}
+		close(batch.done)
+
+		// Forget results nobody came back for
+		time.AfterFunc(f.lateTTL, func() {
+			f.lateMu.Lock()
+			delete(f.late, token)
+			f.lateMu.Unlock()
+		})
 	}()
 
-	// Aggregate results
-	var allResults []FederatedResult
+	return token
+}
+
+// CollectLate returns the results of late providers of an earlier Search
+// made with query.CollectLate. It waits until all of them have answered or
+// ctx is done, and returns the results that arrived since the last call.
+// LateToken is set in the response while results are still outstanding.
+func (f *Federator) CollectLate(ctx context.Context, token string) (FederatedResponse, error) {
+	f.lateMu.Lock()
+	batch, ok := f.late[token]
+	f.lateMu.Unlock()
+	if !ok {
+		return FederatedResponse{}, ErrLateResultsNotFound
+	}
+
+	finished := true
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		finished = false
+	}
+
+	batch.mu.Lock()
+	collected := batch.results
+	batch.results = nil
+	batch.mu.Unlock()
+
+	reports := make([]ProviderReport, 0, len(collected))
+	for _, result := range collected {
+		reports = append(reports, newProviderReport(result))
+	}
+
+	response := f.aggregate(ctx, collected, batch.query)
+	response.Reports = reports
+	response.Duration = time.Since(batch.start)
+	if finished {
+		f.lateMu.Lock()
+		delete(f.late, token)
+		f.lateMu.Unlock()
+	} else {
+		response.LateToken = token
+	}
+	return response, nil
+}
+
+// newToken returns a random token for late results or a result snapshot.
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// newProviderReport reports on a provider that answered.
+func newProviderReport(result FederatedResult) ProviderReport {
+	report := ProviderReport{
+		Provider: result.Provider,
+		Status:   ReportOK,
+		Duration: result.Duration,
+	}
+	switch {
+	case result.Error != nil:
+		report.Status = ReportFailed
+		report.ErrorClass = errorClass(result.Error)
+		report.Error = result.Error.Error()
+	case result.Skipped:
+		report.Status = ReportSkipped
+	}
+	return report
+}
+
+// errorClass categorizes a provider search error for reporting.
+func errorClass(err error) string {
+	var providerErr *provider.ProviderError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, errNotConnected), errors.Is(err, errProviderNotFound):
+		return ErrorClassUnavailable
+	case errors.As(err, &providerErr):
+		return string(providerErr.Type)
+	default:
+		return string(provider.ErrorTypeUnknown)
+	}
+}
+
+// aggregate merges provider results and ranks their entities.
+func (f *Federator) aggregate(ctx context.Context, results []FederatedResult, query SearchQuery) FederatedResponse {
+	allResults := make([]FederatedResult, 0, len(results))
 	var allEntities []EntityWithProvider
 	totalCount := 0
 	typeCounts := make(map[string]int)
 	hasErrors := false
+	sources := make(map[string]string)
 
-	for result := range results {
+	for _, result := range results {
 		allResults = append(allResults, result)
 		totalCount += len(result.Entities)
 
+		if f.vocabulary != nil {
+			f.vocabulary.observe(result.Entities)
+		}
+
 		// Collect entities for ranking
 		for _, entity := range result.Entities {
 			allEntities = append(allEntities, EntityWithProvider{
 				Entity:   entity,
 				Provider: result.Provider,
 			})
+			sources[entity.ID] = result.Source
 		}
 
 		// Aggregate type counts
@@ -142,17 +626,29 @@ func (f *Federator) Search(ctx context.Context, query SearchQuery) FederatedResp
 		}
 	}
 
+	// Flag each ranked entity as live or cached
+	for i := range rankedEntities {
+		rankedEntities[i].Source = sources[rankedEntities[i].Entity.ID]
+	}
+	SortEntities(rankedEntities, query.Sort)
+
+	// Suggest a spelling after seeing the results, so words they contain count as known
+	suggestion := ""
+	if f.vocabulary != nil {
+		suggestion = f.vocabulary.suggest(query.Query)
+	}
+
 	return FederatedResponse{
 		Results:        allResults,
 		RankedEntities: rankedEntities,
 		TotalCount:     totalCount,
 		TypeCounts:     typeCounts,
 		HasErrors:      hasErrors,
-		Duration:       time.Since(start),
+		Suggestion:     suggestion,
 	}
 }
 
-// searchProvider searches a single provider and returns the result.
+// searchProvider searches a single provider instance and returns the result.
 func (f *Federator) searchProvider(ctx context.Context, providerName string, query SearchQuery) FederatedResult {
 	start := time.Now()
 
@@ -160,8 +656,9 @@ func (f *Federator) searchProvider(ctx context.Context, providerName string, que
 	if !f.manager.IsConnected(providerName) {
 		return FederatedResult{
 			Provider:   providerName,
+			Source:     SourceLive,
 			Entities:   []types.Entity{},
-			Error:      fmt.Errorf("provider not connected"),
+			Error:      errNotConnected,
 			Duration:   time.Since(start),
 			TypeCounts: map[string]int{},
 		}
@@ -172,8 +669,25 @@ func (f *Federator) searchProvider(ctx context.Context, providerName string, que
 	if !ok {
 		return FederatedResult{
 			Provider:   providerName,
+			Source:     SourceLive,
+			Entities:   []types.Entity{},
+			Error:      errProviderNotFound,
+			Duration:   time.Since(start),
+			TypeCounts: map[string]int{},
+		}
+	}
+
+	// Skip providers that declare they can't return entities of the queried type
+	if query.Type != "" && !f.producesType(providerName, query.Type) {
+		f.logger.Debug().
+			Str("provider", providerName).
+			Str("type", query.Type).
+			Msg("Provider does not produce the query type, skipping provider")
+		return FederatedResult{
+			Provider:   providerName,
+			Source:     SourceLive,
+			Skipped:    true,
 			Entities:   []types.Entity{},
-			Error:      fmt.Errorf("provider not found"),
 			Duration:   time.Since(start),
 			TypeCounts: map[string]int{},
 		}
@@ -185,18 +699,14 @@ func (f *Federator) searchProvider(ctx context.Context, providerName string, que
 		capabilities = make(map[string]provider.FilterCapability)
 	}
 
-	// Filter the query's filters to only include keys the provider supports
-	filteredFilters := make(map[string]any)
-	for key, value := range query.Filters {
-		if _, supported := capabilities[key]; supported {
-			filteredFilters[key] = value
-		}
-	}
+	// Push down the filters the provider supports, and apply the rest to its results
+	pushed, residual := pushdown(query, capabilities, f.manager.SupportsFilterExpressions(providerName))
 
-	// If the provider doesn't support any of the query's filters, skip it entirely
-	// This prevents irrelevant results when filtering by provider-specific attributes
-	// Exception: always search if there's a text query and no filters are being applied
-	if len(query.Filters) > 0 && len(filteredFilters) == 0 {
+	// Typed filters the provider doesn't support are applied to its results as
+	// residual filters, which drop entities without the attribute. Untyped
+	// filters are raw values, so providers that know none of them are skipped
+	// rather than returning unfiltered results.
+	if query.TypedFilters == nil && len(query.Filters) > 0 && !supportsAny(query.Filters, capabilities) {
 		f.logger.Debug().
 			Str("provider", providerName).
 			Str("query", query.Query).
@@ -204,6 +714,8 @@ func (f *Federator) searchProvider(ctx context.Context, providerName string, que
 			Msg("Provider does not support any of the query filters, skipping provider")
 		return FederatedResult{
 			Provider:   providerName,
+			Source:     SourceLive,
+			Skipped:    true,
 			Entities:   []types.Entity{},
 			Error:      nil, // Not an error, just no results
 			Duration:   time.Since(start),
@@ -211,9 +723,16 @@ func (f *Federator) searchProvider(ctx context.Context, providerName string, que
 		}
 	}
 
-	// Create provider query with filtered filters
+	// Create provider query with the pushed down filters. Residual filters
+	// drop entities from the provider's results, so with any left the
+	// provider is searched unpaged and its results paginated after filtering.
 	providerQuery := query.providerQuery()
-	providerQuery.Filters = filteredFilters
+	providerQuery.Filters = pushed
+	page := providerQuery
+	if len(residual) > 0 {
+		providerQuery.Limit = 0
+		providerQuery.Offset = 0
+	}
 
 	// Log the outgoing provider request
 	f.logger.Debug().
@@ -221,12 +740,19 @@ func (f *Federator) searchProvider(ctx context.Context, providerName string, que
 		Str("query", providerQuery.Query).
 		Str("type", providerQuery.Type).
 		Interface("filters", providerQuery.Filters).
+		Strs("residual_filters", getFilterKeys(residual)).
 		Int("limit", providerQuery.Limit).
 		Int("offset", providerQuery.Offset).
 		Msg("Sending search request to provider")
 
 	// Execute search
 	entities, err := prov.Search(ctx, providerQuery)
+	hasMore := err == nil && providerQuery.Limit > 0 && len(entities) >= providerQuery.Limit
+	if len(residual) > 0 && err == nil {
+		entities = f.filters.ApplyFilters(entities, residual)
+		hasMore = page.Limit > 0 && page.Offset+page.Limit < len(entities)
+		entities = page.Paginate(entities)
+	}
 
 	// Count by type for response and logging
 	typeCounts := make(map[string]int)
@@ -245,6 +771,8 @@ func (f *Federator) searchProvider(ctx context.Context, providerName string, que
 
 	return FederatedResult{
 		Provider:   providerName,
+		Source:     SourceLive,
+		HasMore:    hasMore,
 		Entities:   entities,
 		Error:      err,
 		Duration:   time.Since(start),
@@ -252,9 +780,77 @@ func (f *Federator) searchProvider(ctx context.Context, providerName string, que
 	}
 }
 
+// producesType reports whether a provider instance can return entities of
+// typeName, i.e. whether it emits that type or one of its descendants.
+// Instances that don't declare their types are assumed to produce any type.
+func (f *Federator) producesType(providerName, typeName string) bool {
+	entityTypes, ok := f.manager.EntityTypes(providerName)
+	if !ok {
+		return true
+	}
+	for _, entityType := range entityTypes {
+		if f.types.IsA(entityType, typeName) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchCache answers a failed live search from the local entity store.
+// The live error is kept on the result so callers can still see why the
+// provider didn't answer. If the store has nothing for the instance, the
+// failed live result is returned unchanged.
+func (f *Federator) searchCache(live FederatedResult, query SearchQuery) FederatedResult {
+	start := time.Now()
+
+	// Filters and pagination are applied here so the store only needs to match text and type
+	cacheQuery := query.providerQuery()
+	cacheQuery.Limit = 0
+	cacheQuery.Offset = 0
+
+	entities, err := f.cache.Search(live.Provider, cacheQuery)
+	if err != nil {
+		f.logger.Warn().
+			Str("provider", live.Provider).
+			Err(err).
+			Msg("Failed to search entity store")
+		return live
+	}
+
+	entities = f.filters.ApplyFilters(entities, query.memoryFilters())
+	entities = query.providerQuery().Paginate(entities)
+	if len(entities) == 0 {
+		return live
+	}
+
+	typeCounts := make(map[string]int)
+	for _, entity := range entities {
+		typeCounts[entity.Type]++
+	}
+
+	f.logger.Debug().
+		Str("provider", live.Provider).
+		AnErr("live_error", live.Error).
+		Int("entity_count", len(entities)).
+		Msg("Serving cached results for provider")
+
+	return FederatedResult{
+		Provider:   live.Provider,
+		Source:     SourceCached,
+		Entities:   entities,
+		Error:      live.Error,
+		Duration:   live.Duration + time.Since(start),
+		TypeCounts: typeCounts,
+	}
+}
+
 // DiscoverAll runs discovery on all providers and aggregates results.
 func (f *Federator) DiscoverAll(ctx context.Context) ([]types.Entity, error) {
-	return f.manager.DiscoverAll(ctx)
+	entities, err := f.manager.DiscoverAll(ctx)
+	if f.vocabulary != nil {
+		f.vocabulary.observe(entities)
+	}
+	return entities, err
 }
 
 // GetStatus returns the status of all providers.
@@ -262,11 +858,62 @@ func (f *Federator) GetStatus() []provider.ProviderStatus {
 	return f.manager.Status()
 }
 
+// SetCache sets the local entity store used when a live provider search fails.
+func (f *Federator) SetCache(cache EntityCache) {
+	f.cache = cache
+}
+
 // SetTimeout sets the timeout for federated searches.
 func (f *Federator) SetTimeout(timeout time.Duration) {
 	f.timeout = timeout
 }
 
+// SetSoftDeadline sets how long searches wait for provider instances that
+// don't configure their own soft deadline (0 = up to the timeout).
+func (f *Federator) SetSoftDeadline(deadline time.Duration) {
+	f.softDefault = deadline
+}
+
+// SetTypeRegistry sets the type registry used to route typed queries to the
+// providers that produce the type.
+func (f *Federator) SetTypeRegistry(registry *types.TypeRegistry) {
+	f.types = registry
+}
+
+// SetLateResultsTTL sets how long collected late results are kept for CollectLate.
+func (f *Federator) SetLateResultsTTL(ttl time.Duration) {
+	f.lateTTL = ttl
+}
+
+// SetSnapshotTTL sets how long result snapshots are kept for ReadSnapshot
+// after they were last read.
+func (f *Federator) SetSnapshotTTL(ttl time.Duration) {
+	f.snapshots.configure(ttl, f.snapshots.size)
+}
+
+// SetMaxSnapshots sets how many result snapshots are kept for ReadSnapshot
+// (0 = none, so there are no cursors).
+func (f *Federator) SetMaxSnapshots(size int) {
+	f.snapshots.configure(f.snapshots.ttl, size)
+}
+
+// SetPageWindow sets how many results SearchSnapshot requests from each
+// provider at first (0 = all results at once).
+func (f *Federator) SetPageWindow(window int) {
+	f.pageWindow = window
+}
+
+// SetVocabularySize sets how many words of recently seen entities are kept
+// for spelling suggestions (0 = no suggestions). Words seen so far are
+// forgotten.
+func (f *Federator) SetVocabularySize(size int) {
+	if size <= 0 {
+		f.vocabulary = nil
+		return
+	}
+	f.vocabulary = newVocabulary(size)
+}
+
 // SearchQuery wraps the provider SearchQuery with additional metadata.
 type SearchQuery struct {
 	// Query is the search string
@@ -298,6 +945,48 @@ type SearchQuery struct {
 
 	// MaxDepth specifies how deep to follow relationships
 	MaxDepth int
+
+	// TypedFilters are the validated filters Filters was derived from. When
+	// set, they are negotiated with each provider's filter capabilities, so
+	// operators a provider can't evaluate are applied to its results instead.
+	TypedFilters map[string]filters.FilterValue
+
+	// FilterExpr holds boolean filter groups that must match in addition to
+	// the filters above. It is pushed down to providers that evaluate filter
+	// expressions and support all of its conditions, and applied to the
+	// results of the others.
+	FilterExpr *filters.Expr
+
+	// CollectLate keeps collecting the results of providers that miss their
+	// soft deadline, to be fetched with Federator.CollectLate
+	CollectLate bool
+
+	// Sort orders the ranked results by attributes, with score breaking ties
+	// (nil for score order)
+	Sort []SortClause
+
+	// instances restricts the search to these provider instances (nil for
+	// all), for fetching more results of a snapshot
+	instances map[string]bool
+}
+
+// memoryFilters returns the query's filters for ApplyFilters, as typed
+// filters when the query has them.
+func (q SearchQuery) memoryFilters() map[string]any {
+	if q.TypedFilters == nil && q.FilterExpr == nil {
+		return q.Filters
+	}
+	result := make(map[string]any, len(q.TypedFilters)+1)
+	if q.TypedFilters == nil {
+		maps.Copy(result, q.Filters)
+	}
+	for name, filter := range q.TypedFilters {
+		result[name] = filter
+	}
+	if q.FilterExpr != nil {
+		result[filters.KeyAnd] = q.FilterExpr
+	}
+	return result
 }
 
 // providerQuery converts the search query to a provider query.
@@ -370,6 +1059,16 @@ func (q SearchQuery) WithRelated(enabled bool, maxDepth int) SearchQuery {
 	return q
 }
 
+// supportsAny reports whether a provider has a capability for any of the filters.
+func supportsAny(filterValues map[string]any, capabilities map[string]provider.FilterCapability) bool {
+	for key := range filterValues {
+		if _, supported := capabilities[key]; supported {
+			return true
+		}
+	}
+	return false
+}
+
 // getFilterKeys returns the keys from a filters map for logging.
 func getFilterKeys(filters map[string]any) []string {
 	keys := make([]string, 0, len(filters))
@@ -2,7 +2,10 @@ package search
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/yourname/mifind/internal/search/filters"
 	"github.com/yourname/mifind/internal/types"
 )
 
@@ -244,9 +247,27 @@ func (f *Filters) ApplyFilters(entities []types.Entity, filters map[string]any)
 }
 
 // matchesFilters checks if an entity matches all filter criteria.
-func (f *Filters) matchesFilters(entity types.Entity, filters map[string]any) bool {
-	for key, filterValue := range filters {
-		entityValue, exists := entity.Attributes[key]
+// Criteria are plain values, matched by equality, typed filters
+// (filters.FilterValue), matched with their operator, or boolean filter
+// expressions (*filters.Expr), whose key is ignored.
+func (f *Filters) matchesFilters(entity types.Entity, criteria map[string]any) bool {
+	for key, filterValue := range criteria {
+		if expr, ok := filterValue.(*filters.Expr); ok {
+			if !matchExpr(entity, expr) {
+				return false
+			}
+			continue
+		}
+
+		entityValue, exists := entityAttribute(entity, key)
+
+		if typed, ok := filterValue.(filters.FilterValue); ok {
+			if !matchTyped(entityValue, exists, typed) {
+				return false
+			}
+			continue
+		}
+
 		if !exists {
 			return false
 		}
@@ -258,6 +279,24 @@ func (f *Filters) matchesFilters(entity types.Entity, filters map[string]any) bo
 	return true
 }
 
+// matchExpr evaluates a boolean filter expression against an entity.
+func matchExpr(entity types.Entity, expr *filters.Expr) bool {
+	return expr.Eval(func(attribute string, filter filters.FilterValue) bool {
+		entityValue, exists := entityAttribute(entity, attribute)
+		return matchTyped(entityValue, exists, filter)
+	})
+}
+
+// entityAttribute returns an attribute of an entity, falling back to the
+// entity type for the type attribute.
+func entityAttribute(entity types.Entity, key string) (any, bool) {
+	entityValue, exists := entity.Attributes[key]
+	if !exists && key == types.AttrType {
+		return entity.Type, true
+	}
+	return entityValue, exists
+}
+
 // matchValue checks if an entity value matches a filter value.
 func (f *Filters) matchValue(entityValue, filterValue any) bool {
 	// Handle slice filters (e.g., for multi-select)
@@ -281,6 +320,213 @@ func (f *Filters) matchSingleValue(entityValue, filterValue any) bool {
 	return entityStr == filterStr
 }
 
+// matchTyped checks an entity value against a typed filter.
+func matchTyped(entityValue any, exists bool, filter filters.FilterValue) bool {
+	if !exists {
+		// Only inequality holds for a missing attribute
+		return filter.Operation() == filters.OpNeq
+	}
+
+	switch typed := filter.(type) {
+	case *filters.RangeFilter:
+		n, ok := numericValue(entityValue)
+		return ok && (typed.Min == nil || n >= *typed.Min) && (typed.Max == nil || n <= *typed.Max)
+
+	case *filters.DateRangeFilter:
+		t, ok := timeValue(entityValue)
+		return ok && (typed.Min == nil || !t.Before(*typed.Min)) && (typed.Max == nil || !t.After(*typed.Max))
+
+	case *filters.GeoRadiusFilter:
+		point, ok := GPSValue(entityValue)
+		return ok && typed.Contains(point)
+
+	case *filters.GeoBoxFilter:
+		point, ok := GPSValue(entityValue)
+		return ok && typed.Contains(point)
+
+	case *filters.StringSliceFilter:
+		have := stringValues(entityValue)
+		want, _ := typed.Value().([]string)
+		matched := 0
+		for _, value := range want {
+			if containsString(have, value) {
+				matched++
+			}
+		}
+		if typed.Op == filters.OpEq {
+			// Contains all
+			return matched == len(want)
+		}
+		return matched > 0
+	}
+
+	// Inequality holds when no value of a list attribute is equal
+	if filter.Operation() == filters.OpNeq {
+		for _, value := range listValues(entityValue) {
+			if cmp, ok := compareValues(value, filter.Value()); ok && cmp == 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, value := range listValues(entityValue) {
+		if matchOperation(value, filter.Operation(), filter.Value()) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchOperation compares a single entity value with a filter value.
+func matchOperation(entityValue any, op filters.FilterOperation, filterValue any) bool {
+	if op == filters.OpContains {
+		return strings.Contains(
+			strings.ToLower(attributeValueToString(entityValue)),
+			strings.ToLower(attributeValueToString(filterValue)))
+	}
+
+	cmp, ok := compareValues(entityValue, filterValue)
+	if !ok {
+		return false
+	}
+	switch op {
+	case filters.OpEq, filters.OpIn:
+		return cmp == 0
+	case filters.OpGt:
+		return cmp > 0
+	case filters.OpGte:
+		return cmp >= 0
+	case filters.OpLt:
+		return cmp < 0
+	case filters.OpLte:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// compareValues orders two attribute values: numerically (times as Unix
+// timestamps) when both are numeric, otherwise as strings. Bools only compare
+// for equality. ok is false when the values can't be compared.
+func compareValues(a, b any) (cmp int, ok bool) {
+	if x, ok := numericValue(a); ok {
+		if y, ok := numericValue(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	x, isBool := a.(bool)
+	if y, ok := b.(bool); ok || isBool {
+		if !ok || !isBool {
+			return 0, false
+		}
+		if x == y {
+			return 0, true
+		}
+		return 1, true
+	}
+
+	return strings.Compare(attributeValueToString(a), attributeValueToString(b)), true
+}
+
+// numericValue converts a numeric or time attribute value to float64.
+func numericValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case time.Time:
+		return float64(v.Unix()), true
+	default:
+		return 0, false
+	}
+}
+
+// GPSValue converts a GPS attribute value to a coordinate. Besides types.GPS,
+// it accepts the {"latitude", "longitude"} maps GPS values become after a
+// JSON round trip (entity store, plugins).
+func GPSValue(value any) (types.GPS, bool) {
+	switch v := value.(type) {
+	case types.GPS:
+		return v, true
+	case *types.GPS:
+		if v != nil {
+			return *v, true
+		}
+	case map[string]any:
+		lat, latOK := numericValue(v["latitude"])
+		lng, lngOK := numericValue(v["longitude"])
+		if latOK && lngOK {
+			return types.GPS{Latitude: lat, Longitude: lng}, true
+		}
+	}
+	return types.GPS{}, false
+}
+
+// timeValue converts a time attribute value, or a Unix timestamp, to a time.
+func timeValue(value any) (time.Time, bool) {
+	if t, ok := value.(time.Time); ok {
+		return t, true
+	}
+	if n, ok := numericValue(value); ok {
+		return time.Unix(int64(n), 0), true
+	}
+	return time.Time{}, false
+}
+
+// listValues returns the elements of a list attribute, or the value itself.
+func listValues(value any) []any {
+	switch v := value.(type) {
+	case []string:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = item
+		}
+		return result
+	case []any:
+		return v
+	default:
+		return []any{value}
+	}
+}
+
+// stringValues returns the values of an attribute as strings.
+func stringValues(value any) []string {
+	if list, ok := value.([]string); ok {
+		return list
+	}
+	var result []string
+	for _, item := range listValues(value) {
+		result = append(result, attributeValueToString(item))
+	}
+	return result
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
 // getMinMax calculates min and max values from a map of string values.
 func (f *Filters) getMinMax(values map[string]int) (min, max float64) {
 	first := true
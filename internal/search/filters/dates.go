@@ -0,0 +1,121 @@
+package filters
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the absolute date formats, with the period each one spans.
+var dateLayouts = []struct {
+	layout string
+	next   func(time.Time) time.Time
+}{
+	{time.RFC3339, func(t time.Time) time.Time { return t.Add(time.Second) }},
+	{"2006-01-02T15:04", func(t time.Time) time.Time { return t.Add(time.Minute) }},
+	{"2006-01-02", func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }},
+	{"2006-01", func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }},
+	{"2006", func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }},
+}
+
+// relativeUnits are the units of rolling periods such as -7d.
+var relativeUnits = map[byte]func(t time.Time, n int) time.Time{
+	'h': func(t time.Time, n int) time.Time { return t.Add(-time.Duration(n) * time.Hour) },
+	'd': func(t time.Time, n int) time.Time { return t.AddDate(0, 0, -n) },
+	'w': func(t time.Time, n int) time.Time { return t.AddDate(0, 0, -7*n) },
+	'm': func(t time.Time, n int) time.Time { return t.AddDate(0, -n, 0) },
+	'y': func(t time.Time, n int) time.Time { return t.AddDate(-n, 0, 0) },
+}
+
+// ResolveDate resolves a date expression to the first and last second of the
+// period it covers. Expressions are resolved relative to now, in its location:
+//
+//   - absolute dates: 2023, 2023-06, 2023-06-15, 2023-06-15T10:30, RFC 3339
+//     and quarters (2023-Q2)
+//   - rolling periods ending now: -12h, -7d, -2w, -3m (months), -1y
+//   - calendar periods: now, today, yesterday, this-week, this-month,
+//     this-quarter, this-year and the last-* variants of the latter four
+//
+// Weeks start on Monday.
+func ResolveDate(expr string, now time.Time) (time.Time, time.Time, error) {
+	trimmed := strings.TrimSpace(expr)
+	s := strings.ToLower(trimmed)
+	loc := now.Location()
+
+	if start, next, ok := calendarPeriod(s, now); ok {
+		return start, next.Add(-time.Second), nil
+	}
+
+	if len(s) > 2 && s[0] == '-' {
+		if shift, ok := relativeUnits[s[len(s)-1]]; ok {
+			n, err := strconv.Atoi(s[1 : len(s)-1])
+			if err == nil && n >= 0 {
+				return shift(now, n).Truncate(time.Second), now.Truncate(time.Second), nil
+			}
+		}
+	}
+
+	if year, quarter, ok := strings.Cut(s, "-q"); ok && len(quarter) == 1 && quarter >= "1" && quarter <= "4" {
+		if y, err := strconv.Atoi(year); err == nil && len(year) == 4 {
+			start := time.Date(y, time.Month(3*(int(quarter[0]-'1'))+1), 1, 0, 0, 0, 0, loc)
+			return start, start.AddDate(0, 3, 0).Add(-time.Second), nil
+		}
+	}
+
+	for _, candidate := range dateLayouts {
+		start, err := time.ParseInLocation(candidate.layout, trimmed, loc)
+		if err != nil {
+			continue
+		}
+		return start, candidate.next(start).Add(-time.Second), nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q (expected e.g. 2024, 2024-06, 2024-06-15, 2024-Q2, RFC 3339, -7d, yesterday or last-month)", expr)
+}
+
+// calendarPeriod returns the start of a named calendar period and the start
+// of the period after it.
+func calendarPeriod(name string, now time.Time) (time.Time, time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch name {
+	case "now":
+		t := now.Truncate(time.Second)
+		return t, t.Add(time.Second), true
+	case "today":
+		return today, today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, true
+	}
+
+	which, unit, ok := strings.Cut(name, "-")
+	if !ok || (which != "this" && which != "last") {
+		return time.Time{}, time.Time{}, false
+	}
+
+	var start time.Time
+	var step func(time.Time, int) time.Time
+	switch unit {
+	case "week":
+		// Weekday counts from Sunday, weeks start on Monday
+		start = today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, 0, 7*n) }
+	case "month":
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, n, 0) }
+	case "quarter":
+		start = time.Date(now.Year(), now.Month()-(now.Month()-1)%3, 1, 0, 0, 0, 0, now.Location())
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, 3*n, 0) }
+	case "year":
+		start = time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+		step = func(t time.Time, n int) time.Time { return t.AddDate(n, 0, 0) }
+	default:
+		return time.Time{}, time.Time{}, false
+	}
+
+	if which == "last" {
+		start = step(start, -1)
+	}
+	return start, step(start, 1), true
+}
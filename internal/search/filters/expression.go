@@ -0,0 +1,176 @@
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourname/mifind/internal/types"
+)
+
+// ExprOp is the boolean operator of a filter expression group.
+type ExprOp string
+
+const (
+	ExprAnd ExprOp = "and" // All children match
+	ExprOr  ExprOp = "or"  // At least one child matches
+	ExprNot ExprOp = "not" // The single child doesn't match
+)
+
+// Keys of boolean groups in filter data. They start with "$", so they can't
+// clash with attribute names.
+const (
+	KeyAnd = "$and" // List of filter maps that must all match
+	KeyOr  = "$or"  // List of filter maps of which one must match
+	KeyNot = "$not" // Filter map that must not match
+)
+
+// IsGroupKey returns true if a filter data key is a boolean group rather than
+// an attribute name.
+func IsGroupKey(key string) bool {
+	return strings.HasPrefix(key, "$")
+}
+
+// Expr is a node of a boolean filter expression. A node with an operator
+// combines its children; a node without one is a condition on a single
+// attribute.
+type Expr struct {
+	// Op is the boolean operator of a group (empty for conditions)
+	Op ExprOp
+
+	// Children are the operands of a group (exactly one for NOT)
+	Children []*Expr
+
+	// Attribute is the attribute a condition filters
+	Attribute string
+
+	// Filter is the filter a condition applies to the attribute
+	Filter FilterValue
+}
+
+// And creates an expression matching when all children match.
+func And(children ...*Expr) *Expr {
+	return &Expr{Op: ExprAnd, Children: children}
+}
+
+// Or creates an expression matching when at least one child matches.
+func Or(children ...*Expr) *Expr {
+	return &Expr{Op: ExprOr, Children: children}
+}
+
+// Not creates an expression matching when child doesn't match.
+func Not(child *Expr) *Expr {
+	return &Expr{Op: ExprNot, Children: []*Expr{child}}
+}
+
+// Condition creates an expression applying filter to an attribute.
+func Condition(attribute string, filter FilterValue) *Expr {
+	return &Expr{Attribute: attribute, Filter: filter}
+}
+
+// Validate checks the structure of the expression and validates every
+// condition against the attribute definitions.
+func (e *Expr) Validate(attrs map[string]types.AttributeDef) error {
+	switch e.Op {
+	case "":
+		attrDef, exists := attrs[e.Attribute]
+		if !exists {
+			return &ValidationError{
+				FilterName: e.Attribute,
+				Reason:     fmt.Sprintf("unknown attribute: %q", e.Attribute),
+			}
+		}
+		if !attrDef.Filterable {
+			return &ValidationError{
+				FilterName: e.Attribute,
+				Reason:     fmt.Sprintf("attribute %q is not filterable", e.Attribute),
+			}
+		}
+		if e.Filter == nil {
+			return &ValidationError{
+				FilterName: e.Attribute,
+				Reason:     "condition has no filter",
+			}
+		}
+		return e.Filter.Validate(attrDef)
+
+	case ExprAnd, ExprOr:
+		if len(e.Children) == 0 {
+			return &ValidationError{Reason: fmt.Sprintf("%s group cannot be empty", e.Op)}
+		}
+
+	case ExprNot:
+		if len(e.Children) != 1 {
+			return &ValidationError{Reason: fmt.Sprintf("not group must have exactly one operand, got %d", len(e.Children))}
+		}
+
+	default:
+		return &ValidationError{Reason: fmt.Sprintf("unknown boolean operator: %s", e.Op)}
+	}
+
+	for _, child := range e.Children {
+		if err := child.Validate(attrs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Eval evaluates the expression, calling match for each condition it needs.
+func (e *Expr) Eval(match func(attribute string, filter FilterValue) bool) bool {
+	switch e.Op {
+	case ExprAnd:
+		for _, child := range e.Children {
+			if !child.Eval(match) {
+				return false
+			}
+		}
+		return true
+	case ExprOr:
+		for _, child := range e.Children {
+			if child.Eval(match) {
+				return true
+			}
+		}
+		return false
+	case ExprNot:
+		return !e.Children[0].Eval(match)
+	default:
+		return match(e.Attribute, e.Filter)
+	}
+}
+
+// Conditions returns the conditions of the expression, depth first.
+func (e *Expr) Conditions() []*Expr {
+	if e.Op == "" {
+		return []*Expr{e}
+	}
+	var conditions []*Expr
+	for _, child := range e.Children {
+		conditions = append(conditions, child.Conditions()...)
+	}
+	return conditions
+}
+
+// Spec encodes the expression as filter data, which Parser.ParseExpression
+// reads back. Groups use the "$and", "$or" and "$not" keys, conditions the
+// explicit operation format ({"size": {"gte": 1000}}), and ranges their
+// min/max map.
+func (e *Expr) Spec() map[string]any {
+	switch e.Op {
+	case ExprAnd, ExprOr:
+		children := make([]any, len(e.Children))
+		for i, child := range e.Children {
+			children[i] = child.Spec()
+		}
+		return map[string]any{"$" + string(e.Op): children}
+	case ExprNot:
+		return map[string]any{KeyNot: e.Children[0].Spec()}
+	}
+
+	switch e.Filter.Operation() {
+	case OpRange, OpDateRange:
+		return map[string]any{e.Attribute: e.Filter.Value()}
+	default:
+		return map[string]any{e.Attribute: map[string]any{string(e.Filter.Operation()): e.Filter.Value()}}
+	}
+}
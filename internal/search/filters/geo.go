@@ -0,0 +1,182 @@
+package filters
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/yourname/mifind/internal/types"
+)
+
+// earthRadiusKm is the mean radius of the earth used for distances.
+const earthRadiusKm = 6371.0
+
+// GeoRadiusFilter matches coordinates within a distance of a center point.
+type GeoRadiusFilter struct {
+	Latitude  float64 // Latitude of the center in degrees
+	Longitude float64 // Longitude of the center in degrees
+	RadiusKm  float64 // Maximum distance from the center in kilometers
+}
+
+// NewGeoRadiusFilter creates a new GeoRadiusFilter.
+func NewGeoRadiusFilter(lat, lng, radiusKm float64) *GeoRadiusFilter {
+	return &GeoRadiusFilter{Latitude: lat, Longitude: lng, RadiusKm: radiusKm}
+}
+
+// Operation returns the filter operation (always "radius" for radius filters).
+func (f *GeoRadiusFilter) Operation() FilterOperation {
+	return OpGeoRadius
+}
+
+// Validate checks if the radius filter is valid.
+func (f *GeoRadiusFilter) Validate(attrDef types.AttributeDef) error {
+	if err := validateGeoAttribute(f.Operation(), attrDef); err != nil {
+		return err
+	}
+
+	if err := validateCoordinate(f.Latitude, f.Longitude); err != nil {
+		return &ValidationError{
+			FilterName: attrDef.Name,
+			Reason:     fmt.Sprintf("invalid center: %v", err),
+			Operation:  f.Operation(),
+		}
+	}
+
+	if f.RadiusKm <= 0 || math.IsNaN(f.RadiusKm) {
+		return &ValidationError{
+			FilterName: attrDef.Name,
+			Reason:     "radius must be greater than 0",
+			Operation:  f.Operation(),
+		}
+	}
+
+	return nil
+}
+
+// Value returns the center and radius as a map with lat, lng and km keys.
+func (f *GeoRadiusFilter) Value() any {
+	return map[string]any{
+		"lat": f.Latitude,
+		"lng": f.Longitude,
+		"km":  f.RadiusKm,
+	}
+}
+
+// Contains returns true if a coordinate is within the radius.
+func (f *GeoRadiusFilter) Contains(point types.GPS) bool {
+	return DistanceKm(f.Latitude, f.Longitude, point.Latitude, point.Longitude) <= f.RadiusKm
+}
+
+// GeoBoxFilter matches coordinates within a bounding box. A box whose west
+// edge is east of its east edge crosses the antimeridian.
+type GeoBoxFilter struct {
+	South float64 // Minimum latitude in degrees
+	West  float64 // Western longitude in degrees
+	North float64 // Maximum latitude in degrees
+	East  float64 // Eastern longitude in degrees
+}
+
+// NewGeoBoxFilter creates a new GeoBoxFilter.
+func NewGeoBoxFilter(south, west, north, east float64) *GeoBoxFilter {
+	return &GeoBoxFilter{South: south, West: west, North: north, East: east}
+}
+
+// Operation returns the filter operation (always "bbox" for bounding box filters).
+func (f *GeoBoxFilter) Operation() FilterOperation {
+	return OpGeoBox
+}
+
+// Validate checks if the bounding box filter is valid.
+func (f *GeoBoxFilter) Validate(attrDef types.AttributeDef) error {
+	if err := validateGeoAttribute(f.Operation(), attrDef); err != nil {
+		return err
+	}
+
+	for _, corner := range [][2]float64{{f.South, f.West}, {f.North, f.East}} {
+		if err := validateCoordinate(corner[0], corner[1]); err != nil {
+			return &ValidationError{
+				FilterName: attrDef.Name,
+				Reason:     fmt.Sprintf("invalid bounding box: %v", err),
+				Operation:  f.Operation(),
+			}
+		}
+	}
+
+	if f.South > f.North {
+		return &ValidationError{
+			FilterName: attrDef.Name,
+			Reason:     "bounding box south cannot be greater than north",
+			Operation:  f.Operation(),
+		}
+	}
+
+	return nil
+}
+
+// Value returns the box as a map with south, west, north and east keys.
+func (f *GeoBoxFilter) Value() any {
+	return map[string]any{
+		"south": f.South,
+		"west":  f.West,
+		"north": f.North,
+		"east":  f.East,
+	}
+}
+
+// Contains returns true if a coordinate is within the box.
+func (f *GeoBoxFilter) Contains(point types.GPS) bool {
+	if point.Latitude < f.South || point.Latitude > f.North {
+		return false
+	}
+	if f.West <= f.East {
+		return point.Longitude >= f.West && point.Longitude <= f.East
+	}
+	// Crosses the antimeridian
+	return point.Longitude >= f.West || point.Longitude <= f.East
+}
+
+// DistanceKm returns the great-circle distance between two coordinates in
+// kilometers (haversine formula).
+func DistanceKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// validateGeoAttribute checks that a geo filter targets a GPS attribute that
+// supports geo filtering.
+func validateGeoAttribute(op FilterOperation, attrDef types.AttributeDef) error {
+	if attrDef.Type != types.AttributeTypeGPS {
+		return &ValidationError{
+			FilterName:   attrDef.Name,
+			Reason:       fmt.Sprintf("invalid type: geo filters require gps type, got %s", attrDef.Type),
+			Operation:    op,
+			ExpectedType: types.AttributeTypeGPS,
+			ActualType:   attrDef.Type,
+		}
+	}
+
+	if err := validateOperation(op, attrDef.Filter); err != nil {
+		return &ValidationError{
+			FilterName: attrDef.Name,
+			Reason:     err.Error(),
+			Operation:  op,
+		}
+	}
+
+	return nil
+}
+
+// validateCoordinate checks that a latitude and longitude are in range.
+func validateCoordinate(lat, lng float64) error {
+	if math.IsNaN(lat) || lat < -90 || lat > 90 {
+		return fmt.Errorf("latitude %v out of range [-90, 90]", lat)
+	}
+	if math.IsNaN(lng) || lng < -180 || lng > 180 {
+		return fmt.Errorf("longitude %v out of range [-180, 180]", lng)
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package filters
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/yourname/mifind/internal/types"
@@ -12,15 +14,27 @@ import (
 // It handles the frontend's filter format and validates against attribute definitions.
 type Parser struct {
 	registry *types.TypeRegistry
+	location *time.Location
 }
 
-// NewParser creates a new filter parser.
+// NewParser creates a new filter parser. Date expressions are resolved in UTC
+// until SetLocation is called.
 func NewParser(registry *types.TypeRegistry) *Parser {
 	return &Parser{
 		registry: registry,
+		location: time.UTC,
 	}
 }
 
+// SetLocation sets the timezone that relative dates (yesterday, this-month)
+// and dates without a zone are resolved in. A nil location means UTC.
+func (p *Parser) SetLocation(loc *time.Location) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	p.location = loc
+}
+
 // ParseFilters parses filter data from an HTTP request into typed FilterValue objects.
 // The input format is a map of attribute names to filter specifications:
 //
@@ -28,9 +42,13 @@ func NewParser(registry *types.TypeRegistry) *Parser {
 //	  "extension": {"eq": "jpg"},
 //	  "size": {"gte": 1000, "lte": 100000},
 //	  "person": {"in": ["id1", "id2"]},
-//	  "created": {"min": 1234567890, "max": 1234567899}
+//	  "created": {"min": 1234567890, "max": 1234567899},
+//	  "modified": {"min": "-7d"}
 //	}
 //
+// Time values are Unix timestamps or date expressions (see ResolveDate): a
+// range covers the periods of its bounds, eq the period of its value.
+//
 // Returns a map of attribute names to FilterValue objects, or a MultiValidationError
 // if any filters fail to parse or validate.
 func (p *Parser) ParseFilters(filterData map[string]any) (map[string]FilterValue, error) {
@@ -45,38 +63,17 @@ func (p *Parser) ParseFilters(filterData map[string]any) (map[string]FilterValue
 	var validationErrors *MultiValidationError
 
 	for attrName, filterSpec := range filterData {
-		// Look up attribute definition
-		attrDef, exists := allAttrs[attrName]
-		if !exists {
-			validationErrors = p.addError(validationErrors, &ValidationError{
-				FilterName: attrName,
-				Reason:     fmt.Sprintf("unknown attribute: %q", attrName),
-			})
-			continue
-		}
-
-		// Check if attribute is filterable
-		if !attrDef.Filterable {
-			validationErrors = p.addError(validationErrors, &ValidationError{
-				FilterName: attrName,
-				Reason:     fmt.Sprintf("attribute %q is not filterable", attrName),
-			})
+		// Boolean groups are parsed by ParseExpression
+		if IsGroupKey(attrName) {
 			continue
 		}
 
-		// Parse the filter specification based on its format
-		filterValue, err := p.parseFilterSpec(attrName, filterSpec, attrDef)
+		filterValue, err := p.parseAttributeFilter(attrName, filterSpec, allAttrs)
 		if err != nil {
 			validationErrors = p.addError(validationErrors, err)
 			continue
 		}
 
-		// Validate the filter against the attribute definition
-		if err := filterValue.Validate(attrDef); err != nil {
-			validationErrors = p.addError(validationErrors, err)
-			continue
-		}
-
 		result[attrName] = filterValue
 	}
 
@@ -87,6 +84,164 @@ func (p *Parser) ParseFilters(filterData map[string]any) (map[string]FilterValue
 	return result, nil
 }
 
+// ParseExpression parses the boolean groups of filter data into an
+// expression. Groups combine filter maps, whose entries are ANDed and may
+// themselves be groups:
+//
+//	{
+//	  "$or":  [{"person": "alice"}, {"person": "bob"}],
+//	  "$not": {"album": "album-x"},
+//	  "$and": [{"$or": [...]}, {"$or": [...]}]
+//	}
+//
+// The top-level groups are ANDed with each other and with the attribute
+// filters returned by ParseFilters. Returns nil if filterData has no groups.
+func (p *Parser) ParseExpression(filterData map[string]any) (*Expr, error) {
+	allAttrs := p.registry.GetAllAttributes()
+
+	var groups []*Expr
+	for _, key := range sortedKeys(filterData) {
+		if !IsGroupKey(key) {
+			continue
+		}
+		group, err := p.parseGroup(key, filterData[key], allAttrs)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+
+	switch len(groups) {
+	case 0:
+		return nil, nil
+	case 1:
+		return groups[0], nil
+	default:
+		return And(groups...), nil
+	}
+}
+
+// parseGroup parses a "$and", "$or" or "$not" group.
+func (p *Parser) parseGroup(key string, value any, allAttrs map[string]types.AttributeDef) (*Expr, error) {
+	switch key {
+	case KeyAnd, KeyOr:
+		items, ok := value.([]any)
+		if !ok || len(items) == 0 {
+			return nil, &ValidationError{
+				FilterName: key,
+				Reason:     "expected a non-empty array of filter objects",
+			}
+		}
+
+		children := make([]*Expr, 0, len(items))
+		for i, item := range items {
+			itemMap, ok := item.(map[string]any)
+			if !ok {
+				return nil, &ValidationError{
+					FilterName: key,
+					Reason:     fmt.Sprintf("expected filter object at index %d, got %T", i, item),
+				}
+			}
+			child, err := p.parseExprMap(key, itemMap, allAttrs)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, child)
+		}
+
+		if key == KeyAnd {
+			return And(children...), nil
+		}
+		return Or(children...), nil
+
+	case KeyNot:
+		valueMap, ok := value.(map[string]any)
+		if !ok {
+			return nil, &ValidationError{
+				FilterName: key,
+				Reason:     fmt.Sprintf("expected a filter object, got %T", value),
+			}
+		}
+		child, err := p.parseExprMap(key, valueMap, allAttrs)
+		if err != nil {
+			return nil, err
+		}
+		return Not(child), nil
+
+	default:
+		return nil, &ValidationError{
+			FilterName: key,
+			Reason:     fmt.Sprintf("unknown boolean group %q (expected %s, %s or %s)", key, KeyAnd, KeyOr, KeyNot),
+		}
+	}
+}
+
+// parseExprMap parses a filter map inside a group, ANDing its entries.
+func (p *Parser) parseExprMap(groupKey string, filterData map[string]any, allAttrs map[string]types.AttributeDef) (*Expr, error) {
+	if len(filterData) == 0 {
+		return nil, &ValidationError{
+			FilterName: groupKey,
+			Reason:     "filter object cannot be empty",
+		}
+	}
+
+	children := make([]*Expr, 0, len(filterData))
+	for _, key := range sortedKeys(filterData) {
+		if IsGroupKey(key) {
+			group, err := p.parseGroup(key, filterData[key], allAttrs)
+			if err != nil {
+				return nil, err
+			}
+			children = append(children, group)
+			continue
+		}
+
+		filterValue, err := p.parseAttributeFilter(key, filterData[key], allAttrs)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, Condition(key, filterValue))
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return And(children...), nil
+}
+
+// parseAttributeFilter parses and validates the filter of a single attribute.
+func (p *Parser) parseAttributeFilter(attrName string, filterSpec any, allAttrs map[string]types.AttributeDef) (FilterValue, error) {
+	// Look up attribute definition
+	attrDef, exists := allAttrs[attrName]
+	if !exists {
+		return nil, &ValidationError{
+			FilterName: attrName,
+			Reason:     fmt.Sprintf("unknown attribute: %q", attrName),
+		}
+	}
+
+	// Check if attribute is filterable
+	if !attrDef.Filterable {
+		return nil, &ValidationError{
+			FilterName: attrName,
+			Reason:     fmt.Sprintf("attribute %q is not filterable", attrName),
+		}
+	}
+
+	// Parse the filter specification based on its format
+	filterValue, err := p.parseFilterSpec(attrName, filterSpec, attrDef)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the filter against the attribute definition
+	if err := filterValue.Validate(attrDef); err != nil {
+		return nil, err
+	}
+
+	return filterValue, nil
+}
+
 // parseFilterSpec parses a single filter specification into a FilterValue.
 // The filter specification can be in several formats:
 //
@@ -146,7 +301,7 @@ func (p *Parser) parseFilterSpec(attrName string, filterSpec any, attrDef types.
 
 	return nil, &ValidationError{
 		FilterName: attrName,
-		Reason:     "no valid filter operation found (expected eq, neq, gt, gte, lt, lte, contains, in, min, or max)",
+		Reason:     "no valid filter operation found (expected eq, neq, gt, gte, lt, lte, contains, in, min, max, radius, or bbox)",
 	}
 }
 
@@ -194,7 +349,7 @@ func (p *Parser) parseDateRangeFilter(attrName string, minValue, maxValue any) (
 	var minPtr, maxPtr *time.Time
 
 	if minValue != nil {
-		min, err := parseTime(attrName, "min", minValue)
+		min, _, err := p.parseDate(attrName, "min", minValue)
 		if err != nil {
 			return nil, err
 		}
@@ -202,7 +357,7 @@ func (p *Parser) parseDateRangeFilter(attrName string, minValue, maxValue any) (
 	}
 
 	if maxValue != nil {
-		max, err := parseTime(attrName, "max", maxValue)
+		_, max, err := p.parseDate(attrName, "max", maxValue)
 		if err != nil {
 			return nil, err
 		}
@@ -252,11 +407,33 @@ func (p *Parser) parseOperationFilter(attrName string, op FilterOperation, value
 		return NewBoolFilter(op, boolVal), nil
 
 	case types.AttributeTypeTime:
-		timeVal, err := parseTime(attrName, string(op), value)
+		if _, isExpr := value.(string); !isExpr {
+			timeVal, err := parseTime(attrName, string(op), value)
+			if err != nil {
+				return nil, err
+			}
+			return NewTimeFilter(op, timeVal), nil
+		}
+
+		start, end, err := p.parseDate(attrName, string(op), value)
 		if err != nil {
 			return nil, err
 		}
-		return NewTimeFilter(op, timeVal), nil
+		// A date expression covers a period, so bounds refer to its start or end
+		switch op {
+		case OpEq:
+			return NewDateRangeFilter(&start, &end), nil
+		case OpGt, OpLte:
+			return NewTimeFilter(op, end), nil
+		case OpGte, OpLt:
+			return NewTimeFilter(op, start), nil
+		default:
+			return nil, &ValidationError{
+				FilterName: attrName,
+				Reason:     fmt.Sprintf("date expressions don't support the %s operation", op),
+				Operation:  op,
+			}
+		}
 
 	case types.AttributeTypeStringSlice:
 		// String slice with "eq" or "neq" - treat as array membership
@@ -270,6 +447,9 @@ func (p *Parser) parseOperationFilter(attrName string, op FilterOperation, value
 		}
 		return NewStringSliceFilter(op, sliceVal), nil
 
+	case types.AttributeTypeGPS:
+		return parseGeoFilter(attrName, op, value)
+
 	default:
 		return nil, &ValidationError{
 			FilterName: attrName,
@@ -293,6 +473,57 @@ func (p *Parser) parseInFilter(attrName string, value any, attrDef types.Attribu
 	return NewStringSliceFilter(OpIn, sliceVal), nil
 }
 
+// parseGeoFilter parses a geo filter on a GPS attribute:
+//
+//	{"radius": {"lat": 48.14, "lng": 11.58, "km": 10}}
+//	{"bbox": {"south": 48.0, "west": 11.3, "north": 48.3, "east": 11.8}}
+func parseGeoFilter(attrName string, op FilterOperation, value any) (FilterValue, error) {
+	var keys []string
+	switch op {
+	case OpGeoRadius:
+		keys = []string{"lat", "lng", "km"}
+	case OpGeoBox:
+		keys = []string{"south", "west", "north", "east"}
+	default:
+		return nil, &ValidationError{
+			FilterName: attrName,
+			Reason:     fmt.Sprintf("gps filters only support radius and bbox operations, got %s", op),
+			Operation:  op,
+		}
+	}
+
+	spec, ok := value.(map[string]any)
+	if !ok {
+		return nil, &ValidationError{
+			FilterName: attrName,
+			Reason:     fmt.Sprintf("expected object with %v for %s, got %T", keys, op, value),
+			Operation:  op,
+		}
+	}
+
+	values := make([]float64, len(keys))
+	for i, key := range keys {
+		keyValue, exists := spec[key]
+		if !exists {
+			return nil, &ValidationError{
+				FilterName: attrName,
+				Reason:     fmt.Sprintf("%s filter requires %q", op, key),
+				Operation:  op,
+			}
+		}
+		f, err := parseFloat64(attrName, string(op)+"."+key, keyValue)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = f
+	}
+
+	if op == OpGeoRadius {
+		return NewGeoRadiusFilter(values[0], values[1], values[2]), nil
+	}
+	return NewGeoBoxFilter(values[0], values[1], values[2], values[3]), nil
+}
+
 // Helper functions for parsing specific types
 
 func parseInt64(attrName, op string, value any) (int64, error) {
@@ -367,14 +598,38 @@ func parseTime(attrName, op string, value any) (time.Time, error) {
 	default:
 		return time.Time{}, &ValidationError{
 			FilterName: attrName,
-			Reason:     fmt.Sprintf("expected numeric timestamp for %s, got %T", op, value),
+			Reason:     fmt.Sprintf("expected timestamp or date expression for %s, got %T", op, value),
 		}
 	}
 
 	return time.Unix(timestamp, 0), nil
 }
 
+// parseDate parses a Unix timestamp or a date expression and returns the first
+// and last second of the period it covers (the same time for timestamps).
+func (p *Parser) parseDate(attrName, op string, value any) (time.Time, time.Time, error) {
+	expr, ok := value.(string)
+	if !ok {
+		t, err := parseTime(attrName, op, value)
+		return t, t, err
+	}
+
+	start, end, err := ResolveDate(expr, time.Now().In(p.location))
+	if err != nil {
+		return time.Time{}, time.Time{}, &ValidationError{
+			FilterName: attrName,
+			Reason:     fmt.Sprintf("invalid time value for %s: %v", op, err),
+		}
+	}
+	return start, end, nil
+}
+
 func parseStringSlice(attrName string, value any) ([]string, error) {
+	// Filters built in Go (e.g. from Expr.Spec) hold []string
+	if values, ok := value.([]string); ok && len(values) > 0 {
+		return values, nil
+	}
+
 	slice, ok := value.([]any)
 	if !ok {
 		return nil, &ValidationError{
@@ -405,9 +660,15 @@ func parseStringSlice(attrName string, value any) ([]string, error) {
 	return result, nil
 }
 
+// sortedKeys returns the keys of filter data in order, so that expressions
+// are built deterministically.
+func sortedKeys(filterData map[string]any) []string {
+	return slices.Sorted(maps.Keys(filterData))
+}
+
 func isValidOperation(op FilterOperation) bool {
 	switch op {
-	case OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte, OpContains, OpIn:
+	case OpEq, OpNeq, OpGt, OpGte, OpLt, OpLte, OpContains, OpIn, OpGeoRadius, OpGeoBox:
 		return true
 	default:
 		return false
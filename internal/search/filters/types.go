@@ -19,6 +19,11 @@ const (
 	OpLte      FilterOperation = "lte"      // Less than or equal
 	OpContains FilterOperation = "contains" // Substring match
 	OpIn       FilterOperation = "in"       // In array
+
+	OpRange     FilterOperation = "range"      // Numeric min/max range (RangeFilter)
+	OpDateRange FilterOperation = "date-range" // Time min/max range (DateRangeFilter)
+	OpGeoRadius FilterOperation = "radius"     // Within a distance of a point (GeoRadiusFilter)
+	OpGeoBox    FilterOperation = "bbox"       // Within a bounding box (GeoBoxFilter)
 )
 
 // FilterValue is the interface for all typed filter values.
@@ -370,7 +375,7 @@ func NewRangeFilter(min, max *float64) *RangeFilter {
 
 // Operation returns the filter operation (always "range" for range filters).
 func (f *RangeFilter) Operation() FilterOperation {
-	return "range"
+	return OpRange
 }
 
 // Validate checks if the range filter is valid.
@@ -442,7 +447,7 @@ func NewDateRangeFilter(min, max *time.Time) *DateRangeFilter {
 
 // Operation returns the filter operation (always "date-range" for date range filters).
 func (f *DateRangeFilter) Operation() FilterOperation {
-	return "date-range"
+	return OpDateRange
 }
 
 // Validate checks if the date range filter is valid.
@@ -520,6 +525,10 @@ func validateOperation(op FilterOperation, filterConfig types.FilterConfig) erro
 		if !filterConfig.SupportsContains {
 			return fmt.Errorf("contains filtering is not supported for this attribute")
 		}
+	case OpGeoRadius, OpGeoBox:
+		if !filterConfig.SupportsGeo {
+			return fmt.Errorf("geo filtering is not supported for this attribute")
+		}
 	case OpIn:
 		// "in" operation is for array membership - check if eq is supported as fallback
 		// Many attributes support "in" even if they don't explicitly declare it
@@ -0,0 +1,244 @@
+package search
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// relevanceScoreAttribute is the attribute providers that support relevance
+// scores store them in (see provider.Provider.SupportsRelevanceScore).
+const relevanceScoreAttribute = "_score"
+
+// Fusion methods, as used in FusionConfig.Method.
+const (
+	// FusionRRF sums the reciprocal ranks of an entity in each ranked list
+	FusionRRF = "rrf"
+
+	// FusionNormalized sums the scores of an entity in each ranked list,
+	// normalized to 0-1 within the list
+	FusionNormalized = "normalized"
+)
+
+// FusionConfig configures the fusion ranking strategy.
+type FusionConfig struct {
+	// Method is how the ranked lists are combined: "rrf" or "normalized"
+	Method string `mapstructure:"method"`
+
+	// K dampens the difference between top ranks in reciprocal rank fusion
+	K float64 `mapstructure:"k"`
+
+	// NativeWeight weights each provider's own ordering of its results
+	NativeWeight float64 `mapstructure:"native_weight"`
+
+	// TextWeight weights mifind's text relevance ordering of all results
+	TextWeight float64 `mapstructure:"text_weight"`
+}
+
+// DefaultFusionConfig returns the default fusion configuration.
+func DefaultFusionConfig() FusionConfig {
+	return FusionConfig{
+		Method:       FusionRRF,
+		K:            60,
+		NativeWeight: 1.0,
+		TextWeight:   1.0,
+	}
+}
+
+// FusionRanker ranks entities by fusing ranked lists: each provider's own
+// ordering of its results, by their "_score" attribute if the provider sets
+// it or else by the order they were returned in, and mifind's text relevance
+// ordering of all results. Entities that several lists rank highly come
+// first, so a provider's native ordering (such as CLIP similarity) survives
+// federation. Type and provider weights are added to the fused score.
+type FusionRanker struct {
+	config RankingConfig
+	base   *InMemoryRanker
+}
+
+// NewFusionRanker creates a new fusion ranker with the given config. Unset
+// fusion parameters take their defaults.
+func NewFusionRanker(config RankingConfig) *FusionRanker {
+	defaults := DefaultFusionConfig()
+	switch config.Fusion.Method {
+	case FusionRRF, FusionNormalized:
+	default:
+		config.Fusion.Method = defaults.Method
+	}
+	if config.Fusion.K <= 0 {
+		config.Fusion.K = defaults.K
+	}
+	if config.Fusion.NativeWeight < 0 || config.Fusion.TextWeight < 0 ||
+		config.Fusion.NativeWeight+config.Fusion.TextWeight == 0 {
+		config.Fusion.NativeWeight = defaults.NativeWeight
+		config.Fusion.TextWeight = defaults.TextWeight
+	}
+
+	return &FusionRanker{
+		config: config,
+		base:   NewInMemoryRanker(config),
+	}
+}
+
+// Name returns the name of this ranking strategy.
+func (r *FusionRanker) Name() string {
+	return "fusion"
+}
+
+// rankedList is an ordering of entities, best first, with their scores
+// normalized to 0-1.
+type rankedList struct {
+	indexes []int
+	scores  []float64
+}
+
+// Rank scores and orders entities by fusing the providers' orderings with
+// text relevance to the query.
+func (r *FusionRanker) Rank(ctx context.Context, entities []EntityWithProvider, query SearchQuery) ([]RankedEntity, error) {
+	// Entities returned by several providers are fused into one
+	var unique []EntityWithProvider
+	seen := make(map[string]int, len(entities))
+	listed := make(map[[2]string]bool, len(entities))
+	byProvider := make(map[string][]int)
+	var providers []string
+	for _, entity := range entities {
+		index, ok := seen[entity.Entity.ID]
+		if !ok {
+			index = len(unique)
+			seen[entity.Entity.ID] = index
+			unique = append(unique, entity)
+		}
+		if _, ok := byProvider[entity.Provider]; !ok {
+			providers = append(providers, entity.Provider)
+		}
+		key := [2]string{entity.Provider, entity.Entity.ID}
+		if !listed[key] {
+			listed[key] = true
+			byProvider[entity.Provider] = append(byProvider[entity.Provider], index)
+		}
+	}
+
+	fused := make([]float64, len(unique))
+	weight := 0.0
+	if r.config.Fusion.NativeWeight > 0 {
+		for _, provider := range providers {
+			r.fuse(fused, nativeList(unique, byProvider[provider]), r.config.Fusion.NativeWeight)
+		}
+		weight += r.config.Fusion.NativeWeight
+	}
+	if query.Query != "" && r.config.Fusion.TextWeight > 0 {
+		r.fuse(fused, r.textList(unique, query.Query), r.config.Fusion.TextWeight)
+		weight += r.config.Fusion.TextWeight
+	}
+
+	// Normalize so that the top of a single list scores 1
+	best := weight
+	if r.config.Fusion.Method == FusionRRF {
+		best = weight / (r.config.Fusion.K + 1)
+	}
+
+	ranked := make([]RankedEntity, len(unique))
+	for i, entity := range unique {
+		ranked[i] = RankedEntity{
+			Entity:   entity.Entity,
+			Score:    fused[i]/best + r.base.weightScore(entity, query),
+			Provider: entity.Provider,
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Entity.Timestamp.After(ranked[j].Entity.Timestamp)
+	})
+
+	return ranked, nil
+}
+
+// fuse adds the weighted contribution of a ranked list to the fused scores.
+func (r *FusionRanker) fuse(fused []float64, list rankedList, weight float64) {
+	for rank, index := range list.indexes {
+		if r.config.Fusion.Method == FusionRRF {
+			fused[index] += weight / (r.config.Fusion.K + float64(rank+1))
+		} else {
+			fused[index] += weight * list.scores[rank]
+		}
+	}
+}
+
+// textList orders the entities that match the query text by text relevance.
+func (r *FusionRanker) textList(entities []EntityWithProvider, query string) rankedList {
+	var list rankedList
+	scores := make(map[int]float64)
+	for i, entity := range entities {
+		if score := r.base.textRelevanceScore(entity.Entity, query); score > 0 {
+			list.indexes = append(list.indexes, i)
+			scores[i] = score
+		}
+	}
+	if len(list.indexes) == 0 {
+		return list
+	}
+
+	sort.SliceStable(list.indexes, func(i, j int) bool {
+		return scores[list.indexes[i]] > scores[list.indexes[j]]
+	})
+	top := scores[list.indexes[0]]
+	for _, index := range list.indexes {
+		list.scores = append(list.scores, scores[index]/top)
+	}
+	return list
+}
+
+// nativeList orders a provider's entities as the provider ranked them: by
+// their "_score" attribute, or by the order they were returned in if the
+// provider doesn't score them. Entities without a score come after the
+// scored ones.
+func nativeList(entities []EntityWithProvider, indexes []int) rankedList {
+	list := rankedList{indexes: slices.Clone(indexes)}
+
+	scores := make(map[int]float64)
+	for _, index := range indexes {
+		if value, ok := entities[index].Entity.Attributes[relevanceScoreAttribute]; ok {
+			if score, ok := numericValue(value); ok {
+				scores[index] = score
+			}
+		}
+	}
+
+	if len(scores) == 0 {
+		// Positional scores: 1 for the first result down to 1/n for the last
+		n := float64(len(indexes))
+		for rank := range indexes {
+			list.scores = append(list.scores, (n-float64(rank))/n)
+		}
+		return list
+	}
+
+	sort.SliceStable(list.indexes, func(i, j int) bool {
+		x, xOK := scores[list.indexes[i]]
+		y, yOK := scores[list.indexes[j]]
+		if xOK != yOK {
+			return xOK
+		}
+		return x > y
+	})
+
+	// Min-max normalize the provider's scores, whatever their scale
+	values := slices.Collect(maps.Values(scores))
+	low, high := slices.Min(values), slices.Max(values)
+	for _, index := range list.indexes {
+		score, ok := scores[index]
+		switch {
+		case !ok:
+			list.scores = append(list.scores, 0)
+		case high == low:
+			list.scores = append(list.scores, 1)
+		default:
+			list.scores = append(list.scores, (score-low)/(high-low))
+		}
+	}
+	return list
+}
@@ -0,0 +1,288 @@
+package search
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yourname/mifind/internal/types"
+)
+
+// FuzzyConfig configures typo-tolerant matching in the in-memory ranker.
+type FuzzyConfig struct {
+	// Enabled scores words close to the query words as weaker matches
+	Enabled bool `mapstructure:"enabled"`
+
+	// MaxEdits is how many typos a word of 8 or more letters may have.
+	// Shorter words may have one, words under 4 letters none (0 = no typos)
+	MaxEdits int `mapstructure:"max_edits"`
+
+	// MinSimilarity is the trigram similarity (0-1) from which words match
+	// regardless of their edit distance (0 = edit distance only)
+	MinSimilarity float64 `mapstructure:"min_similarity"`
+}
+
+// DefaultFuzzyConfig returns the default fuzzy matching configuration.
+func DefaultFuzzyConfig() FuzzyConfig {
+	return FuzzyConfig{
+		Enabled:       true,
+		MaxEdits:      2,
+		MinSimilarity: 0.5,
+	}
+}
+
+// allowedEdits returns how many edits a word of n letters may have.
+func allowedEdits(n, maxEdits int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 8:
+		return min(maxEdits, 1)
+	default:
+		return maxEdits
+	}
+}
+
+// wordSimilarity returns how closely word matches the query term, from 1 for
+// the same word to 0 for no match. Words match within the allowed edit
+// distance, or by trigram similarity.
+func wordSimilarity(term, word string, config FuzzyConfig) float64 {
+	if term == word {
+		return 1
+	}
+
+	n := utf8.RuneCountInString(term)
+	allowed := allowedEdits(n, config.MaxEdits)
+	if allowed > 0 && abs(n-utf8.RuneCountInString(word)) <= allowed {
+		if d := editDistance(term, word); d <= allowed {
+			return 1 - float64(d)/float64(n)
+		}
+	}
+
+	if n >= 4 && config.MinSimilarity > 0 {
+		if similarity := trigramSimilarity(term, word); similarity >= config.MinSimilarity {
+			return similarity
+		}
+	}
+	return 0
+}
+
+// fuzzyFieldScore returns how closely the words of a field match the query
+// words: the mean similarity of each query word to its closest field word.
+func fuzzyFieldScore(queryWords, fieldWords []string, config FuzzyConfig) float64 {
+	if len(queryWords) == 0 || len(fieldWords) == 0 {
+		return 0
+	}
+
+	total := 0.0
+	for _, term := range queryWords {
+		best := 0.0
+		for _, word := range fieldWords {
+			best = max(best, wordSimilarity(term, word, config))
+		}
+		total += best
+	}
+	return total / float64(len(queryWords))
+}
+
+// queryWords returns the words of a query without stop words.
+func queryWords(query string) []string {
+	words := splitWords(query)
+	kept := words[:0]
+	for _, word := range words {
+		if !stopWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return kept
+}
+
+// editDistance returns the optimal string alignment distance of a and b: how
+// many insertions, deletions, substitutions and swaps of adjacent letters
+// turn one into the other.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+
+	// Rows i-2, i-1 and i of the distance matrix
+	before := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				cur[j] = min(cur[j], before[j-2]+1)
+			}
+		}
+		before, prev, cur = prev, cur, before
+	}
+	return prev[len(t)]
+}
+
+// trigramSimilarity returns the Jaccard similarity of the trigrams of a and
+// b, padded so that the first and last letters count too.
+func trigramSimilarity(a, b string) float64 {
+	x, y := trigrams(a), trigrams(b)
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for gram := range x {
+		if y[gram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(x)+len(y)-shared)
+}
+
+// trigrams returns the set of three-letter sequences of a word padded with
+// a space on each side: "cat" → " ca", "cat", "at ".
+func trigrams(word string) map[string]bool {
+	runes := []rune(" " + word + " ")
+	grams := make(map[string]bool, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		grams[string(runes[i:i+3])] = true
+	}
+	return grams
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// suggestMaxEdits is how many typos a suggestion corrects in a long word.
+const suggestMaxEdits = 2
+
+// vocabulary holds the words of the titles and search tokens of recently
+// seen entities, for spelling suggestions. When full, the least recently
+// seen words are forgotten first.
+type vocabulary struct {
+	mu   sync.Mutex
+	size int
+
+	// words indexes the elements of order by word
+	words map[string]*list.Element
+
+	// order holds *vocabularyWord values, most recently seen first
+	order *list.List
+}
+
+// vocabularyWord is a word of the vocabulary and how often it was seen.
+type vocabularyWord struct {
+	word  string
+	count int
+}
+
+// newVocabulary creates a vocabulary of at most size words.
+func newVocabulary(size int) *vocabulary {
+	return &vocabulary{
+		size:  size,
+		words: make(map[string]*list.Element),
+		order: list.New(),
+	}
+}
+
+// observe adds the words of entity titles and search tokens.
+func (v *vocabulary) observe(entities []types.Entity) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for _, entity := range entities {
+		v.add(entity.Title)
+		for _, token := range entity.SearchTokens {
+			v.add(token)
+		}
+	}
+}
+
+// add adds the words of text. Stop words, words with digits and words under
+// 3 letters are left out, since they aren't worth suggesting.
+func (v *vocabulary) add(text string) {
+	for _, word := range splitWords(text) {
+		if stopWords[word] || utf8.RuneCountInString(word) < 3 || strings.ContainsFunc(word, unicode.IsDigit) {
+			continue
+		}
+
+		if element, ok := v.words[word]; ok {
+			element.Value.(*vocabularyWord).count++
+			v.order.MoveToFront(element)
+			continue
+		}
+		v.words[word] = v.order.PushFront(&vocabularyWord{word: word, count: 1})
+
+		if v.order.Len() > v.size {
+			oldest := v.order.Back()
+			v.order.Remove(oldest)
+			delete(v.words, oldest.Value.(*vocabularyWord).word)
+		}
+	}
+}
+
+// suggest returns the query with its unknown words replaced by the closest
+// known ones, or "" when no word was replaced. Among equally close words the
+// most often seen is suggested.
+func (v *vocabulary) suggest(query string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	words := splitWords(query)
+	corrected := false
+	for i, word := range words {
+		if _, known := v.words[word]; known || stopWords[word] || strings.ContainsFunc(word, unicode.IsDigit) {
+			continue
+		}
+		if closest := v.closest(word); closest != "" {
+			words[i] = closest
+			corrected = true
+		}
+	}
+
+	if !corrected {
+		return ""
+	}
+	return strings.Join(words, " ")
+}
+
+// closest returns the known word nearest to word within the allowed edit
+// distance, or "" if there is none.
+func (v *vocabulary) closest(word string) string {
+	n := utf8.RuneCountInString(word)
+	allowed := allowedEdits(n, suggestMaxEdits)
+	if allowed == 0 {
+		return ""
+	}
+
+	var best *vocabularyWord
+	bestDistance := allowed + 1
+	for element := v.order.Front(); element != nil; element = element.Next() {
+		candidate := element.Value.(*vocabularyWord)
+		if abs(n-utf8.RuneCountInString(candidate.word)) > allowed {
+			continue
+		}
+		d := editDistance(word, candidate.word)
+		if d < bestDistance || (d == bestDistance && best != nil && candidate.count > best.count) {
+			best, bestDistance = candidate, d
+		}
+	}
+
+	if best == nil {
+		return ""
+	}
+	return best.word
+}
@@ -3,6 +3,7 @@ package search
 import (
 	"context"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/yourname/mifind/internal/types"
@@ -54,12 +55,22 @@ func (r *InMemoryRanker) Rank(ctx context.Context, entities []EntityWithProvider
 
 // scoreEntity calculates a relevance score for an entity.
 func (r *InMemoryRanker) scoreEntity(entity EntityWithProvider, query SearchQuery) float64 {
+	// Text relevance score (weight: 1.0)
+	return r.textRelevanceScore(entity.Entity, query.Query) + r.boostScore(entity, query)
+}
+
+// boostScore calculates the part of the score that doesn't depend on the
+// query text: type and provider weights and recency.
+func (r *InMemoryRanker) boostScore(entity EntityWithProvider, query SearchQuery) float64 {
+	// Recency score (weight: 0.3)
+	return r.weightScore(entity, query) + r.recencyScore(entity.Entity.Timestamp)*0.3
+}
+
+// weightScore calculates the configured type and provider weights of an entity.
+func (r *InMemoryRanker) weightScore(entity EntityWithProvider, query SearchQuery) float64 {
 	e := entity.Entity
 	score := 0.0
 
-	// Text relevance score (weight: 1.0)
-	score += r.textRelevanceScore(e, query.Query)
-
 	// Type boost (weight: 0.5)
 	if typeWeight, ok := r.config.TypeWeights[e.Type]; ok {
 		score += typeWeight * 0.5
@@ -68,13 +79,10 @@ func (r *InMemoryRanker) scoreEntity(entity EntityWithProvider, query SearchQuer
 	}
 
 	// Provider boost
-	if providerWeight, ok := r.config.ProviderWeights[entity.Provider]; ok {
+	if providerWeight, ok := lookupProviderWeight(r.config.ProviderWeights, entity.Provider); ok {
 		score += providerWeight
 	}
 
-	// Recency score (weight: 0.3)
-	score += r.recencyScore(e.Timestamp) * 0.3
-
 	return score
 }
 
@@ -87,6 +95,13 @@ func (r *InMemoryRanker) textRelevanceScore(entity types.Entity, query string) f
 	score := 0.0
 	queryLower := toLower(query)
 
+	// Words close to the query words count as weaker matches in fields that
+	// don't contain the query, so that typos still rank matches first
+	var words []string
+	if r.config.Fuzzy.Enabled {
+		words = queryWords(query)
+	}
+
 	// Check title match
 	titleLower := toLower(entity.Title)
 	if contains(titleLower, queryLower) {
@@ -97,6 +112,8 @@ func (r *InMemoryRanker) textRelevanceScore(entity types.Entity, query string) f
 		} else {
 			score += 1.0 // Contains match
 		}
+	} else if len(words) > 0 {
+		score += 0.8 * fuzzyFieldScore(words, splitWords(titleLower), r.config.Fuzzy)
 	}
 
 	// Check description match
@@ -104,16 +121,24 @@ func (r *InMemoryRanker) textRelevanceScore(entity types.Entity, query string) f
 		descLower := toLower(entity.Description)
 		if contains(descLower, queryLower) {
 			score += 0.5
+		} else if len(words) > 0 {
+			score += 0.4 * fuzzyFieldScore(words, splitWords(descLower), r.config.Fuzzy)
 		}
 	}
 
 	// Check search tokens
+	tokenMatched := false
 	for _, token := range entity.SearchTokens {
 		tokenLower := toLower(token)
 		if contains(tokenLower, queryLower) {
 			score += 0.3
+			tokenMatched = true
 		}
 	}
+	if !tokenMatched && len(words) > 0 && len(entity.SearchTokens) > 0 {
+		tokenWords := splitWords(strings.Join(entity.SearchTokens, " "))
+		score += 0.2 * fuzzyFieldScore(words, tokenWords, r.config.Fuzzy)
+	}
 
 	// Check attributes
 	for key, val := range entity.Attributes {
@@ -0,0 +1,123 @@
+package search
+
+import (
+	"maps"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/search/filters"
+)
+
+// pushdown splits a query's filters into the filters a provider evaluates
+// itself and the residual filters the federator applies to its results.
+//
+// Pushed filters use the SearchQuery.Filters encoding providers understand:
+// a plain value for equality, a []string for membership, a {"min", "max"}
+// map for ranges and a {"radius": {...}} or {"bbox": {...}} map for geo
+// filters. A filter is pushed when this encoding can express its
+// operator and the provider's capability for the attribute supports it.
+// Inequality and substring filters can't be expressed, so they are always
+// residual. Residual values are filters.FilterValue for typed filters, which
+// ApplyFilters evaluates with their operator.
+//
+// The boolean groups of query.FilterExpr are pushed as a whole, in their
+// filters.Expr.Spec encoding, when the provider evaluates filter expressions
+// (exprs) and supports every condition in them. Otherwise they are residual.
+func pushdown(query SearchQuery, capabilities map[string]provider.FilterCapability, exprs bool) (pushed, residual map[string]any) {
+	pushed = make(map[string]any)
+	residual = make(map[string]any)
+
+	if query.FilterExpr != nil {
+		if exprs && supportsExpr(query.FilterExpr, capabilities) {
+			maps.Copy(pushed, query.FilterExpr.Spec())
+		} else {
+			residual[filters.KeyAnd] = query.FilterExpr
+		}
+	}
+
+	// Untyped filters are passed as they are to providers that know the attribute
+	if query.TypedFilters == nil {
+		for name, value := range query.Filters {
+			if _, ok := capabilities[name]; ok {
+				pushed[name] = value
+			} else {
+				residual[name] = value
+			}
+		}
+		return pushed, residual
+	}
+
+	for name, filter := range query.TypedFilters {
+		capability, ok := capabilities[name]
+		if !ok {
+			residual[name] = filter
+			continue
+		}
+
+		value, exact, supported := pushdownValue(filter, capability)
+		if supported {
+			pushed[name] = value
+		}
+		if !supported || !exact {
+			residual[name] = filter
+		}
+	}
+	return pushed, residual
+}
+
+// pushdownValue encodes a typed filter for a provider. exact is false when
+// the provider is sent a broader filter, which is then also applied as a
+// residual filter: strict bounds are sent as inclusive ranges, and "contains
+// all" list filters as "contains any".
+func pushdownValue(filter filters.FilterValue, capability provider.FilterCapability) (value any, exact, supported bool) {
+	switch filter.Operation() {
+	case filters.OpEq:
+		if _, isList := filter.(*filters.StringSliceFilter); isList {
+			return filter.Value(), false, capability.SupportsEq
+		}
+		return filter.Value(), true, capability.SupportsEq
+	case filters.OpIn:
+		return filter.Value(), true, capability.SupportsEq
+	case filters.OpGte:
+		return map[string]any{"min": filter.Value()}, true, capability.SupportsRange
+	case filters.OpGt:
+		return map[string]any{"min": filter.Value()}, false, capability.SupportsRange
+	case filters.OpLte:
+		return map[string]any{"max": filter.Value()}, true, capability.SupportsRange
+	case filters.OpLt:
+		return map[string]any{"max": filter.Value()}, false, capability.SupportsRange
+	case filters.OpRange, filters.OpDateRange:
+		return filter.Value(), true, capability.SupportsRange
+	case filters.OpGeoRadius, filters.OpGeoBox:
+		return map[string]any{string(filter.Operation()): filter.Value()}, true, capability.SupportsGeo
+	default:
+		return nil, false, false
+	}
+}
+
+// supportsExpr reports whether a provider supports the operator of every
+// condition in a filter expression.
+func supportsExpr(expr *filters.Expr, capabilities map[string]provider.FilterCapability) bool {
+	for _, condition := range expr.Conditions() {
+		capability, ok := capabilities[condition.Attribute]
+		if !ok {
+			return false
+		}
+
+		// Substring filters are never pushed, like ungrouped ones
+		var supported bool
+		switch condition.Filter.Operation() {
+		case filters.OpEq, filters.OpIn:
+			supported = capability.SupportsEq
+		case filters.OpNeq:
+			supported = capability.SupportsNeq
+		case filters.OpGt, filters.OpGte, filters.OpLt, filters.OpLte, filters.OpRange, filters.OpDateRange:
+			supported = capability.SupportsRange
+		case filters.OpGeoRadius, filters.OpGeoBox:
+			supported = capability.SupportsGeo
+		}
+		if !supported {
+			return false
+		}
+	}
+	return true
+}
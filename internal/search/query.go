@@ -2,6 +2,8 @@ package search
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/yourname/mifind/internal/provider"
 	"github.com/yourname/mifind/internal/search/filters"
@@ -17,6 +19,10 @@ type TypedSearchQuery struct {
 	// TypedFilters specifies validated attribute filters with type-safe values
 	TypedFilters map[string]filters.FilterValue
 
+	// FilterExpr holds boolean filter groups (OR, NOT, nested), which must
+	// match in addition to TypedFilters (nil for none)
+	FilterExpr *filters.Expr
+
 	// Type filters by entity type
 	Type string
 
@@ -40,6 +46,10 @@ type TypedSearchQuery struct {
 
 	// MaxDepth specifies how deep to follow relationships
 	MaxDepth int
+
+	// Sort orders the results by attributes instead of score (nil for score
+	// order)
+	Sort []SortClause
 }
 
 // NewTypedSearchQuery creates a new typed search query with default values.
@@ -59,6 +69,34 @@ func (q *TypedSearchQuery) WithFilter(name string, filter filters.FilterValue) *
 	return q
 }
 
+// Merge adds the text, type and filters of other to this query. It fails if
+// both queries set different types or filter the same attribute.
+func (q *TypedSearchQuery) Merge(other *TypedSearchQuery) error {
+	if other.Type != "" {
+		if q.Type != "" && q.Type != other.Type {
+			return fmt.Errorf("conflicting types %q and %q", q.Type, other.Type)
+		}
+		q.Type = other.Type
+	}
+
+	for name, filter := range other.TypedFilters {
+		if _, exists := q.TypedFilters[name]; exists {
+			return fmt.Errorf("attribute %q is filtered more than once", name)
+		}
+		q.WithFilter(name, filter)
+	}
+
+	switch {
+	case q.FilterExpr == nil:
+		q.FilterExpr = other.FilterExpr
+	case other.FilterExpr != nil:
+		q.FilterExpr = filters.And(q.FilterExpr, other.FilterExpr)
+	}
+
+	q.Query = strings.TrimSpace(q.Query + " " + other.Query)
+	return nil
+}
+
 // Validate validates the query against the type registry.
 // Returns an error if:
 // - The type is not registered
@@ -95,6 +133,12 @@ func (q *TypedSearchQuery) Validate(registry *types.TypeRegistry) error {
 		}
 	}
 
+	if q.FilterExpr != nil {
+		if err := q.FilterExpr.Validate(allAttrs); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -106,6 +150,11 @@ func (q *TypedSearchQuery) ToProviderQuery() provider.SearchQuery {
 	for name, filter := range q.TypedFilters {
 		legacyFilters[name] = filter.Value()
 	}
+	if q.FilterExpr != nil {
+		for key, value := range q.FilterExpr.Spec() {
+			legacyFilters[key] = value
+		}
+	}
 
 	limit := q.ProviderLimit
 	if limit == 0 && q.Limit > 0 {
@@ -134,6 +183,8 @@ func (q *TypedSearchQuery) ToSearchQuery() SearchQuery {
 	return SearchQuery{
 		Query:            q.Query,
 		Filters:          legacyFilters,
+		TypedFilters:     q.TypedFilters,
+		FilterExpr:       q.FilterExpr,
 		Type:             q.Type,
 		RelationshipType: q.RelationshipType,
 		Limit:            q.Limit,
@@ -142,14 +193,17 @@ func (q *TypedSearchQuery) ToSearchQuery() SearchQuery {
 		TypeWeights:      q.TypeWeights,
 		IncludeRelated:   q.IncludeRelated,
 		MaxDepth:         q.MaxDepth,
+		Sort:             q.Sort,
 	}
 }
 
 // ParseAndValidate creates a TypedSearchQuery from raw filter data and validates it.
 // This is a convenience function for the common case of parsing HTTP request data.
-func ParseAndValidate(queryStr string, filterData map[string]any, registry *types.TypeRegistry) (*TypedSearchQuery, error) {
+// Date expressions in the filters are resolved in loc (UTC if nil).
+func ParseAndValidate(queryStr string, filterData map[string]any, registry *types.TypeRegistry, loc *time.Location) (*TypedSearchQuery, error) {
 	// Create parser
 	parser := filters.NewParser(registry)
+	parser.SetLocation(loc)
 
 	// Parse filters
 	typedFilters, err := parser.ParseFilters(filterData)
@@ -157,10 +211,17 @@ func ParseAndValidate(queryStr string, filterData map[string]any, registry *type
 		return nil, fmt.Errorf("failed to parse filters: %w", err)
 	}
 
+	// Parse boolean filter groups
+	filterExpr, err := parser.ParseExpression(filterData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse filters: %w", err)
+	}
+
 	// Create typed query
 	q := &TypedSearchQuery{
 		Query:        queryStr,
 		TypedFilters: typedFilters,
+		FilterExpr:   filterExpr,
 	}
 
 	// Validate
@@ -0,0 +1,471 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yourname/mifind/internal/search/filters"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// QueryError is a syntax or validation error in a query string. Pos and End
+// are the character offsets (0-based, in runes) of the offending part.
+type QueryError struct {
+	Pos    int
+	End    int
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("at character %d: %s", e.Pos+1, e.Reason)
+}
+
+// queryOperators maps query language operators to filter operations, longest
+// first so that ">=" isn't read as ">".
+var queryOperators = []struct {
+	token string
+	op    filters.FilterOperation
+}{
+	{">=", filters.OpGte},
+	{"<=", filters.OpLte},
+	{"!=", filters.OpNeq},
+	{">", filters.OpGt},
+	{"<", filters.OpLt},
+	{"~", filters.OpContains},
+	{":", filters.OpEq},
+}
+
+// negatedOperations maps operations to their negation for "-" terms.
+var negatedOperations = map[filters.FilterOperation]filters.FilterOperation{
+	filters.OpEq:  filters.OpNeq,
+	filters.OpNeq: filters.OpEq,
+	filters.OpGt:  filters.OpLte,
+	filters.OpGte: filters.OpLt,
+	filters.OpLt:  filters.OpGte,
+	filters.OpLte: filters.OpGt,
+}
+
+// sizeUnits are the multipliers of the size suffixes accepted for integers.
+var sizeUnits = map[string]float64{
+	"b":  1,
+	"k":  1 << 10,
+	"kb": 1 << 10,
+	"m":  1 << 20,
+	"mb": 1 << 20,
+	"g":  1 << 30,
+	"gb": 1 << 30,
+	"t":  1 << 40,
+	"tb": 1 << 40,
+}
+
+// queryTerm is a single whitespace-separated term of a query string.
+type queryTerm struct {
+	pos, end int
+
+	// negated is set for terms starting with "-"
+	negated bool
+
+	// attribute is empty for free text terms
+	attribute string
+	op        filters.FilterOperation
+	value     string
+	valuePos  int
+
+	// quoted values are taken literally, not as ranges or lists
+	quoted bool
+}
+
+// ParseQuery parses a search box query into a TypedSearchQuery, e.g.
+//
+//	type:photo camera:"Canon EOS" size>10MB modified:2024-01..2024-06 -extension:gif vacation
+//
+// Terms of the form attribute:value filter on an attribute, with the
+// operators :, !=, >, >=, <, <= and ~ (contains); a leading "-" negates a
+// filter. Values can be quoted, ranges are written min..max (either bound
+// may be left out), lists a,b,c. Integers accept size suffixes (KB, MB, GB,
+// TB, powers of 1024). Dates are date expressions such as 2024, 2024-Q2,
+// 2024-06-15, RFC 3339, -7d, yesterday or last-month (see
+// filters.ResolveDate), resolved in loc (UTC if nil) and covering their whole
+// period. type:name sets the entity type, resolved by full name or by its
+// last segments ("photo" for "media.asset.photo"). All other terms make up
+// the query text.
+//
+// Attributes are resolved through attrs, which should include the
+// attribute extensions of providers; if nil, through the registry's
+// attributes. Errors are *QueryError values.
+func ParseQuery(input string, registry *types.TypeRegistry, attrs map[string]types.AttributeDef, loc *time.Location) (*TypedSearchQuery, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := time.Now().In(loc)
+
+	terms, err := scanQuery([]rune(input))
+	if err != nil {
+		return nil, err
+	}
+
+	q := NewTypedSearchQuery("")
+	if attrs == nil {
+		attrs = registry.GetAllAttributes()
+	}
+	var text []string
+	var typePos int
+
+	for _, term := range terms {
+		if term.attribute == "" {
+			if term.negated {
+				return nil, &QueryError{Pos: term.pos, End: term.end, Reason: "only filters can be negated"}
+			}
+			text = append(text, term.value)
+			continue
+		}
+
+		if term.attribute == types.AttrType {
+			if term.negated || term.op != filters.OpEq {
+				return nil, &QueryError{Pos: term.pos, End: term.end, Reason: "type only supports type:name"}
+			}
+			if q.Type != "" {
+				return nil, &QueryError{Pos: term.pos, End: term.end, Reason: fmt.Sprintf("type is already set at character %d", typePos+1)}
+			}
+			typeName, err := resolveType(registry, term.value)
+			if err != nil {
+				return nil, &QueryError{Pos: term.valuePos, End: term.end, Reason: err.Error()}
+			}
+			q.Type = typeName
+			typePos = term.pos
+			continue
+		}
+
+		attrDef, ok := attrs[term.attribute]
+		if !ok {
+			return nil, &QueryError{Pos: term.pos, End: term.end, Reason: fmt.Sprintf("unknown attribute %q", term.attribute)}
+		}
+		if !attrDef.Filterable {
+			return nil, &QueryError{Pos: term.pos, End: term.end, Reason: fmt.Sprintf("attribute %q is not filterable", term.attribute)}
+		}
+		if _, exists := q.TypedFilters[term.attribute]; exists {
+			return nil, &QueryError{Pos: term.pos, End: term.end, Reason: fmt.Sprintf("attribute %q is filtered more than once, use a range min..max", term.attribute)}
+		}
+		// Filter names in validation errors refer to the attribute as written
+		attrDef.Name = term.attribute
+
+		filter, err := termFilter(term, attrDef, now)
+		if err != nil {
+			return nil, &QueryError{Pos: term.valuePos, End: term.end, Reason: err.Error()}
+		}
+		if err := filter.Validate(attrDef); err != nil {
+			reason := err.Error()
+			if valErr, ok := err.(*filters.ValidationError); ok {
+				reason = valErr.Reason
+			}
+			return nil, &QueryError{Pos: term.pos, End: term.end, Reason: fmt.Sprintf("%s: %s", term.attribute, reason)}
+		}
+		q.TypedFilters[term.attribute] = filter
+	}
+
+	q.Query = strings.Join(text, " ")
+	return q, nil
+}
+
+// scanQuery splits a query string into terms.
+func scanQuery(input []rune) ([]queryTerm, error) {
+	var terms []queryTerm
+	i := 0
+	for {
+		for i < len(input) && unicode.IsSpace(input[i]) {
+			i++
+		}
+		if i == len(input) {
+			return terms, nil
+		}
+
+		term := queryTerm{pos: i}
+		if input[i] == '-' && i+1 < len(input) && !unicode.IsSpace(input[i+1]) {
+			term.negated = true
+			i++
+		}
+
+		// attribute name followed by an operator
+		nameEnd := i
+		for nameEnd < len(input) && isAttributeRune(input[nameEnd]) {
+			nameEnd++
+		}
+		if nameEnd > i {
+			if op, width, ok := scanOperator(input[nameEnd:]); ok {
+				term.attribute = string(input[i:nameEnd])
+				term.op = op
+				i = nameEnd + width
+			}
+		}
+
+		term.valuePos = i
+		value, next, quoted, err := scanValue(input, i)
+		if err != nil {
+			return nil, err
+		}
+		if value == "" && term.attribute != "" {
+			return nil, &QueryError{Pos: term.pos, End: next, Reason: fmt.Sprintf("missing value for %q", term.attribute)}
+		}
+		term.value = value
+		term.quoted = quoted
+		term.end = next
+		i = next
+		terms = append(terms, term)
+	}
+}
+
+// scanOperator reads an operator at the start of input. "name:>value" is read
+// as "name>value".
+func scanOperator(input []rune) (filters.FilterOperation, int, bool) {
+	s := string(input)
+	for _, candidate := range queryOperators {
+		if !strings.HasPrefix(s, candidate.token) {
+			continue
+		}
+		if candidate.op == filters.OpEq {
+			for _, other := range queryOperators {
+				if other.op != filters.OpEq && strings.HasPrefix(s[1:], other.token) {
+					return other.op, 1 + len([]rune(other.token)), true
+				}
+			}
+		}
+		return candidate.op, len([]rune(candidate.token)), true
+	}
+	return "", 0, false
+}
+
+// scanValue reads a quoted or bare value starting at i and returns it with
+// the offset after it.
+func scanValue(input []rune, i int) (string, int, bool, error) {
+	if i < len(input) && input[i] == '"' {
+		var value strings.Builder
+		for j := i + 1; j < len(input); j++ {
+			switch {
+			case input[j] == '\\' && j+1 < len(input):
+				j++
+				value.WriteRune(input[j])
+			case input[j] == '"':
+				if j+1 < len(input) && !unicode.IsSpace(input[j+1]) {
+					return "", 0, false, &QueryError{Pos: j + 1, End: j + 2, Reason: "expected a space after the closing quote"}
+				}
+				return value.String(), j + 1, true, nil
+			default:
+				value.WriteRune(input[j])
+			}
+		}
+		return "", 0, false, &QueryError{Pos: i, End: len(input), Reason: "unterminated quote"}
+	}
+
+	j := i
+	for j < len(input) && !unicode.IsSpace(input[j]) {
+		j++
+	}
+	return string(input[i:j]), j, false, nil
+}
+
+// isAttributeRune reports whether r can be part of an attribute name.
+func isAttributeRune(r rune) bool {
+	return r == '_' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// termFilter builds the filter of an attribute term for the attribute's type.
+func termFilter(term queryTerm, attrDef types.AttributeDef, now time.Time) (filters.FilterValue, error) {
+	op := term.op
+	if term.negated {
+		negated, ok := negatedOperations[op]
+		if !ok {
+			return nil, fmt.Errorf("%s filters can't be negated", op)
+		}
+		op = negated
+	}
+
+	if lower, upper, ok := strings.Cut(term.value, ".."); ok && !term.quoted {
+		if op != filters.OpEq || term.negated {
+			return nil, fmt.Errorf("ranges only support attribute:min..max")
+		}
+		return rangeFilter(lower, upper, attrDef, now)
+	}
+
+	switch attrDef.Type {
+	case types.AttributeTypeString:
+		return filters.NewStringFilter(op, term.value), nil
+
+	case types.AttributeTypeStringSlice:
+		if op != filters.OpEq {
+			return nil, fmt.Errorf("%s only supports attribute:value or attribute:a,b,c", attrDef.Name)
+		}
+		values := []string{term.value}
+		if !term.quoted {
+			values = strings.Split(term.value, ",")
+		}
+		return filters.NewStringSliceFilter(filters.OpIn, values), nil
+
+	case types.AttributeTypeInt, types.AttributeTypeInt64:
+		value, err := parseQueryInt(term.value)
+		if err != nil {
+			return nil, err
+		}
+		return filters.NewIntFilter(op, value), nil
+
+	case types.AttributeTypeFloat, types.AttributeTypeFloat64:
+		value, err := strconv.ParseFloat(term.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", term.value)
+		}
+		return filters.NewFloatFilter(op, value), nil
+
+	case types.AttributeTypeBool:
+		value, err := parseQueryBool(term.value)
+		if err != nil {
+			return nil, err
+		}
+		return filters.NewBoolFilter(op, value), nil
+
+	case types.AttributeTypeTime:
+		start, end, err := filters.ResolveDate(term.value, now)
+		if err != nil {
+			return nil, err
+		}
+		// A date covers its whole period, so bounds refer to its start or end
+		switch op {
+		case filters.OpEq:
+			return filters.NewDateRangeFilter(&start, &end), nil
+		case filters.OpGt, filters.OpLte:
+			return filters.NewTimeFilter(op, end), nil
+		case filters.OpGte, filters.OpLt:
+			return filters.NewTimeFilter(op, start), nil
+		default:
+			return nil, fmt.Errorf("dates only support :, >, >=, < and <=")
+		}
+
+	default:
+		return nil, fmt.Errorf("attribute type %s can't be filtered in queries", attrDef.Type)
+	}
+}
+
+// rangeFilter builds the filter of a min..max term.
+func rangeFilter(lower, upper string, attrDef types.AttributeDef, now time.Time) (filters.FilterValue, error) {
+	if lower == "" && upper == "" {
+		return nil, fmt.Errorf("a range needs at least one bound")
+	}
+
+	switch attrDef.Type {
+	case types.AttributeTypeTime:
+		var min, max *time.Time
+		if lower != "" {
+			start, _, err := filters.ResolveDate(lower, now)
+			if err != nil {
+				return nil, err
+			}
+			min = &start
+		}
+		if upper != "" {
+			_, end, err := filters.ResolveDate(upper, now)
+			if err != nil {
+				return nil, err
+			}
+			max = &end
+		}
+		return filters.NewDateRangeFilter(min, max), nil
+
+	case types.AttributeTypeInt, types.AttributeTypeInt64, types.AttributeTypeFloat, types.AttributeTypeFloat64:
+		parse := func(s string) (float64, error) {
+			if attrDef.Type == types.AttributeTypeInt || attrDef.Type == types.AttributeTypeInt64 {
+				value, err := parseQueryInt(s)
+				return float64(value), err
+			}
+			value, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid number %q", s)
+			}
+			return value, nil
+		}
+
+		var min, max *float64
+		if lower != "" {
+			value, err := parse(lower)
+			if err != nil {
+				return nil, err
+			}
+			min = &value
+		}
+		if upper != "" {
+			value, err := parse(upper)
+			if err != nil {
+				return nil, err
+			}
+			max = &value
+		}
+		return filters.NewRangeFilter(min, max), nil
+
+	default:
+		return nil, fmt.Errorf("ranges need a numeric or date attribute, %s is %s", attrDef.Name, attrDef.Type)
+	}
+}
+
+// parseQueryInt parses an integer with an optional size suffix.
+func parseQueryInt(s string) (int64, error) {
+	number := strings.TrimRightFunc(s, unicode.IsLetter)
+	multiplier := 1.0
+	if suffix := strings.ToLower(s[len(number):]); suffix != "" {
+		unit, ok := sizeUnits[strings.TrimSuffix(suffix, "ib")]
+		if !ok {
+			unit, ok = sizeUnits[suffix]
+		}
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q in %q", s[len(number):], s)
+		}
+		multiplier = unit
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	value *= multiplier
+	if value != math.Trunc(value) || math.Abs(value) > math.MaxInt64 {
+		return 0, fmt.Errorf("%q is not a whole number", s)
+	}
+	return int64(value), nil
+}
+
+// parseQueryBool parses a boolean value.
+func parseQueryBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "1":
+		return true, nil
+	case "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q (expected true or false)", s)
+}
+
+// resolveType resolves a type name given in a query, either in full or by
+// its last segments.
+func resolveType(registry *types.TypeRegistry, name string) (string, error) {
+	if registry.Get(name) != nil {
+		return name, nil
+	}
+
+	var matches []string
+	for _, candidate := range registry.List() {
+		if strings.HasSuffix(candidate, "."+name) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Strings(matches)
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("unknown type %q", name)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("ambiguous type %q, one of: %s", name, strings.Join(matches, ", "))
+	}
+}
@@ -55,6 +55,9 @@ type RankedEntity struct {
 	Entity   types.Entity
 	Score    float64
 	Provider string
+
+	// Source is SourceLive or SourceCached (set by the Federator)
+	Source string
 }
 
 // RankedResult contains ranked search results.
@@ -79,6 +82,7 @@ func (r *Ranker) Rank(response FederatedResponse, query SearchQuery) RankedResul
 				Entity:   entity,
 				Score:    0, // Will be calculated
 				Provider: result.Provider,
+				Source:   result.Source,
 			})
 			typeCounts[entity.Type]++
 		}
@@ -100,6 +104,7 @@ func (r *Ranker) Rank(response FederatedResponse, query SearchQuery) RankedResul
 		}
 		return deduped[i].Entity.Timestamp.After(deduped[j].Entity.Timestamp)
 	})
+	SortEntities(deduped, query.Sort)
 
 	// Apply pagination
 	offset := query.Offset
@@ -147,7 +152,7 @@ func (r *Ranker) scoreEntity(ranked RankedEntity, query SearchQuery) float64 {
 	}
 
 	// Provider boost
-	if providerWeight, ok := r.DefaultWeights.ProviderWeight[ranked.Provider]; ok {
+	if providerWeight, ok := lookupProviderWeight(r.DefaultWeights.ProviderWeight, ranked.Provider); ok {
 		score += providerWeight
 	}
 
@@ -3,6 +3,7 @@ package search
 import (
 	"context"
 
+	"github.com/yourname/mifind/internal/provider"
 	"github.com/yourname/mifind/internal/types"
 )
 
@@ -20,18 +21,35 @@ type RankingStrategy interface {
 }
 
 // EntityWithProvider wraps an entity with its source provider information.
+// Provider is the instance key ("providerType:instanceID") of the source instance.
 type EntityWithProvider struct {
 	Entity   types.Entity
 	Provider string
 }
 
+// lookupProviderWeight returns the configured weight for a provider instance.
+// Weights can be keyed by instance key ("immich:family") or by provider type
+// ("immich"); an instance-specific weight takes precedence.
+func lookupProviderWeight(weights map[string]float64, instanceKey string) (float64, bool) {
+	if weight, ok := weights[instanceKey]; ok {
+		return weight, true
+	}
+	if providerType, _, err := provider.ParseInstanceKey(instanceKey); err == nil {
+		if weight, ok := weights[providerType]; ok {
+			return weight, true
+		}
+	}
+	return 0, false
+}
+
 
 // RankingConfig defines configuration for ranking strategies.
 type RankingConfig struct {
 	// Strategy specifies which ranking strategy to use
 	Strategy string `mapstructure:"strategy"`
 
-	// ProviderWeights specifies weights for different providers
+	// ProviderWeights specifies weights for different providers, keyed by
+	// provider type ("immich") or instance key ("immich:family")
 	ProviderWeights map[string]float64 `mapstructure:"provider_weights"`
 
 	// TypeWeights specifies weights for different entity types
@@ -39,6 +57,15 @@ type RankingConfig struct {
 
 	// Meilisearch config for MeilisearchRanker
 	Meilisearch MeilisearchConfig `mapstructure:"meilisearch"`
+
+	// BM25 config for BM25Ranker
+	BM25 BM25Config `mapstructure:"bm25"`
+
+	// Fuzzy config for typo-tolerant matching in InMemoryRanker
+	Fuzzy FuzzyConfig `mapstructure:"fuzzy"`
+
+	// Fusion config for FusionRanker
+	Fusion FusionConfig `mapstructure:"fusion"`
 }
 
 // MeilisearchConfig contains Meilisearch-specific configuration.
@@ -85,5 +112,8 @@ func DefaultRankingConfig() RankingConfig {
 				MaxAge:   "720h",
 			},
 		},
+		BM25:   DefaultBM25Config(),
+		Fuzzy:  DefaultFuzzyConfig(),
+		Fusion: DefaultFusionConfig(),
 	}
 }
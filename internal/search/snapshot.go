@@ -0,0 +1,261 @@
+package search
+
+import (
+	"container/list"
+	"context"
+	"encoding/base64"
+	"errors"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// snapshot is the ranked result set of a search, stored so that later pages
+// are read from it instead of searching again.
+type snapshot struct {
+	query SearchQuery
+
+	// mu guards the fields below, which grow as later pages need more results
+	mu sync.Mutex
+
+	// window is how many results were requested from each provider (0 = all)
+	window int
+
+	// more holds the providers that may have results beyond the window
+	more map[string]bool
+
+	response FederatedResponse
+}
+
+// snapshotStore holds result snapshots by token. Snapshots expire ttl after
+// they were last read, and when there are more than size the least recently
+// read are dropped first.
+type snapshotStore struct {
+	mu   sync.Mutex
+	ttl  time.Duration
+	size int
+
+	// tokens indexes the elements of order by token
+	tokens map[string]*list.Element
+
+	// order holds *snapshotEntry values, most recently read first
+	order *list.List
+}
+
+// snapshotEntry is a stored snapshot and when it expires.
+type snapshotEntry struct {
+	token   string
+	snap    *snapshot
+	expires time.Time
+}
+
+// newSnapshotStore creates a store of at most size snapshots.
+func newSnapshotStore(ttl time.Duration, size int) *snapshotStore {
+	return &snapshotStore{
+		ttl:    ttl,
+		size:   size,
+		tokens: make(map[string]*list.Element),
+		order:  list.New(),
+	}
+}
+
+// add stores a snapshot and returns its token, or "" if the store holds none.
+func (s *snapshotStore) add(snap *snapshot) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.size <= 0 {
+		return ""
+	}
+
+	token := newToken()
+	now := time.Now()
+	s.tokens[token] = s.order.PushFront(&snapshotEntry{token: token, snap: snap, expires: now.Add(s.ttl)})
+	s.evict(now)
+	return token
+}
+
+// get returns the snapshot stored under token and extends its expiry.
+func (s *snapshotStore) get(token string) (*snapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.evict(now)
+
+	element, ok := s.tokens[token]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*snapshotEntry)
+	entry.expires = now.Add(s.ttl)
+	s.order.MoveToFront(element)
+	return entry.snap, true
+}
+
+// evict drops expired snapshots and the least recently read ones beyond the
+// size. Entries are ordered by expiry too, so both are found at the back.
+// The caller must hold mu.
+func (s *snapshotStore) evict(now time.Time) {
+	for oldest := s.order.Back(); oldest != nil; oldest = s.order.Back() {
+		entry := oldest.Value.(*snapshotEntry)
+		if s.order.Len() <= s.size && now.Before(entry.expires) {
+			return
+		}
+		s.order.Remove(oldest)
+		delete(s.tokens, entry.token)
+	}
+}
+
+// configure sets the ttl and size of the store, dropping snapshots that no
+// longer fit.
+func (s *snapshotStore) configure(ttl time.Duration, size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ttl, s.size = ttl, size
+	s.evict(time.Now())
+}
+
+// SearchSnapshot searches like Search and stores the ranked results under
+// response.Snapshot for ReadSnapshot. need is the number of ranked results
+// the caller reads right away. Results that fit in need aren't stored, since
+// there is no further page to read.
+//
+// Providers are asked for the page window of results (or need, if larger)
+// rather than everything, and ReadSnapshot fetches more from the providers
+// that have them as later pages need them. Entities already in the snapshot
+// keep their position, so pages don't shift. Searches sorted by attributes
+// fetch all results at once, since a provider's first results aren't its
+// first in attribute order.
+func (f *Federator) SearchSnapshot(ctx context.Context, query SearchQuery, need int) FederatedResponse {
+	window := 0
+	if len(query.Sort) == 0 && query.Limit == 0 && query.ProviderLimit == 0 && f.pageWindow > 0 {
+		window = max(f.pageWindow, need)
+	}
+	query.ProviderLimit = window
+	query.Offset = 0
+
+	response := f.Search(ctx, query)
+	snap := &snapshot{
+		query:    query,
+		window:   window,
+		more:     make(map[string]bool),
+		response: response,
+	}
+	for _, result := range response.Results {
+		if result.HasMore {
+			snap.more[result.Provider] = true
+		}
+	}
+
+	response.HasMore = len(snap.more) > 0
+	if len(response.RankedEntities) > need || response.HasMore {
+		response.Snapshot = f.snapshots.add(snap)
+	}
+	return response
+}
+
+// ReadSnapshot returns the results stored by SearchSnapshot under token. If
+// the snapshot has fewer than need ranked results and providers have more,
+// they are fetched and ranked after the stored ones first.
+func (f *Federator) ReadSnapshot(ctx context.Context, token string, need int) (FederatedResponse, error) {
+	snap, ok := f.snapshots.get(token)
+	if !ok {
+		return FederatedResponse{}, ErrSnapshotNotFound
+	}
+
+	snap.mu.Lock()
+	defer snap.mu.Unlock()
+
+	for len(snap.response.RankedEntities) < need && len(snap.more) > 0 && ctx.Err() == nil {
+		f.extendSnapshot(ctx, snap)
+	}
+
+	response := snap.response
+	response.RankedEntities = slices.Clone(snap.response.RankedEntities)
+	response.TypeCounts = maps.Clone(snap.response.TypeCounts)
+	response.Snapshot = token
+	response.HasMore = len(snap.more) > 0
+	return response, nil
+}
+
+// extendSnapshot fetches the next window of results from the providers that
+// have more, doubling the window, and appends the entities that are new to
+// the snapshot. Providers that return nothing new are considered exhausted.
+func (f *Federator) extendSnapshot(ctx context.Context, snap *snapshot) {
+	query := snap.query
+	query.Offset = snap.window
+	query.ProviderLimit = snap.window
+	query.CollectLate = false
+	query.instances = snap.more
+
+	next := f.Search(ctx, query)
+	snap.window *= 2
+
+	seen := make(map[string]bool, len(snap.response.RankedEntities))
+	for _, ranked := range snap.response.RankedEntities {
+		seen[ranked.Entity.ID] = true
+	}
+
+	more := make(map[string]bool)
+	for _, result := range next.Results {
+		if !result.HasMore {
+			continue
+		}
+		for _, entity := range result.Entities {
+			if !seen[entity.ID] {
+				more[result.Provider] = true
+				break
+			}
+		}
+	}
+	snap.more = more
+
+	for _, ranked := range next.RankedEntities {
+		if seen[ranked.Entity.ID] {
+			continue
+		}
+		seen[ranked.Entity.ID] = true
+		snap.response.RankedEntities = append(snap.response.RankedEntities, ranked)
+	}
+	for typeName, count := range next.TypeCounts {
+		snap.response.TypeCounts[typeName] += count
+	}
+	snap.response.TotalCount += next.TotalCount
+}
+
+// Cursor is a position in a result snapshot. Its string form is opaque to
+// clients.
+type Cursor struct {
+	// Snapshot is the token of the result snapshot
+	Snapshot string
+
+	// Offset is the number of ranked results before the position
+	Offset int
+}
+
+// errInvalidCursor is returned by ParseCursor for malformed cursors.
+var errInvalidCursor = errors.New("invalid cursor")
+
+// String encodes the cursor for clients.
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Snapshot + ":" + strconv.Itoa(c.Offset)))
+}
+
+// ParseCursor decodes a cursor returned by Cursor.String.
+func ParseCursor(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, errInvalidCursor
+	}
+	token, offset, ok := strings.Cut(string(data), ":")
+	n, err := strconv.Atoi(offset)
+	if !ok || token == "" || err != nil || n < 0 {
+		return Cursor{}, errInvalidCursor
+	}
+	return Cursor{Snapshot: token, Offset: n}, nil
+}
@@ -0,0 +1,163 @@
+package search
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yourname/mifind/internal/types"
+)
+
+// SortClause orders search results by an attribute.
+type SortClause struct {
+	// Attribute is the attribute to sort by
+	Attribute string
+
+	// Type is the attribute's type, which decides how values compare
+	Type types.AttributeType
+
+	// Descending sorts from the largest value to the smallest
+	Descending bool
+}
+
+// String returns the clause as written in a sort specification.
+func (c SortClause) String() string {
+	if c.Descending {
+		return c.Attribute + " desc"
+	}
+	return c.Attribute + " asc"
+}
+
+// ParseSort parses a sort specification such as "modified desc, size asc"
+// into clauses. Each clause is an attribute of the registry, optionally
+// followed by asc (the default) or desc. Lists and GPS coordinates can't be
+// sorted by. An empty specification returns no clauses.
+func ParseSort(spec string, registry *types.TypeRegistry) ([]SortClause, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+
+	attrs := registry.GetAllAttributes()
+	var clauses []SortClause
+	for _, part := range strings.Split(spec, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("invalid sort clause %q (expected attribute [asc|desc])", strings.TrimSpace(part))
+		}
+
+		clause := SortClause{Attribute: fields[0]}
+		if len(fields) == 2 {
+			switch strings.ToLower(fields[1]) {
+			case "asc":
+			case "desc":
+				clause.Descending = true
+			default:
+				return nil, fmt.Errorf("invalid sort direction %q for %s (expected asc or desc)", fields[1], clause.Attribute)
+			}
+		}
+
+		attrDef, exists := attrs[clause.Attribute]
+		if !exists {
+			return nil, fmt.Errorf("unknown sort attribute %q", clause.Attribute)
+		}
+		switch attrDef.Type {
+		case types.AttributeTypeStringSlice, types.AttributeTypeGPS:
+			return nil, fmt.Errorf("can't sort by %s, %s attributes have no order", clause.Attribute, attrDef.Type)
+		}
+		clause.Type = attrDef.Type
+
+		for _, existing := range clauses {
+			if existing.Attribute == clause.Attribute {
+				return nil, fmt.Errorf("duplicate sort attribute %q", clause.Attribute)
+			}
+		}
+		clauses = append(clauses, clause)
+	}
+
+	return clauses, nil
+}
+
+// SortEntities orders ranked entities by the sort clauses, in place. Values
+// are compared by the attribute's type, so sizes reported as int64 by one
+// provider and float64 by another sort together. Entities without a value
+// (or with one that doesn't convert to the type) come last in either
+// direction. Ties are broken by score.
+func SortEntities(entities []RankedEntity, clauses []SortClause) {
+	if len(clauses) == 0 {
+		return
+	}
+
+	slices.SortStableFunc(entities, func(a, b RankedEntity) int {
+		for _, clause := range clauses {
+			x, xOK := sortValue(a.Entity, clause)
+			y, yOK := sortValue(b.Entity, clause)
+			switch {
+			case !xOK && !yOK:
+				continue
+			case !xOK:
+				return 1
+			case !yOK:
+				return -1
+			}
+
+			order := compareSortValues(x, y)
+			if clause.Descending {
+				order = -order
+			}
+			if order != 0 {
+				return order
+			}
+		}
+		return cmp.Compare(b.Score, a.Score)
+	})
+}
+
+// sortValue returns an entity's value for a sort clause as a float64
+// (numbers, times as Unix nanoseconds and bools as 0 or 1) or a string.
+func sortValue(entity types.Entity, clause SortClause) (any, bool) {
+	value, exists := entityAttribute(entity, clause.Attribute)
+	if !exists && clause.Attribute == types.AttrTitle {
+		value, exists = entity.Title, entity.Title != ""
+	}
+	if !exists || value == nil {
+		return nil, false
+	}
+
+	switch clause.Type {
+	case types.AttributeTypeInt, types.AttributeTypeInt64, types.AttributeTypeFloat, types.AttributeTypeFloat64:
+		return numericValue(value)
+	case types.AttributeTypeTime:
+		// Times are RFC 3339 strings after a JSON round trip
+		if s, ok := value.(string); ok {
+			t, err := time.Parse(time.RFC3339, s)
+			return float64(t.UnixNano()), err == nil
+		}
+		t, ok := timeValue(value)
+		return float64(t.UnixNano()), ok
+	case types.AttributeTypeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, false
+		}
+		if b {
+			return 1.0, true
+		}
+		return 0.0, true
+	default:
+		return strings.ToLower(attributeValueToString(value)), true
+	}
+}
+
+// compareSortValues compares two values returned by sortValue for the same
+// clause.
+func compareSortValues(a, b any) int {
+	if x, ok := a.(float64); ok {
+		y, _ := b.(float64)
+		return cmp.Compare(x, y)
+	}
+	x, _ := a.(string)
+	y, _ := b.(string)
+	return strings.Compare(x, y)
+}
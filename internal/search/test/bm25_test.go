@@ -0,0 +1,49 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/types"
+)
+
+func TestBM25Ranker(t *testing.T) {
+	entity := func(id, title, description string, tokens []string, attrs map[string]any) search.EntityWithProvider {
+		e := types.NewEntity(id, types.TypeFileDocument, "mock", title)
+		e.Description = description
+		e.SearchTokens = tokens
+		for key, value := range attrs {
+			e.AddAttribute(key, value)
+		}
+		return search.EntityWithProvider{Entity: e, Provider: "mock:test"}
+	}
+	entities := []search.EntityWithProvider{
+		entity("description", "Notes", "Trip to the Alps, hiking photos", nil, nil),
+		entity("unrelated", "Tax return 2023", "Forms for the tax office", nil, nil),
+		entity("title", "Alps hiking photos", "", nil, nil),
+		entity("tokens", "IMG_0042.jpg", "", []string{"alps", "hiking"}, nil),
+		entity("attribute", "Scan", "", nil, map[string]any{types.AttrAlbum: "Hikes in the Alps"}),
+	}
+
+	ranker := search.NewBM25Ranker(search.DefaultRankingConfig())
+	ranked, err := ranker.Rank(context.Background(), entities, search.NewSearchQuery("hiked the alps"))
+	if err != nil {
+		t.Fatalf("Rank failed: %v", err)
+	}
+
+	// Stemming matches hiked with hiking and hikes; field boosts order the matches
+	want := []string{"title", "tokens", "description", "attribute", "unrelated"}
+	for i, r := range ranked {
+		if r.Entity.ID != want[i] {
+			var got []string
+			for _, r := range ranked {
+				got = append(got, r.Entity.ID)
+			}
+			t.Fatalf("expected order %v, got %v", want, got)
+		}
+	}
+	if ranked[len(ranked)-1].Score >= ranked[len(ranked)-2].Score {
+		t.Errorf("expected the unrelated entity to score lowest, got %v", ranked)
+	}
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourname/mifind/internal/search/filters"
+	"github.com/yourname/mifind/internal/types"
+)
+
+func TestResolveDate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+	// A Wednesday
+	now := time.Date(2024, 5, 15, 14, 30, 0, 0, berlin)
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		expr       string
+		start, end time.Time
+	}{
+		{"-7d", now.AddDate(0, 0, -7), now},
+		{"-12h", now.Add(-12 * time.Hour), now},
+		{"today", day(2024, 5, 15), day(2024, 5, 16)},
+		{"yesterday", day(2024, 5, 14), day(2024, 5, 15)},
+		{"this-week", day(2024, 5, 13), day(2024, 5, 20)},
+		{"last-week", day(2024, 5, 6), day(2024, 5, 13)},
+		{"last-month", day(2024, 4, 1), day(2024, 5, 1)},
+		{"this-quarter", day(2024, 4, 1), day(2024, 7, 1)},
+		{"last-quarter", day(2024, 1, 1), day(2024, 4, 1)},
+		{"this-year", day(2024, 1, 1), day(2025, 1, 1)},
+		{"2023", day(2023, 1, 1), day(2024, 1, 1)},
+		{"2023-Q2", day(2023, 4, 1), day(2023, 7, 1)},
+		{"2023-06-15", day(2023, 6, 15), day(2023, 6, 16)},
+	}
+
+	for _, tt := range tests {
+		start, end, err := filters.ResolveDate(tt.expr, now)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		// Rolling periods end now, calendar periods a second before the next one
+		wantEnd := tt.end
+		if tt.end != now {
+			wantEnd = tt.end.Add(-time.Second)
+		}
+		if !start.Equal(tt.start) || !end.Equal(wantEnd) {
+			t.Errorf("%s: expected %v..%v, got %v..%v", tt.expr, tt.start, wantEnd, start, end)
+		}
+	}
+
+	for _, expr := range []string{"2023-Q5", "next-week", "-7x", "soon"} {
+		if _, _, err := filters.ResolveDate(expr, now); err == nil {
+			t.Errorf("%s: expected error", expr)
+		}
+	}
+
+	// Range bounds take the start of min and the end of max
+	registry := types.NewTypeRegistry()
+	types.RegisterCoreTypes(registry)
+	parser := filters.NewParser(registry)
+	parser.SetLocation(berlin)
+	parsed, err := parser.ParseFilters(map[string]any{types.AttrModified: map[string]any{"min": "2023", "max": "2023-Q2"}})
+	if err != nil {
+		t.Fatalf("ParseFilters failed: %v", err)
+	}
+	dateRange, ok := parsed[types.AttrModified].(*filters.DateRangeFilter)
+	if !ok {
+		t.Fatalf("expected DateRangeFilter, got %T", parsed[types.AttrModified])
+	}
+	if !dateRange.Min.Equal(day(2023, 1, 1)) || !dateRange.Max.Equal(day(2023, 7, 1).Add(-time.Second)) {
+		t.Errorf("expected 2023-01-01..2023-06-30 in Berlin, got %v..%v", dateRange.Min, dateRange.Max)
+	}
+}
@@ -0,0 +1,610 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/search/filters"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// slowProvider holds searches until released or cancelled.
+type slowProvider struct {
+	*mock.MockProvider
+	release   chan struct{}
+	cancelled chan struct{}
+}
+
+func (p *slowProvider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	select {
+	case <-p.release:
+		return p.MockProvider.Search(ctx, query)
+	case <-ctx.Done():
+		close(p.cancelled)
+		return nil, ctx.Err()
+	}
+}
+
+// brokenProvider fails every search.
+type brokenProvider struct {
+	*mock.MockProvider
+}
+
+func (p *brokenProvider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	return nil, provider.ErrAuthenticationFailed
+}
+
+// recordingProvider records the filters of the last search it received.
+type recordingProvider struct {
+	*mock.MockProvider
+	filters map[string]any
+}
+
+func (p *recordingProvider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	p.filters = query.Filters
+	return p.MockProvider.Search(ctx, query)
+}
+
+// newTestFederator creates a federator over a fast, a slow and a broken instance.
+func newTestFederator(t *testing.T) (*search.Federator, *slowProvider) {
+	t.Helper()
+	ctx := context.Background()
+
+	slow := &slowProvider{
+		MockProvider: mock.NewMockProvider(),
+		release:      make(chan struct{}),
+		cancelled:    make(chan struct{}),
+	}
+	factories := map[string]func() provider.Provider{
+		"fast":   func() provider.Provider { return mock.NewMockProvider() },
+		"slow":   func() provider.Provider { return slow },
+		"broken": func() provider.Provider { return &brokenProvider{MockProvider: mock.NewMockProvider()} },
+	}
+
+	registry := provider.NewRegistry()
+	for name, factory := range factories {
+		if err := registry.Register(provider.ProviderMetadata{Name: name, Factory: factory}); err != nil {
+			t.Fatalf("Failed to register %s provider: %v", name, err)
+		}
+	}
+
+	// Instance IDs differ by type so the mock entity IDs don't collide
+	logger := zerolog.Nop()
+	manager := provider.NewManager(registry, &logger)
+	configs := map[string]map[string]any{
+		"fast":   {"instance_id": "a", "entity_count": 3},
+		"slow":   {"instance_id": "b", "entity_count": 2, "soft_deadline": "50ms", "enable_caching": false},
+		"broken": {"instance_id": "c"},
+	}
+	for name, config := range configs {
+		if err := manager.Initialize(ctx, name, config); err != nil {
+			t.Fatalf("Failed to initialize %s provider: %v", name, err)
+		}
+	}
+
+	ranker := search.NewInMemoryRanker(search.DefaultRankingConfig())
+	return search.NewFederator(manager, ranker, &logger, 5*time.Second), slow
+}
+
+// reportsByProvider indexes provider reports by instance key.
+func reportsByProvider(reports []search.ProviderReport) map[string]search.ProviderReport {
+	result := make(map[string]search.ProviderReport, len(reports))
+	for _, report := range reports {
+		result[report.Provider] = report
+	}
+	return result
+}
+
+// TestFederator_SoftDeadline tests that a search answers without a provider
+// that misses its soft deadline, reports it, and collects its late results.
+func TestFederator_SoftDeadline(t *testing.T) {
+	federator, slow := newTestFederator(t)
+
+	query := search.NewSearchQuery("")
+	query.CollectLate = true
+	response := federator.Search(context.Background(), query)
+
+	if response.Duration > time.Second {
+		t.Errorf("Expected search to answer at the soft deadline, took %v", response.Duration)
+	}
+	if len(response.RankedEntities) != 3 {
+		t.Errorf("Expected the 3 fast results, got %d", len(response.RankedEntities))
+	}
+
+	reports := reportsByProvider(response.Reports)
+	if got := reports["fast:a"]; got.Status != search.ReportOK {
+		t.Errorf("Expected fast provider to be ok, got %+v", got)
+	}
+	if got := reports["slow:b"]; got.Status != search.ReportLate || got.ErrorClass != search.ErrorClassTimeout {
+		t.Errorf("Expected slow provider to be late with a timeout, got %+v", got)
+	}
+	if got := reports["broken:c"]; got.Status != search.ReportFailed || got.ErrorClass != string(provider.ErrorTypeAuth) {
+		t.Errorf("Expected broken provider to fail with an auth error, got %+v", got)
+	}
+	if response.LateToken == "" {
+		t.Fatal("Expected a late token")
+	}
+
+	// Nothing has arrived yet
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	late, err := federator.CollectLate(ctx, response.LateToken)
+	if err != nil {
+		t.Fatalf("CollectLate failed: %v", err)
+	}
+	if len(late.RankedEntities) != 0 || late.LateToken != response.LateToken {
+		t.Errorf("Expected no results yet and the same token, got %d results and token %q", len(late.RankedEntities), late.LateToken)
+	}
+
+	close(slow.release)
+	late, err = federator.CollectLate(context.Background(), response.LateToken)
+	if err != nil {
+		t.Fatalf("CollectLate failed: %v", err)
+	}
+	if len(late.RankedEntities) != 2 {
+		t.Errorf("Expected the 2 late results, got %d", len(late.RankedEntities))
+	}
+	if len(late.Reports) != 1 || late.Reports[0].Provider != "slow:b" || late.Reports[0].Status != search.ReportOK {
+		t.Errorf("Expected an ok report for the slow provider, got %+v", late.Reports)
+	}
+	if late.LateToken != "" {
+		t.Errorf("Expected no late token once all results arrived, got %q", late.LateToken)
+	}
+
+	if _, err := federator.CollectLate(context.Background(), response.LateToken); !errors.Is(err, search.ErrLateResultsNotFound) {
+		t.Errorf("Expected ErrLateResultsNotFound for a collected token, got %v", err)
+	}
+}
+
+// TestFederator_SoftDeadlineCancelsLate tests that late providers are
+// cancelled when their results aren't collected.
+func TestFederator_SoftDeadlineCancelsLate(t *testing.T) {
+	federator, slow := newTestFederator(t)
+
+	response := federator.Search(context.Background(), search.NewSearchQuery(""))
+	if response.LateToken != "" {
+		t.Errorf("Expected no late token without CollectLate, got %q", response.LateToken)
+	}
+
+	select {
+	case <-slow.cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the late search to be cancelled")
+	}
+}
+
+// delayedProvider answers searches after a delay.
+type delayedProvider struct {
+	*mock.MockProvider
+	delay time.Duration
+}
+
+func (p *delayedProvider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	select {
+	case <-time.After(p.delay):
+		return p.MockProvider.Search(ctx, query)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// newDeadlineFederator creates a federator over a quick instance that answers
+// after its soft deadline but before that of a patient instance.
+func newDeadlineFederator(t *testing.T) *search.Federator {
+	t.Helper()
+
+	registry := provider.NewRegistry()
+	delays := map[string]time.Duration{"quick": 150 * time.Millisecond, "patient": 300 * time.Millisecond}
+	for name, delay := range delays {
+		factory := func() provider.Provider {
+			return &delayedProvider{MockProvider: mock.NewMockProvider(), delay: delay}
+		}
+		if err := registry.Register(provider.ProviderMetadata{Name: name, Factory: factory}); err != nil {
+			t.Fatalf("Failed to register %s provider: %v", name, err)
+		}
+	}
+
+	logger := zerolog.Nop()
+	manager := provider.NewManager(registry, &logger)
+	configs := map[string]map[string]any{
+		"quick":   {"instance_id": "a", "entity_count": 2, "soft_deadline": "50ms", "enable_caching": false},
+		"patient": {"instance_id": "b", "entity_count": 3, "soft_deadline": "2s", "enable_caching": false},
+	}
+	for name, config := range configs {
+		if err := manager.Initialize(context.Background(), name, config); err != nil {
+			t.Fatalf("Failed to initialize %s provider: %v", name, err)
+		}
+	}
+
+	ranker := search.NewInMemoryRanker(search.DefaultRankingConfig())
+	return search.NewFederator(manager, ranker, &logger, 5*time.Second)
+}
+
+// TestFederator_OwnSoftDeadline tests that each provider is held to its own
+// soft deadline while the search waits for providers with a later one.
+func TestFederator_OwnSoftDeadline(t *testing.T) {
+	federator := newDeadlineFederator(t)
+
+	query := search.NewSearchQuery("")
+	query.CollectLate = true
+	response := federator.Search(context.Background(), query)
+
+	reports := reportsByProvider(response.Reports)
+	if got := reports["quick:a"]; got.Status != search.ReportLate || got.ErrorClass != search.ErrorClassTimeout {
+		t.Errorf("Expected quick provider to be late with a timeout, got %+v", got)
+	}
+	if got := reports["patient:b"]; got.Status != search.ReportOK {
+		t.Errorf("Expected patient provider to be ok, got %+v", got)
+	}
+	if len(response.RankedEntities) != 3 {
+		t.Errorf("Expected the 3 patient results, got %d", len(response.RankedEntities))
+	}
+	if response.LateToken == "" {
+		t.Fatal("Expected a late token")
+	}
+
+	// The quick results arrived while waiting for the patient provider
+	late, err := federator.CollectLate(context.Background(), response.LateToken)
+	if err != nil {
+		t.Fatalf("CollectLate failed: %v", err)
+	}
+	if len(late.RankedEntities) != 2 {
+		t.Errorf("Expected the 2 late results, got %d", len(late.RankedEntities))
+	}
+	if len(late.Reports) != 1 || late.Reports[0].Provider != "quick:a" {
+		t.Errorf("Expected a report for the quick provider, got %+v", late.Reports)
+	}
+}
+
+// TestFederator_Canceled tests that providers that haven't answered when the
+// caller goes away are reported as canceled.
+func TestFederator_Canceled(t *testing.T) {
+	federator := newDeadlineFederator(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	query := search.NewSearchQuery("")
+	query.CollectLate = true
+	response := federator.Search(ctx, query)
+
+	if response.Duration > time.Second {
+		t.Errorf("Expected search to stop when canceled, took %v", response.Duration)
+	}
+	reports := reportsByProvider(response.Reports)
+	if got := reports["quick:a"]; got.Status != search.ReportLate {
+		t.Errorf("Expected quick provider to be late, got %+v", got)
+	}
+	if got := reports["patient:b"]; got.Status != search.ReportFailed || got.ErrorClass != search.ErrorClassCanceled {
+		t.Errorf("Expected patient provider to be canceled, got %+v", got)
+	}
+	if response.LateToken != "" {
+		t.Errorf("Expected no late token for a canceled search, got %q", response.LateToken)
+	}
+}
+
+// TestFederator_FilterPushdown tests that filters are pushed down only as far
+// as the provider's capabilities go, and the rest is applied to its results.
+func TestFederator_FilterPushdown(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	recorder := &recordingProvider{MockProvider: mock.NewMockProvider()}
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:    "mock",
+		Factory: func() provider.Provider { return recorder },
+	}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	manager := provider.NewManager(registry, &logger)
+	if err := manager.Initialize(ctx, "mock", map[string]any{"instance_id": "default", "entity_count": 10, "enable_caching": false}); err != nil {
+		t.Fatalf("Failed to initialize provider: %v", err)
+	}
+	federator := search.NewFederator(manager, search.NewInMemoryRanker(search.DefaultRankingConfig()), &logger, 5*time.Second)
+
+	tests := []struct {
+		name   string
+		attr   string
+		filter filters.FilterValue
+		pushed map[string]any
+		count  int
+	}{
+		{
+			// Strict bounds are pushed as inclusive ranges and refined in memory
+			name:   "size>1024",
+			attr:   types.AttrSize,
+			filter: filters.NewIntFilter(filters.OpGt, 1024),
+			pushed: map[string]any{types.AttrSize: map[string]any{"min": int64(1024)}},
+			count:  1,
+		},
+		{
+			name:   "size<=1024",
+			attr:   types.AttrSize,
+			filter: filters.NewIntFilter(filters.OpLte, 1024),
+			pushed: map[string]any{types.AttrSize: map[string]any{"max": int64(1024)}},
+			count:  1,
+		},
+		{
+			// Inequality can't be pushed down
+			name:   "extension!=txt",
+			attr:   types.AttrExtension,
+			filter: filters.NewStringFilter(filters.OpNeq, "txt"),
+			pushed: map[string]any{},
+			count:  8,
+		},
+		{
+			name:   "camera~mock",
+			attr:   types.AttrCamera,
+			filter: filters.NewStringFilter(filters.OpContains, "mock"),
+			pushed: map[string]any{},
+			count:  4,
+		},
+		{
+			name:   "extension=txt",
+			attr:   types.AttrExtension,
+			filter: filters.NewStringFilter(filters.OpEq, "txt"),
+			pushed: map[string]any{types.AttrExtension: "txt"},
+			count:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := search.NewTypedSearchQuery("").WithFilter(tt.attr, tt.filter).ToSearchQuery()
+			response := federator.Search(ctx, query)
+
+			if !reflect.DeepEqual(recorder.filters, tt.pushed) {
+				t.Errorf("Expected pushed filters %v, got %v", tt.pushed, recorder.filters)
+			}
+			if response.TotalCount != tt.count {
+				t.Errorf("Expected %d results, got %d", tt.count, response.TotalCount)
+			}
+		})
+	}
+}
+
+// incapableProvider declares no filter capabilities.
+type incapableProvider struct {
+	*mock.MockProvider
+}
+
+func (p *incapableProvider) FilterCapabilities(ctx context.Context) (map[string]provider.FilterCapability, error) {
+	return map[string]provider.FilterCapability{}, nil
+}
+
+// TestFederator_ResidualWithoutCapability tests that a provider without a
+// capability for a typed filter is still searched, with the filter applied
+// to its results.
+func TestFederator_ResidualWithoutCapability(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:    "incapable",
+		Factory: func() provider.Provider { return &incapableProvider{MockProvider: mock.NewMockProvider()} },
+	}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	manager := provider.NewManager(registry, &logger)
+	if err := manager.Initialize(ctx, "incapable", map[string]any{"instance_id": "default", "entity_count": 10, "enable_caching": false}); err != nil {
+		t.Fatalf("Failed to initialize provider: %v", err)
+	}
+	federator := search.NewFederator(manager, search.NewInMemoryRanker(search.DefaultRankingConfig()), &logger, 5*time.Second)
+
+	query := search.NewTypedSearchQuery("").WithFilter(types.AttrSize, filters.NewIntFilter(filters.OpGt, 1024)).ToSearchQuery()
+	response := federator.Search(ctx, query)
+
+	if report := reportsByProvider(response.Reports)["incapable:default"]; report.Status != search.ReportOK {
+		t.Errorf("Expected the provider to be searched, got status %q", report.Status)
+	}
+	if response.TotalCount != 1 {
+		t.Errorf("Expected 1 result, got %d", response.TotalCount)
+	}
+	for _, ranked := range response.RankedEntities {
+		if size, _ := ranked.Entity.Attributes[types.AttrSize].(int64); size <= 1024 {
+			t.Errorf("Expected only entities larger than 1024 bytes, got %s with size %v", ranked.Entity.ID, ranked.Entity.Attributes[types.AttrSize])
+		}
+	}
+}
+
+// TestFederator_ResidualPagination tests that a provider's results are
+// paginated after residual filters, so filtering doesn't empty the page.
+func TestFederator_ResidualPagination(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:    "incapable",
+		Factory: func() provider.Provider { return &incapableProvider{MockProvider: mock.NewMockProvider()} },
+	}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	manager := provider.NewManager(registry, &logger)
+	if err := manager.Initialize(ctx, "incapable", map[string]any{"instance_id": "default", "entity_count": 10, "enable_caching": false}); err != nil {
+		t.Fatalf("Failed to initialize provider: %v", err)
+	}
+	federator := search.NewFederator(manager, search.NewInMemoryRanker(search.DefaultRankingConfig()), &logger, 5*time.Second)
+
+	// Only every fifth entity has a size, so paging the unfiltered results
+	// would leave the second page empty
+	query := search.NewTypedSearchQuery("").WithFilter(types.AttrSize, filters.NewIntFilter(filters.OpGt, 0)).ToSearchQuery()
+	first := federator.Search(ctx, query.WithLimit(1))
+	second := federator.Search(ctx, query.WithLimit(1).WithOffset(1))
+	if len(first.RankedEntities) != 1 || len(second.RankedEntities) != 1 {
+		t.Fatalf("Expected a full page at each offset, got %d and %d results", len(first.RankedEntities), len(second.RankedEntities))
+	}
+	if first.RankedEntities[0].Entity.ID == second.RankedEntities[0].Entity.ID {
+		t.Errorf("Expected different entities on each page, got %s twice", first.RankedEntities[0].Entity.ID)
+	}
+}
+
+// typedProvider declares the entity types it returns and counts its searches.
+type typedProvider struct {
+	*mock.MockProvider
+	entityTypes []string
+	searches    int
+}
+
+func (p *typedProvider) EntityTypes() []string {
+	return p.entityTypes
+}
+
+func (p *typedProvider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	p.searches++
+	return p.MockProvider.Search(ctx, query)
+}
+
+// TestFederator_TypeRouting tests that typed searches skip providers that
+// declare they don't produce the type or any of its descendants.
+func TestFederator_TypeRouting(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	instances := map[string]*typedProvider{
+		"files":  {MockProvider: mock.NewMockProvider(), entityTypes: []string{types.TypeFileDocument, types.TypeFileMediaImage}},
+		"assets": {MockProvider: mock.NewMockProvider(), entityTypes: []string{types.TypeMediaAssetPhoto}},
+		"issues": {MockProvider: mock.NewMockProvider(), entityTypes: []string{"code.gitlab.issue"}},
+		"any":    {MockProvider: mock.NewMockProvider()},
+	}
+	registry := provider.NewRegistry()
+	for name, instance := range instances {
+		if err := registry.Register(provider.ProviderMetadata{
+			Name:    name,
+			Factory: func() provider.Provider { return instance },
+		}); err != nil {
+			t.Fatalf("Failed to register %s provider: %v", name, err)
+		}
+	}
+	manager := provider.NewManager(registry, &logger)
+	for name := range instances {
+		if err := manager.Initialize(ctx, name, map[string]any{"instance_id": name, "enable_caching": false}); err != nil {
+			t.Fatalf("Failed to initialize %s provider: %v", name, err)
+		}
+	}
+
+	typeRegistry := types.NewTypeRegistry()
+	types.RegisterCoreTypes(typeRegistry)
+	federator := search.NewFederator(manager, search.NewInMemoryRanker(search.DefaultRankingConfig()), &logger, 5*time.Second)
+	federator.SetTypeRegistry(typeRegistry)
+
+	tests := []struct {
+		typeName string
+		searched []string
+	}{
+		{typeName: "", searched: []string{"any", "assets", "files", "issues"}},
+		// Nothing registered places code.gitlab.issue under item
+		{typeName: types.TypeItem, searched: []string{"any", "assets", "files"}},
+		{typeName: types.TypeFileMedia, searched: []string{"any", "files"}},
+		{typeName: types.TypeMedia, searched: []string{"any", "assets"}},
+		{typeName: "code.gitlab", searched: []string{"any", "issues"}},
+		{typeName: types.TypeFileMediaVideo, searched: []string{"any"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typeName, func(t *testing.T) {
+			for _, instance := range instances {
+				instance.searches = 0
+			}
+
+			response := federator.Search(ctx, search.NewSearchQuery("").WithType(tt.typeName))
+
+			reports := reportsByProvider(response.Reports)
+			for name, instance := range instances {
+				key := provider.InstanceKey(name, name)
+				wantSearched := false
+				for _, searched := range tt.searched {
+					wantSearched = wantSearched || searched == name
+				}
+				if searched := instance.searches > 0; searched != wantSearched {
+					t.Errorf("Expected %s searched=%v, got %v", name, wantSearched, searched)
+				}
+				if skipped := reports[key].Status == search.ReportSkipped; skipped == wantSearched {
+					t.Errorf("Expected %s skipped=%v, got status %q", name, !wantSearched, reports[key].Status)
+				}
+			}
+		})
+	}
+}
+
+// TestFederator_Snapshot tests that a result snapshot fetches more results
+// from providers as later pages need them, without moving earlier ones.
+func TestFederator_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:    "mock",
+		Factory: func() provider.Provider { return mock.NewMockProvider() },
+	}); err != nil {
+		t.Fatalf("Failed to register mock provider: %v", err)
+	}
+	manager := provider.NewManager(registry, &logger)
+	for _, instanceID := range []string{"a", "b"} {
+		config := map[string]any{"instance_id": instanceID, "entity_count": 10, "enable_caching": false}
+		if err := manager.Initialize(ctx, "mock", config); err != nil {
+			t.Fatalf("Failed to initialize %s: %v", instanceID, err)
+		}
+	}
+	federator := search.NewFederator(manager, search.NewInMemoryRanker(search.DefaultRankingConfig()), &logger, 5*time.Second)
+	federator.SetPageWindow(3)
+
+	// The first page only asks each provider for the window
+	first := federator.SearchSnapshot(ctx, search.NewSearchQuery(""), 3)
+	if len(first.RankedEntities) != 6 || !first.HasMore || first.Snapshot == "" {
+		t.Fatalf("Expected 6 results with more to come, got %d (has more: %v)", len(first.RankedEntities), first.HasMore)
+	}
+
+	for _, need := range []int{12, 100} {
+		page, err := federator.ReadSnapshot(ctx, first.Snapshot, need)
+		if err != nil {
+			t.Fatalf("ReadSnapshot failed: %v", err)
+		}
+		for i, ranked := range first.RankedEntities {
+			if page.RankedEntities[i].Entity.ID != ranked.Entity.ID {
+				t.Fatalf("Result %d moved from %s to %s", i, ranked.Entity.ID, page.RankedEntities[i].Entity.ID)
+			}
+		}
+		if need == 12 && (len(page.RankedEntities) != 12 || !page.HasMore) {
+			t.Errorf("Expected 12 results with more to come, got %d (has more: %v)", len(page.RankedEntities), page.HasMore)
+		}
+		if need == 100 && (len(page.RankedEntities) != 20 || page.HasMore) {
+			t.Errorf("Expected all 20 results, got %d (has more: %v)", len(page.RankedEntities), page.HasMore)
+		}
+	}
+
+	if _, err := federator.ReadSnapshot(ctx, "unknown", 1); !errors.Is(err, search.ErrSnapshotNotFound) {
+		t.Errorf("Expected ErrSnapshotNotFound, got %v", err)
+	}
+
+	// Results that fit in what the caller reads aren't stored
+	if all := federator.SearchSnapshot(ctx, search.NewSearchQuery(""), 100); all.Snapshot != "" {
+		t.Errorf("Expected no snapshot for a single page, got %q", all.Snapshot)
+	}
+
+	// The least recently read snapshot is dropped first
+	federator.SetMaxSnapshots(1)
+	if second := federator.SearchSnapshot(ctx, search.NewSearchQuery(""), 3); second.Snapshot == "" {
+		t.Error("Expected a snapshot")
+	}
+	if _, err := federator.ReadSnapshot(ctx, first.Snapshot, 1); !errors.Is(err, search.ErrSnapshotNotFound) {
+		t.Errorf("Expected the first snapshot to be dropped, got %v", err)
+	}
+
+	cursor := search.Cursor{Snapshot: first.Snapshot, Offset: 24}
+	if parsed, err := search.ParseCursor(cursor.String()); err != nil || parsed != cursor {
+		t.Errorf("Expected cursor %+v to round trip, got %+v (%v)", cursor, parsed, err)
+	}
+	if _, err := search.ParseCursor("not a cursor"); err == nil {
+		t.Error("Expected an error for an invalid cursor")
+	}
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/search/filters"
+	"github.com/yourname/mifind/internal/types"
+)
+
+func TestFilterExpression(t *testing.T) {
+	registry := types.NewTypeRegistry()
+	types.RegisterCoreTypes(registry)
+	parser := filters.NewParser(registry)
+
+	var filterData map[string]any
+	if err := json.Unmarshal([]byte(`{
+		"$or": [{"person": "alice"}, {"person": "bob"}],
+		"$not": {"album": "work"}
+	}`), &filterData); err != nil {
+		t.Fatal(err)
+	}
+
+	expr, err := parser.ParseExpression(filterData)
+	if err != nil {
+		t.Fatalf("ParseExpression failed: %v", err)
+	}
+	if err := expr.Validate(registry.GetAllAttributes()); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	entity := func(id string, people []string, album string) types.Entity {
+		e := types.NewEntity(id, "media.asset.photo", "mock", id)
+		e.AddAttribute(types.AttrPerson, people)
+		if album != "" {
+			e.AddAttribute(types.AttrAlbum, album)
+		}
+		return e
+	}
+	entities := []types.Entity{
+		entity("alice-holiday", []string{"alice"}, "holiday"),
+		entity("bob-work", []string{"bob"}, "work"),
+		entity("carol", []string{"carol"}, ""),
+		entity("bob-unsorted", []string{"bob", "carol"}, ""),
+	}
+
+	// The Spec encoding parses back to an equivalent expression
+	roundTrip, err := parser.ParseExpression(expr.Spec())
+	if err != nil {
+		t.Fatalf("ParseExpression of Spec failed: %v", err)
+	}
+
+	f := search.NewFilters(registry)
+	for name, e := range map[string]*filters.Expr{"parsed": expr, "spec": roundTrip} {
+		matched := f.ApplyFilters(entities, map[string]any{filters.KeyAnd: e})
+		var ids []string
+		for _, entity := range matched {
+			ids = append(ids, entity.ID)
+		}
+		if len(ids) != 2 || ids[0] != "alice-holiday" || ids[1] != "bob-unsorted" {
+			t.Errorf("%s: expected alice-holiday and bob-unsorted, got %v", name, ids)
+		}
+	}
+
+	// Malformed groups are rejected
+	for _, data := range []map[string]any{
+		{"$or": []any{}},
+		{"$xor": []any{map[string]any{"album": "work"}}},
+		{"$not": map[string]any{"colour": "red"}},
+	} {
+		if _, err := parser.ParseExpression(data); err == nil {
+			t.Errorf("expected error for %v", data)
+		}
+	}
+}
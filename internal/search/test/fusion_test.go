@@ -0,0 +1,54 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/types"
+)
+
+func TestFusionRanker(t *testing.T) {
+	entity := func(id, title, provider string, score any) search.EntityWithProvider {
+		e := types.NewEntity(id, types.TypeFile, "mock", title)
+		if score != nil {
+			e.AddAttribute("_score", score)
+		}
+		return search.EntityWithProvider{Entity: e, Provider: provider}
+	}
+	// The photo provider scores its results; the file provider only orders them
+	entities := []search.EntityWithProvider{
+		entity("photo-low", "IMG_0001.jpg", "immich:photos", 0.2),
+		entity("photo-high", "IMG_0002.jpg", "immich:photos", 0.9),
+		entity("photo-beach", "beach.jpg", "immich:photos", 0.5),
+		entity("file-first", "taxes.txt", "filesystem:docs", nil),
+		entity("file-beach", "Notes about the beach", "filesystem:docs", nil),
+	}
+
+	for _, method := range []string{search.FusionRRF, search.FusionNormalized} {
+		config := search.DefaultRankingConfig()
+		config.Fusion.Method = method
+		ranked, err := search.NewFusionRanker(config).Rank(context.Background(), entities, search.NewSearchQuery("beach"))
+		if err != nil {
+			t.Fatalf("%s: Rank failed: %v", method, err)
+		}
+
+		position := make(map[string]int, len(ranked))
+		for i, r := range ranked {
+			position[r.Entity.ID] = i
+		}
+
+		// Ranked first by its provider's score and by text relevance
+		if position["photo-beach"] != 0 {
+			t.Errorf("%s: expected photo-beach first, got %v", method, ranked)
+		}
+		// The provider's own scores order its results, not the order they came in
+		if position["photo-high"] > position["photo-low"] {
+			t.Errorf("%s: expected photo-high before photo-low, got %v", method, ranked)
+		}
+		// A text match ranks above results only their provider ranks highly
+		if position["file-beach"] > position["photo-high"] || position["file-beach"] > position["file-first"] {
+			t.Errorf("%s: expected file-beach before unmatched results, got %v", method, ranked)
+		}
+	}
+}
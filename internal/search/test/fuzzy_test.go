@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/types"
+)
+
+func TestInMemoryRanker_Fuzzy(t *testing.T) {
+	entity := func(id, title string, tokens []string) search.EntityWithProvider {
+		e := types.NewEntity(id, types.TypeFileDocument, "mock", title)
+		e.SearchTokens = tokens
+		return search.EntityWithProvider{Entity: e, Provider: "mock:test"}
+	}
+	entities := []search.EntityWithProvider{
+		entity("unrelated", "Tax return", nil),
+		entity("tokens", "IMG_0042.jpg", []string{"vacation", "lisbon"}),
+		entity("title", "Vacation in Lisbon", nil),
+		entity("partial", "Vacation photos", nil),
+	}
+
+	ranker := search.NewInMemoryRanker(search.DefaultRankingConfig())
+	ranked, err := ranker.Rank(context.Background(), entities, search.NewSearchQuery("vacaton lisbn"))
+	if err != nil {
+		t.Fatalf("Rank failed: %v", err)
+	}
+
+	want := []string{"title", "partial", "tokens", "unrelated"}
+	for i, r := range ranked {
+		if r.Entity.ID != want[i] {
+			var got []string
+			for _, r := range ranked {
+				got = append(got, r.Entity.ID)
+			}
+			t.Fatalf("expected order %v, got %v", want, got)
+		}
+	}
+}
+
+func TestFederator_Suggestion(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	registry := provider.NewRegistry()
+	if err := registry.Register(provider.ProviderMetadata{
+		Name:    "mock",
+		Factory: func() provider.Provider { return mock.NewMockProvider() },
+	}); err != nil {
+		t.Fatalf("Failed to register mock provider: %v", err)
+	}
+	manager := provider.NewManager(registry, &logger)
+	if err := manager.Initialize(ctx, "mock", map[string]any{"instance_id": "a", "entity_count": 10}); err != nil {
+		t.Fatalf("Failed to initialize mock provider: %v", err)
+	}
+	federator := search.NewFederator(manager, search.NewInMemoryRanker(search.DefaultRankingConfig()), &logger, 5*time.Second)
+
+	// Nothing has been seen yet
+	if response := federator.Search(ctx, search.NewSearchQuery("vidoe")); response.Suggestion != "" {
+		t.Errorf("Expected no suggestion from an empty vocabulary, got %q", response.Suggestion)
+	}
+
+	// Results add their title words to the vocabulary
+	federator.Search(ctx, search.NewSearchQuery(""))
+
+	tests := map[string]string{
+		"vidoe":       "video",
+		"Vidoe clips": "video clips",
+		"video":       "",
+		"xyz":         "",
+	}
+	for query, want := range tests {
+		if got := federator.Search(ctx, search.NewSearchQuery(query)).Suggestion; got != want {
+			t.Errorf("%q: expected suggestion %q, got %q", query, want, got)
+		}
+	}
+
+	federator.SetVocabularySize(0)
+	if got := federator.Search(ctx, search.NewSearchQuery("vidoe")).Suggestion; got != "" {
+		t.Errorf("Expected no suggestion with suggestions disabled, got %q", got)
+	}
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/search/filters"
+	"github.com/yourname/mifind/internal/types"
+)
+
+func TestParseQuery(t *testing.T) {
+	registry := types.NewTypeRegistry()
+	types.RegisterCoreTypes(registry)
+
+	query, err := search.ParseQuery(`type:photo camera:"Canon EOS" size>10MB modified:2024-01..2024-06 -extension:gif vacation`, registry, nil, nil)
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+
+	if query.Type != "media.asset.photo" {
+		t.Errorf("expected type media.asset.photo, got %q", query.Type)
+	}
+	if query.Query != "vacation" {
+		t.Errorf("expected query text %q, got %q", "vacation", query.Query)
+	}
+
+	camera := query.TypedFilters["camera"]
+	if camera == nil || camera.Operation() != filters.OpEq || camera.Value() != "Canon EOS" {
+		t.Errorf("expected camera eq Canon EOS, got %#v", camera)
+	}
+
+	size := query.TypedFilters["size"]
+	if size == nil || size.Operation() != filters.OpGt || size.Value() != int64(10<<20) {
+		t.Errorf("expected size gt 10MB, got %#v", size)
+	}
+
+	extension := query.TypedFilters["extension"]
+	if extension == nil || extension.Operation() != filters.OpNeq || extension.Value() != "gif" {
+		t.Errorf("expected extension neq gif, got %#v", extension)
+	}
+
+	modified, ok := query.TypedFilters["modified"].(*filters.DateRangeFilter)
+	if !ok || modified.Min == nil || modified.Max == nil {
+		t.Fatalf("expected modified date range, got %#v", query.TypedFilters["modified"])
+	}
+	if want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !modified.Min.Equal(want) {
+		t.Errorf("expected range start %v, got %v", want, modified.Min)
+	}
+	if want := time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC); !modified.Max.Equal(want) {
+		t.Errorf("expected range end %v, got %v", want, modified.Max)
+	}
+}
+
+func TestParseQuery_ErrorPositions(t *testing.T) {
+	registry := types.NewTypeRegistry()
+	types.RegisterCoreTypes(registry)
+
+	tests := []struct {
+		input string
+		pos   int
+	}{
+		{`type:photo colour:red`, 11},
+		{`vacation camera:"Canon EOS`, 16},
+		{`size>big`, 5},
+	}
+
+	for _, tt := range tests {
+		_, err := search.ParseQuery(tt.input, registry, nil, nil)
+		var queryErr *search.QueryError
+		if !errors.As(err, &queryErr) {
+			t.Errorf("%s: expected QueryError, got %v", tt.input, err)
+			continue
+		}
+		if queryErr.Pos != tt.pos {
+			t.Errorf("%s: expected error at %d, got %d (%v)", tt.input, tt.pos, queryErr.Pos, queryErr)
+		}
+	}
+}
+
+func TestParseQuery_AttributeExtensions(t *testing.T) {
+	registry := types.NewTypeRegistry()
+	types.RegisterCoreTypes(registry)
+
+	attrs := registry.GetAllAttributes()
+	attrs["rating"] = types.AttributeDef{
+		Name:       "rating",
+		Type:       types.AttributeTypeInt,
+		Filterable: true,
+		Filter:     types.FilterConfig{SupportsEq: true, SupportsRange: true},
+	}
+
+	if _, err := search.ParseQuery(`rating>3`, registry, nil, nil); err == nil {
+		t.Error("expected rating to be unknown without the extension")
+	}
+
+	query, err := search.ParseQuery(`rating>3`, registry, attrs, nil)
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+	rating := query.TypedFilters["rating"]
+	if rating == nil || rating.Operation() != filters.OpGt || rating.Value() != int64(3) {
+		t.Errorf("expected rating gt 3, got %#v", rating)
+	}
+}
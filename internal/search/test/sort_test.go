@@ -0,0 +1,55 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourname/mifind/internal/search"
+	"github.com/yourname/mifind/internal/types"
+)
+
+func TestSortEntities(t *testing.T) {
+	registry := types.NewTypeRegistry()
+	types.RegisterCoreTypes(registry)
+
+	clauses, err := search.ParseSort("size desc, modified DESC", registry)
+	if err != nil {
+		t.Fatalf("ParseSort failed: %v", err)
+	}
+	if len(clauses) != 2 || clauses[0].String() != "size desc" || clauses[1].Type != types.AttributeTypeTime {
+		t.Fatalf("unexpected clauses %v", clauses)
+	}
+
+	entity := func(id string, score float64, attrs map[string]any) search.RankedEntity {
+		e := types.NewEntity(id, types.TypeFile, "mock", id)
+		for key, value := range attrs {
+			e.AddAttribute(key, value)
+		}
+		return search.RankedEntity{Entity: e, Score: score}
+	}
+	modified := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// Providers report sizes and times in different representations
+	entities := []search.RankedEntity{
+		entity("no-size", 0.9, map[string]any{types.AttrModified: modified}),
+		entity("small", 0.8, map[string]any{types.AttrSize: int64(10)}),
+		entity("large-old", 0.1, map[string]any{types.AttrSize: 2048.0, types.AttrModified: modified.Unix() - 60}),
+		entity("large-new", 0.2, map[string]any{types.AttrSize: int64(2048), types.AttrModified: modified.Format(time.RFC3339)}),
+		entity("large-unknown-high", 0.7, map[string]any{types.AttrSize: 2048}),
+		entity("large-unknown-low", 0.3, map[string]any{types.AttrSize: 2048}),
+	}
+	search.SortEntities(entities, clauses)
+
+	want := []string{"large-new", "large-old", "large-unknown-high", "large-unknown-low", "small", "no-size"}
+	for i, ranked := range entities {
+		if ranked.Entity.ID != want[i] {
+			t.Fatalf("expected order %v, got %s at %d", want, ranked.Entity.ID, i)
+		}
+	}
+
+	for _, spec := range []string{"colour desc", "size sideways", "person asc", "size, size desc", "size desc extra"} {
+		if _, err := search.ParseSort(spec, registry); err == nil {
+			t.Errorf("%q: expected error", spec)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English words that carry no relevance on their own.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+	"with": true,
+}
+
+// stemSuffixes are the suffixes stripped by stem, longest first, with their
+// replacement.
+var stemSuffixes = []struct {
+	suffix, replacement string
+}{
+	{"ational", "ate"},
+	{"ization", "ize"},
+	{"fulness", "ful"},
+	{"iveness", "ive"},
+	{"ingly", ""},
+	{"ments", "ment"},
+	{"ches", "ch"},
+	{"shes", "sh"},
+	{"sses", "ss"},
+	{"ness", ""},
+	{"edly", ""},
+	{"xes", "x"},
+	{"ies", "i"},
+	{"ing", ""},
+	{"ed", ""},
+	{"ly", ""},
+	{"s", ""},
+}
+
+// tokenize splits text into lowercase terms at anything that isn't a letter
+// or digit, drops stop words and stems the rest:
+//
+//	"Holiday photos from Lisbon" → holiday, photo, lisbon
+func tokenize(text string) []string {
+	words := splitWords(text)
+	terms := words[:0]
+	for _, word := range words {
+		if stopWords[word] {
+			continue
+		}
+		terms = append(terms, stem(word))
+	}
+	return terms
+}
+
+// splitWords splits text into lowercase words at anything that isn't a
+// letter or digit.
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// stem reduces an English word to its stem by stripping a common suffix,
+// so that photos and photo, hiking and hike, or parties and party stem the
+// same. It is deliberately light: stems need not be words (hik, parti).
+// Short words and words with digits are kept as they are.
+func stem(word string) string {
+	if len(word) <= 3 || strings.ContainsFunc(word, unicode.IsDigit) {
+		return word
+	}
+
+	for _, rule := range stemSuffixes {
+		base, ok := strings.CutSuffix(word, rule.suffix)
+		if !ok || len(base)+len(rule.replacement) < 3 {
+			continue
+		}
+		// Keep plural-looking endings of glass, virus and analysis
+		if rule.suffix == "s" && strings.ContainsAny(base[len(base)-1:], "siu") {
+			continue
+		}
+		word = base + rule.replacement
+
+		// running → run, but not calling → cal
+		n := len(word)
+		if (rule.suffix == "ing" || rule.suffix == "ed") && word[n-1] == word[n-2] && !strings.ContainsAny(word[n-1:], "aeioulsz") {
+			word = word[:n-1]
+		}
+		break
+	}
+
+	// hike and hiking, party and parties
+	if len(word) > 3 {
+		if base, ok := strings.CutSuffix(word, "e"); ok {
+			word = base
+		} else if base, ok := strings.CutSuffix(word, "y"); ok {
+			word = base + "i"
+		}
+	}
+	return word
+}
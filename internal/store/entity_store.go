@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Config configures the local entity store.
+type Config struct {
+	// Enabled turns on the local entity store
+	Enabled bool `mapstructure:"enabled"`
+
+	// Path is the bbolt database file
+	Path string `mapstructure:"path"`
+}
+
+// DefaultConfig returns the default entity store configuration.
+func DefaultConfig() Config {
+	return Config{
+		Enabled: false,
+		Path:    "data/entities.db",
+	}
+}
+
+// EntityStore is a local, persistent store of entity summaries backed by bbolt.
+// Entities are kept in one bucket per provider instance, keyed by entity ID.
+// The store is fed by provider discovery and is not a source of truth: it can
+// be deleted at any time and is rebuilt by the next full sync.
+type EntityStore struct {
+	db *bolt.DB
+}
+
+// Open opens (or creates) the entity store at the given path.
+func Open(path string) (*EntityStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create entity store directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open entity store %s: %w", path, err)
+	}
+
+	return &EntityStore{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *EntityStore) Close() error {
+	return s.db.Close()
+}
+
+// Put inserts or updates entities for a provider instance.
+func (s *EntityStore) Put(instanceKey string, entities []types.Entity) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(instanceKey))
+		if err != nil {
+			return fmt.Errorf("failed to create bucket for %q: %w", instanceKey, err)
+		}
+		return putEntities(bucket, entities)
+	})
+}
+
+// Replace replaces all entities of a provider instance, dropping any that are
+// no longer present. Used for full syncs.
+func (s *EntityStore) Replace(instanceKey string, entities []types.Entity) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(instanceKey)) != nil {
+			if err := tx.DeleteBucket([]byte(instanceKey)); err != nil {
+				return fmt.Errorf("failed to clear bucket for %q: %w", instanceKey, err)
+			}
+		}
+		bucket, err := tx.CreateBucket([]byte(instanceKey))
+		if err != nil {
+			return fmt.Errorf("failed to create bucket for %q: %w", instanceKey, err)
+		}
+		return putEntities(bucket, entities)
+	})
+}
+
+// Delete removes all entities of a provider instance.
+func (s *EntityStore) Delete(instanceKey string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(instanceKey)) == nil {
+			return nil
+		}
+		return tx.DeleteBucket([]byte(instanceKey))
+	})
+}
+
+// Get returns a single entity by ID.
+func (s *EntityStore) Get(id string) (types.Entity, bool, error) {
+	var entity types.Entity
+	found := false
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(provider.EntityID(id).InstanceKey()))
+		if bucket == nil {
+			return nil
+		}
+		data := bucket.Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &entity)
+	})
+	if err != nil {
+		return types.Entity{}, false, fmt.Errorf("failed to read entity %q: %w", id, err)
+	}
+
+	return entity, found, nil
+}
+
+// Count returns the number of stored entities for a provider instance.
+func (s *EntityStore) Count(instanceKey string) (int, error) {
+	count := 0
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if bucket := tx.Bucket([]byte(instanceKey)); bucket != nil {
+			count = bucket.Stats().KeyN
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Search returns stored entities of a provider instance that match the query
+// text and type. Every whitespace-separated term of the query must appear in
+// the title, description, search tokens or a string attribute.
+// Attribute filters are left to the caller.
+func (s *EntityStore) Search(instanceKey string, query provider.SearchQuery) ([]types.Entity, error) {
+	terms := strings.Fields(strings.ToLower(query.Query))
+	entities := make([]types.Entity, 0)
+	skipped := 0
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(instanceKey))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(_, data []byte) error {
+			var entity types.Entity
+			if err := json.Unmarshal(data, &entity); err != nil {
+				return err
+			}
+
+			if !matchesType(entity.Type, query.Type) || !matchesTerms(entity, terms) {
+				return nil
+			}
+
+			// Apply offset and limit
+			if skipped < query.Offset {
+				skipped++
+				return nil
+			}
+			if query.Limit > 0 && len(entities) >= query.Limit {
+				return nil
+			}
+
+			entities = append(entities, entity)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search entity store for %q: %w", instanceKey, err)
+	}
+
+	return entities, nil
+}
+
+// HandleSync stores the entities of a sync run. It matches provider.SyncHandler,
+// so it can be registered with Syncer.OnSync.
+func (s *EntityStore) HandleSync(_ context.Context, instanceKey string, entities []types.Entity, full bool) error {
+	if full {
+		return s.Replace(instanceKey, entities)
+	}
+	return s.Put(instanceKey, entities)
+}
+
+// putEntities encodes and writes entities to a bucket.
+func putEntities(bucket *bolt.Bucket, entities []types.Entity) error {
+	for _, entity := range entities {
+		data, err := json.Marshal(entity)
+		if err != nil {
+			return fmt.Errorf("failed to encode entity %q: %w", entity.ID, err)
+		}
+		if err := bucket.Put([]byte(entity.ID), data); err != nil {
+			return fmt.Errorf("failed to store entity %q: %w", entity.ID, err)
+		}
+	}
+	return nil
+}
+
+// matchesType checks if an entity type equals or descends from the queried type.
+func matchesType(entityType, queryType string) bool {
+	if queryType == "" {
+		return true
+	}
+	return entityType == queryType || strings.HasPrefix(entityType, queryType+".")
+}
+
+// matchesTerms checks that every query term appears somewhere in the entity's text.
+func matchesTerms(entity types.Entity, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+
+	fields := []string{entity.Title, entity.Description}
+	fields = append(fields, entity.SearchTokens...)
+	for _, value := range entity.Attributes {
+		if str, ok := value.(string); ok {
+			fields = append(fields, str)
+		}
+	}
+	text := strings.ToLower(strings.Join(fields, " "))
+
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/store"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// TestEntityStore_SyncAndSearch tests that full syncs replace an instance's
+// entities, incremental syncs add to them, and search matches text and type.
+func TestEntityStore_SyncAndSearch(t *testing.T) {
+	ctx := context.Background()
+
+	s, err := store.Open(filepath.Join(t.TempDir(), "entities.db"))
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	defer s.Close()
+
+	photo := types.NewEntity("immich:home:1", "media.asset.photo", "immich", "Beach sunset")
+	video := types.NewEntity("immich:home:2", "media.asset.video", "immich", "Beach volleyball")
+	stale := types.NewEntity("immich:home:3", "media.asset.photo", "immich", "Old beach")
+
+	if err := s.HandleSync(ctx, "immich:home", []types.Entity{photo, stale}, true); err != nil {
+		t.Fatalf("Full sync failed: %v", err)
+	}
+	if err := s.HandleSync(ctx, "immich:home", []types.Entity{photo, video}, true); err != nil {
+		t.Fatalf("Full sync failed: %v", err)
+	}
+
+	if _, found, _ := s.Get("immich:home:3"); found {
+		t.Error("Expected full sync to drop entities that are no longer present")
+	}
+
+	results, err := s.Search("immich:home", provider.SearchQuery{Query: "beach"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("Expected 2 results for 'beach', got %d", len(results))
+	}
+
+	results, err = s.Search("immich:home", provider.SearchQuery{Query: "beach", Type: "media.asset.photo"})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != photo.ID {
+		t.Errorf("Expected only the photo for type media.asset.photo, got %v", results)
+	}
+
+	extra := types.NewEntity("immich:home:4", "media.asset.photo", "immich", "Mountain")
+	if err := s.HandleSync(ctx, "immich:home", []types.Entity{extra}, false); err != nil {
+		t.Fatalf("Incremental sync failed: %v", err)
+	}
+	if count, _ := s.Count("immich:home"); count != 3 {
+		t.Errorf("Expected 3 entities after incremental sync, got %d", count)
+	}
+}
@@ -376,7 +376,8 @@ var (
 			Priority: 25,
 		},
 		Filter: FilterConfig{
-			Cacheable: false,
+			SupportsGeo: true,
+			Cacheable:   false,
 		},
 	}
 
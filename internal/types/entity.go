@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 // Entity represents a lightweight item in the mifind system.
 // It is a unified representation that can come from any provider.
@@ -59,6 +62,49 @@ func NewEntity(id, entityType, provider, title string) Entity {
 	}
 }
 
+// Clone returns a copy of the entity that shares no maps or slices with it,
+// including list and map attribute values.
+func (e Entity) Clone() Entity {
+	if e.Attributes != nil {
+		attributes := make(map[string]any, len(e.Attributes))
+		for key, value := range e.Attributes {
+			attributes[key] = cloneValue(value)
+		}
+		e.Attributes = attributes
+	}
+	e.Relationships = slices.Clone(e.Relationships)
+	e.SearchTokens = slices.Clone(e.SearchTokens)
+	return e
+}
+
+// cloneValue copies list and map attribute values.
+func cloneValue(value any) any {
+	switch v := value.(type) {
+	case []string:
+		return slices.Clone(v)
+	case []any:
+		if v == nil {
+			return v
+		}
+		list := make([]any, len(v))
+		for i, item := range v {
+			list[i] = cloneValue(item)
+		}
+		return list
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[key] = cloneValue(item)
+		}
+		return m
+	default:
+		return value
+	}
+}
+
 // AddAttribute adds a key-value pair to the entity's attributes.
 func (e *Entity) AddAttribute(key string, value any) {
 	if e.Attributes == nil {
@@ -2,6 +2,8 @@ package types
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +13,9 @@ import (
 type TypeRegistry struct {
 	mu    sync.RWMutex
 	types map[string]*TypeDefinition
+
+	// owners maps types contributed by providers to the provider name
+	owners map[string]string
 }
 
 // TypeDefinition defines a type in the hierarchy with its attributes and filters.
@@ -92,6 +97,9 @@ type FilterConfig struct {
 	// SupportsContains indicates if substring matching is supported
 	SupportsContains bool
 
+	// SupportsGeo indicates if radius and bounding box filtering is supported
+	SupportsGeo bool
+
 	// Cacheable indicates if filter values should be cached
 	Cacheable bool
 
@@ -206,7 +214,8 @@ type FilterOption struct {
 // NewTypeRegistry creates a new empty TypeRegistry.
 func NewTypeRegistry() *TypeRegistry {
 	return &TypeRegistry{
-		types: make(map[string]*TypeDefinition),
+		types:  make(map[string]*TypeDefinition),
+		owners: make(map[string]string),
 	}
 }
 
@@ -236,6 +245,139 @@ func (r *TypeRegistry) Register(def TypeDefinition) error {
 	return nil
 }
 
+// RegisterProvided registers the type definitions contributed by a provider.
+// Definitions may use each other as parents, in any order. A type that is
+// already registered is kept if the new definition agrees with it (same
+// parent, same types for shared attributes). Otherwise the definitions
+// conflict, an error naming the type's owner is returned and none of them
+// are registered. An attribute has one type across all types, so a new
+// definition that gives an attribute another type than a registered one
+// conflicts too.
+func (r *TypeRegistry) RegisterProvided(providerName string, defs []TypeDefinition) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := make(map[string]TypeDefinition, len(defs))
+	for _, def := range defs {
+		if def.Name == "" {
+			return fmt.Errorf("provider %q: type definition without a name", providerName)
+		}
+		if existing, ok := r.types[def.Name]; ok {
+			if err := compatible(existing, &def); err != nil {
+				owner := r.owners[def.Name]
+				if owner == "" {
+					owner = "core types"
+				} else {
+					owner = fmt.Sprintf("provider %q", owner)
+				}
+				return fmt.Errorf("provider %q: type %q conflicts with the definition from %s: %w", providerName, def.Name, owner, err)
+			}
+			continue
+		}
+		if previous, ok := pending[def.Name]; ok {
+			if err := compatible(&previous, &def); err != nil {
+				return fmt.Errorf("provider %q: type %q is defined twice: %w", providerName, def.Name, err)
+			}
+			continue
+		}
+		pending[def.Name] = def
+	}
+
+	// Every new type must lead up to a registered type or a root
+	for name, def := range pending {
+		for parent, depth := def.Parent, 0; parent != ""; depth++ {
+			if _, ok := r.types[parent]; ok {
+				break
+			}
+			next, ok := pending[parent]
+			if !ok {
+				return fmt.Errorf("provider %q: parent type %q not found for type %q", providerName, parent, name)
+			}
+			if depth > len(pending) {
+				return fmt.Errorf("provider %q: type %q is its own ancestor", providerName, name)
+			}
+			parent = next.Parent
+		}
+	}
+
+	// Attribute types must agree with every registered type, not only the
+	// ones of the same name
+	attrTypes := r.attributeTypesLocked()
+	for _, name := range slices.Sorted(maps.Keys(pending)) {
+		for attrName, attr := range pending[name].Attributes {
+			if current, ok := attrTypes[attrName]; ok && current != attr.Type {
+				return fmt.Errorf("provider %q: type %q: attribute %q has type %q instead of %q", providerName, name, attrName, attr.Type, current)
+			}
+			attrTypes[attrName] = attr.Type
+		}
+	}
+
+	for name, def := range pending {
+		if def.Attributes == nil {
+			def.Attributes = make(map[string]AttributeDef)
+		}
+		r.types[name] = &def
+		r.owners[name] = providerName
+	}
+	return nil
+}
+
+// attributeTypesLocked returns the type of every core and registered
+// attribute. The caller must hold mu.
+func (r *TypeRegistry) attributeTypesLocked() map[string]AttributeType {
+	attrTypes := make(map[string]AttributeType, len(CoreAttributes))
+	for name, attr := range CoreAttributes {
+		attrTypes[name] = attr.Type
+	}
+	for _, def := range r.types {
+		for name, attr := range def.Attributes {
+			attrTypes[name] = attr.Type
+		}
+	}
+	return attrTypes
+}
+
+// sortedTypesLocked returns the registered types ordered by name, the types
+// registered directly before the ones contributed by providers. The caller
+// must hold mu.
+func (r *TypeRegistry) sortedTypesLocked() []*TypeDefinition {
+	names := slices.SortedFunc(maps.Keys(r.types), func(a, b string) int {
+		if providedA, providedB := r.owners[a] != "", r.owners[b] != ""; providedA != providedB {
+			if providedA {
+				return 1
+			}
+			return -1
+		}
+		return strings.Compare(a, b)
+	})
+	defs := make([]*TypeDefinition, len(names))
+	for i, name := range names {
+		defs[i] = r.types[name]
+	}
+	return defs
+}
+
+// compatible returns an error describing how def disagrees with existing.
+func compatible(existing, def *TypeDefinition) error {
+	if existing.Parent != def.Parent {
+		return fmt.Errorf("parent %q differs from %q", def.Parent, existing.Parent)
+	}
+	for name, attr := range def.Attributes {
+		if current, ok := existing.Attributes[name]; ok && current.Type != attr.Type {
+			return fmt.Errorf("attribute %q has type %q instead of %q", name, attr.Type, current.Type)
+		}
+	}
+	return nil
+}
+
+// Owner returns the name of the provider that contributed a type, or "" for
+// types registered directly.
+func (r *TypeRegistry) Owner(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.owners[name]
+}
+
 // Get retrieves a type definition by name.
 // Returns nil if the type doesn't exist.
 func (r *TypeRegistry) Get(name string) *TypeDefinition {
@@ -282,6 +424,31 @@ func (r *TypeRegistry) GetDescendants(name string) []*TypeDefinition {
 	return descendants
 }
 
+// IsA reports whether name is the type ancestor or one of its descendants.
+// Unregistered types are placed under their longest registered dotted prefix,
+// so "media.asset.jellyfin.movie" is a "media.asset" even if only the latter
+// is registered. An unregistered type without one is only a type of itself,
+// since nothing is known about where it belongs.
+func (r *TypeRegistry) IsA(name, ancestor string) bool {
+	current := name
+	for current != ancestor {
+		if r.Get(current) != nil {
+			for _, def := range r.GetAncestors(current) {
+				if def.Name == ancestor {
+					return true
+				}
+			}
+			return false
+		}
+		i := strings.LastIndex(current, ".")
+		if i < 0 {
+			return false
+		}
+		current = current[:i]
+	}
+	return true
+}
+
 func (r *TypeRegistry) getDescendantsLocked(name string) []*TypeDefinition {
 	var descendants []*TypeDefinition
 	for _, def := range r.types {
@@ -320,9 +487,20 @@ func (r *TypeRegistry) GetAllAttributes() map[string]AttributeDef {
 		attrs[name] = attrDef
 	}
 
-	// Add/override with attributes from all registered types
-	for _, typeDef := range r.types {
+	// Add/override with attributes from all registered types, core types
+	// first and in name order so that the result doesn't vary. Types usually
+	// only declare core attributes, so they keep the core filter and UI
+	// metadata unless they set their own.
+	for _, typeDef := range r.sortedTypesLocked() {
 		for name, attr := range typeDef.Attributes {
+			if core, ok := CoreAttributes[name]; ok {
+				if attr.Filter == (FilterConfig{}) {
+					attr.Filter = core.Filter
+				}
+				if attr.UI == (UIConfig{}) {
+					attr.UI = core.UI
+				}
+			}
 			attrs[name] = attr
 		}
 	}
@@ -0,0 +1,37 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/pkg/provider/filesystem"
+	"github.com/yourname/mifind/pkg/provider/gitlab"
+	"github.com/yourname/mifind/pkg/provider/httpjson"
+	"github.com/yourname/mifind/pkg/provider/immich"
+	"github.com/yourname/mifind/pkg/provider/jellyfin"
+)
+
+// Factories returns the factories of all provider types that ship with mifind.
+// New provider types are added here; the binaries pick them up through Register.
+func Factories() []provider.ProviderFactory {
+	return []provider.ProviderFactory{
+		func() provider.Provider { return mock.NewMockProvider() },
+		func() provider.Provider { return filesystem.NewProvider() },
+		func() provider.Provider { return immich.NewProvider() },
+		func() provider.Provider { return jellyfin.NewProvider() },
+		func() provider.Provider { return gitlab.NewProvider() },
+		func() provider.Provider { return httpjson.NewProvider() },
+	}
+}
+
+// Register registers all built-in provider types with the registry,
+// using the metadata and config schema each provider declares.
+func Register(registry *provider.Registry) error {
+	for _, factory := range Factories() {
+		if err := registry.RegisterFactory(factory); err != nil {
+			return fmt.Errorf("failed to register provider: %w", err)
+		}
+	}
+	return nil
+}
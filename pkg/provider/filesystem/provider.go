@@ -41,6 +41,7 @@ func NewProvider() *Provider {
 					Type:        "string",
 					Required:    false,
 					Description: "API key for authentication",
+					Secret:      true,
 				},
 			},
 		}),
@@ -278,6 +279,23 @@ func (p *Provider) SupportsRelevanceScore() bool {
 	return false
 }
 
+// SupportsFilterExpressions returns true - filter groups are passed to the
+// filesystem-api service, which translates them to Meilisearch filters.
+func (p *Provider) SupportsFilterExpressions() bool {
+	return true
+}
+
+// EntityTypes returns the types FileTypeToMifindType maps files and directories to.
+func (p *Provider) EntityTypes() []string {
+	return entityTypes
+}
+
+// Health checks that the filesystem-api service is reachable.
+func (p *Provider) Health(ctx context.Context) error {
+	_, err := p.client.Health(ctx)
+	return err
+}
+
 // Shutdown shuts down the filesystem provider.
 func (p *Provider) Shutdown(ctx context.Context) error {
 	// No cleanup needed
@@ -58,6 +58,23 @@ type SearchRequest struct {
 	Offset  int            `json:"offset,omitempty"`
 }
 
+// entityTypes lists every type FileTypeToMifindType returns.
+var entityTypes = []string{
+	types.TypeCollectionFolder,
+	types.TypeFile,
+	types.TypeFileMediaImage,
+	types.TypeFileMediaVideo,
+	types.TypeFileMediaMusic,
+	types.TypeFileDocumentPDF,
+	types.TypeFileDocumentWord,
+	types.TypeFileDocumentSheet,
+	types.TypeFileDocumentPres,
+	types.TypeFileDocumentText,
+	types.TypeFileDocumentHTML,
+	types.TypeFileDocumentCode,
+	types.TypeFileArchive,
+}
+
 // FileTypeToMifindType converts a file extension to a mifind core type.
 func FileTypeToMifindType(extension string, isDir bool) string {
 	if isDir {
@@ -3,6 +3,10 @@ package gitlab
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -37,6 +41,7 @@ func NewProvider() *Provider {
 					Type:        "string",
 					Required:    true,
 					Description: "GitLab personal access token",
+					Secret:      true,
 				},
 				"search_issues": {
 					Type:        "bool",
@@ -88,8 +93,13 @@ func (p *Provider) Initialize(ctx context.Context, config map[string]any) error
 	}
 	p.searchIssues = searchIssues
 
-	// Get projects list (optional)
-	if projects, ok := config["projects"].([]interface{}); ok {
+	// Get projects list (optional); YAML config decodes it as []interface{}
+	switch projects := config["projects"].(type) {
+	case []string:
+		for _, path := range projects {
+			p.configuredProjects[path] = true
+		}
+	case []interface{}:
 		for _, proj := range projects {
 			if path, ok := proj.(string); ok {
 				p.configuredProjects[path] = true
@@ -105,7 +115,7 @@ func (p *Provider) Initialize(ctx context.Context, config map[string]any) error
 	p.client = client
 
 	// Test connection by fetching current user
-	if _, _, err := p.client.Users.CurrentUser(); err != nil {
+	if _, _, err := p.client.Users.CurrentUser(gitlab.WithContext(ctx)); err != nil {
 		return provider.NewProviderError(provider.ErrorTypeAuth, "failed to connect to GitLab", err)
 	}
 
@@ -129,7 +139,7 @@ func (p *Provider) DiscoverSince(ctx context.Context, since time.Time) ([]types.
 	opts.ListOptions.Page = 1
 	opts.ListOptions.PerPage = 50
 
-	projects, _, err := p.client.Projects.ListProjects(opts)
+	projects, _, err := p.client.Projects.ListProjects(opts, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("list projects failed: %w", err)
 	}
@@ -156,12 +166,15 @@ func (p *Provider) Hydrate(ctx context.Context, id string) (types.Entity, error)
 
 	if hasIssue {
 		// Get issue - GitLab API expects int64 for IID
-		issue, _, err := p.client.Issues.GetIssue(projID, int64(issueIID))
-		if err != nil {
+		issue, resp, err := p.client.Issues.GetIssue(projID, int64(issueIID), gitlab.WithContext(ctx))
+		if isNotFound(resp) {
 			return types.Entity{}, provider.ErrNotFound
 		}
+		if err != nil {
+			return types.Entity{}, err
+		}
 		// Get project for context
-		project, _, err := p.client.Projects.GetProject(projID, nil)
+		project, _, err := p.client.Projects.GetProject(projID, nil, gitlab.WithContext(ctx))
 		if err != nil {
 			return p.issueToEntity(nil, issue), nil
 		}
@@ -169,14 +182,22 @@ func (p *Provider) Hydrate(ctx context.Context, id string) (types.Entity, error)
 	}
 
 	// Get project
-	project, _, err := p.client.Projects.GetProject(projID, nil)
-	if err != nil {
+	project, resp, err := p.client.Projects.GetProject(projID, nil, gitlab.WithContext(ctx))
+	if isNotFound(resp) {
 		return types.Entity{}, provider.ErrNotFound
 	}
+	if err != nil {
+		return types.Entity{}, err
+	}
 
 	return p.projectToEntity(project), nil
 }
 
+// isNotFound reports whether a GitLab API response is a 404.
+func isNotFound(resp *gitlab.Response) bool {
+	return resp != nil && resp.Response != nil && resp.StatusCode == http.StatusNotFound
+}
+
 // GetRelated retrieves entities related to an entity.
 func (p *Provider) GetRelated(ctx context.Context, id string, relType string) ([]types.Entity, error) {
 	entityID, err := provider.ParseEntityID(id)
@@ -197,13 +218,13 @@ func (p *Provider) GetRelated(ctx context.Context, id string, relType string) ([
 		opts := &gitlab.ListProjectIssuesOptions{}
 		opts.ListOptions.PerPage = 100
 
-		issues, _, err := p.client.Issues.ListProjectIssues(projID, opts)
+		issues, _, err := p.client.Issues.ListProjectIssues(projID, opts, gitlab.WithContext(ctx))
 		if err != nil {
 			return nil, err
 		}
 
 		// Get project for context
-		project, _, _ := p.client.Projects.GetProject(projID, nil)
+		project, _, _ := p.client.Projects.GetProject(projID, nil, gitlab.WithContext(ctx))
 
 		entities := make([]types.Entity, 0, len(issues))
 		for _, issue := range issues {
@@ -213,7 +234,7 @@ func (p *Provider) GetRelated(ctx context.Context, id string, relType string) ([
 
 	case RelProject:
 		// Get project for an issue
-		project, _, err := p.client.Projects.GetProject(projID, nil)
+		project, _, err := p.client.Projects.GetProject(projID, nil, gitlab.WithContext(ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -225,63 +246,92 @@ func (p *Provider) GetRelated(ctx context.Context, id string, relType string) ([
 }
 
 // Search performs a search query on this provider.
+// Visibility and archived filters only apply to projects, state and labels
+// filters only to issues, so a filtered search returns one kind or the other.
 func (p *Provider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
 	var entities []types.Entity
 
-	// Search projects
-	projectOpts := &gitlab.ListProjectsOptions{
-		Search: gitlab.Ptr(query.Query),
-	}
+	_, hasVisibility := query.Filters[AttrVisibility]
+	_, hasArchived := query.Filters[AttrArchived]
+	_, hasState := query.Filters[AttrState]
+	_, hasLabels := query.Filters[AttrLabels]
+	projectFilters := hasVisibility || hasArchived
+	issueFilters := hasState || hasLabels
+
+	// GitLab pages each list separately, so fetch everything up to the end
+	// of the requested page and slice the combined results below.
+	var perPage int64
 	if query.Limit > 0 {
-		projectOpts.ListOptions.PerPage = int64(query.Limit)
+		perPage = int64(query.Offset + query.Limit)
 	}
 
-	// Apply filters
-	if archived, ok := query.Filters[AttrArchived].(bool); ok {
-		projectOpts.Archived = &archived
-	}
-	if visibility, ok := query.Filters[AttrVisibility].(string); ok {
-		v := gitlab.VisibilityValue(visibility)
-		projectOpts.Visibility = &v
-	}
+	// Search projects
+	if !issueFilters && (query.Type == TypeProject || query.Type == "") {
+		projectOpts := &gitlab.ListProjectsOptions{
+			Search: gitlab.Ptr(query.Query),
+		}
+		projectOpts.ListOptions.PerPage = perPage
 
-	projects, _, err := p.client.Projects.ListProjects(projectOpts)
-	if err != nil {
-		return nil, fmt.Errorf("list projects failed: %w", err)
-	}
+		// Apply filters
+		if archived, ok := query.Filters[AttrArchived].(bool); ok {
+			projectOpts.Archived = &archived
+		}
+		if visibility, ok := query.Filters[AttrVisibility].(string); ok {
+			v := gitlab.VisibilityValue(visibility)
+			projectOpts.Visibility = &v
+		}
 
-	for _, proj := range projects {
-		entities = append(entities, p.projectToEntity(proj))
+		projects, _, err := p.client.Projects.ListProjects(projectOpts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("list projects failed: %w", err)
+		}
+
+		for _, proj := range projects {
+			entities = append(entities, p.projectToEntity(proj))
+		}
 	}
 
 	// Search issues if enabled and we have configured projects
-	if p.searchIssues && (query.Type == TypeIssue || query.Type == "") {
+	if p.searchIssues && !projectFilters && (query.Type == TypeIssue || query.Type == "") {
+		projPaths := make([]string, 0, len(p.configuredProjects))
 		for projPath := range p.configuredProjects {
+			projPaths = append(projPaths, projPath)
+		}
+		sort.Strings(projPaths)
+
+		for _, projPath := range projPaths {
 			issueOpts := &gitlab.ListProjectIssuesOptions{
 				Search: gitlab.Ptr(query.Query),
 			}
 			issueOpts.ListOptions.PerPage = 50
+			if perPage > 0 {
+				issueOpts.ListOptions.PerPage = perPage
+			}
 
 			// Apply filters
 			if state, ok := query.Filters[AttrState].(string); ok {
 				issueOpts.State = &state
 			}
-			if labels, ok := query.Filters[AttrLabels].([]string); ok && len(labels) > 0 {
+			if labels := provider.StringList(query.Filters[AttrLabels]); len(labels) > 0 {
 				labelOpts := gitlab.LabelOptions(labels)
 				issueOpts.Labels = &labelOpts
 			}
 
-			issues, _, err := p.client.Issues.ListProjectIssues(projPath, issueOpts)
-			if err == nil {
-				project, _, _ := p.client.Projects.GetProject(projPath, nil)
-				for _, issue := range issues {
-					entities = append(entities, p.issueToEntity(project, issue))
+			issues, _, err := p.client.Issues.ListProjectIssues(projPath, issueOpts, gitlab.WithContext(ctx))
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, fmt.Errorf("list issues failed: %w", err)
 				}
+				continue
+			}
+			project, _, _ := p.client.Projects.GetProject(projPath, nil, gitlab.WithContext(ctx))
+			for _, issue := range issues {
+				entities = append(entities, p.issueToEntity(project, issue))
 			}
 		}
 	}
 
-	return entities, nil
+	return query.Paginate(entities), nil
 }
 
 // FilterCapabilities returns the filter capabilities for GitLab.
@@ -334,7 +384,7 @@ func (p *Provider) FilterValues(ctx context.Context, filterName string) ([]provi
 		for projPath := range p.configuredProjects {
 			opts := &gitlab.ListLabelsOptions{}
 			opts.ListOptions.PerPage = 100
-			labels, _, err := p.client.Labels.ListLabels(projPath, opts)
+			labels, _, err := p.client.Labels.ListLabels(projPath, opts, gitlab.WithContext(ctx))
 			if err == nil {
 				for _, label := range labels {
 					if label.Name != "" {
@@ -358,6 +408,14 @@ func (p *Provider) FilterValues(ctx context.Context, filterName string) ([]provi
 	}
 }
 
+// Health checks that the GitLab API is reachable and the token is still valid.
+func (p *Provider) Health(ctx context.Context) error {
+	if _, _, err := p.client.Users.CurrentUser(gitlab.WithContext(ctx)); err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
+
 // Shutdown gracefully shuts down the provider.
 func (p *Provider) Shutdown(ctx context.Context) error {
 	return nil
@@ -487,16 +545,19 @@ func (p *Provider) shouldSearchIssues(projectID string) bool {
 }
 
 // parseResourceID parses a resource ID into project ID and optional issue IID.
+// Issue IDs are the project path followed by "/" and the IID; project paths
+// contain slashes themselves, so the IID is the numeric last segment.
 // Returns (projectID, issueIID, hasIssue).
 func parseResourceID(resourceID string) (string, int, bool) {
-	// Try to parse as "projectID/issueIID"
-	var projID string
-	var issueIID int
-	n, err := fmt.Sscanf(resourceID, "%s/%d", &projID, &issueIID)
-	if err == nil && n == 2 {
-		return projID, issueIID, true
-	}
-	return resourceID, 0, false
+	i := strings.LastIndex(resourceID, "/")
+	if i <= 0 {
+		return resourceID, 0, false
+	}
+	issueIID, err := strconv.Atoi(resourceID[i+1:])
+	if err != nil {
+		return resourceID, 0, false
+	}
+	return resourceID[:i], issueIID, true
 }
 
 // formatProjectID formats a project ID for storage.
@@ -512,7 +573,7 @@ func (p *Provider) discoverWithLimit(ctx context.Context, limit int) ([]types.En
 	}
 	opts.ListOptions.PerPage = int64(limit)
 
-	projects, _, err := p.client.Projects.ListProjects(opts)
+	projects, _, err := p.client.Projects.ListProjects(opts, gitlab.WithContext(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("list projects failed: %w", err)
 	}
@@ -525,6 +586,50 @@ func (p *Provider) discoverWithLimit(ctx context.Context, limit int) ([]types.En
 	return entities, nil
 }
 
+// EntityTypes returns the GitLab project and issue types.
+func (p *Provider) EntityTypes() []string {
+	return []string{TypeProject, TypeIssue}
+}
+
+// TypeDefinitions returns the GitLab types and their attributes.
+func (p *Provider) TypeDefinitions() []types.TypeDefinition {
+	eq := types.FilterConfig{SupportsEq: true, SupportsNeq: true}
+	return []types.TypeDefinition{
+		{
+			Name:        TypeGitLab,
+			Parent:      types.TypeItem,
+			Description: "Base type for GitLab items",
+			Attributes: map[string]types.AttributeDef{
+				AttrWebURL:      {Name: AttrWebURL, Type: types.AttributeTypeString},
+				AttrProjectPath: {Name: AttrProjectPath, Type: types.AttributeTypeString, Filterable: true, Filter: eq},
+				AttrCreatedAt:   {Name: AttrCreatedAt, Type: types.AttributeTypeString},
+				AttrUpdatedAt:   {Name: AttrUpdatedAt, Type: types.AttributeTypeString},
+			},
+		},
+		{
+			Name:        TypeProject,
+			Parent:      TypeGitLab,
+			Description: "GitLab project",
+			Attributes: map[string]types.AttributeDef{
+				AttrVisibility: {Name: AttrVisibility, Type: types.AttributeTypeString, Filterable: true, Filter: eq},
+				AttrArchived:   {Name: AttrArchived, Type: types.AttributeTypeBool, Filterable: true, Filter: eq},
+			},
+		},
+		{
+			Name:        TypeIssue,
+			Parent:      TypeGitLab,
+			Description: "GitLab issue",
+			Attributes: map[string]types.AttributeDef{
+				AttrState:       {Name: AttrState, Type: types.AttributeTypeString, Filterable: true, Filter: eq},
+				AttrLabels:      {Name: AttrLabels, Type: types.AttributeTypeStringSlice, Filterable: true},
+				AttrAssignee:    {Name: AttrAssignee, Type: types.AttributeTypeString, Filterable: true, Filter: eq},
+				AttrAuthor:      {Name: AttrAuthor, Type: types.AttributeTypeString, Filterable: true, Filter: eq},
+				AttrProjectName: {Name: AttrProjectName, Type: types.AttributeTypeString},
+			},
+		},
+	}
+}
+
 // AttributeExtensions returns provider-specific attribute extensions.
 func (p *Provider) AttributeExtensions(ctx context.Context) map[string]types.AttributeDef {
 	extensions := map[string]types.AttributeDef{
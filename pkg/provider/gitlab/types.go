@@ -2,6 +2,7 @@ package gitlab
 
 // Entity types for GitLab provider.
 const (
+	TypeGitLab  = "code.gitlab"
 	TypeProject = "code.gitlab.project"
 	TypeIssue   = "code.gitlab.issue"
 )
@@ -0,0 +1,222 @@
+package httpjson
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourname/mifind/internal/provider"
+)
+
+// errNotFound is returned for 404 responses and get responses without an item.
+var errNotFound = errors.New("not found")
+
+// Client is an HTTP client for a JSON API described by a Config.
+type Client struct {
+	config     *Config
+	httpClient *http.Client
+}
+
+// NewClient creates a client for the API described by config.
+func NewClient(config *Config) *Client {
+	return &Client{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Health checks that the API answers the list (or search) endpoint.
+func (c *Client) Health(ctx context.Context) error {
+	ep := c.config.Endpoints.List
+	params := url.Values{}
+	if ep == nil {
+		ep = c.config.Endpoints.Search
+		params.Set(ep.QueryParam, "")
+	}
+	c.setPageSize(params, 1)
+
+	_, err := c.get(ctx, ep.Path, ep, params)
+	return err
+}
+
+// Fetch returns up to limit items (0 for no limit) of an endpoint, starting
+// at offset, following the configured pagination.
+func (c *Client) Fetch(ctx context.Context, ep *Endpoint, params url.Values, offset, limit int) ([]any, error) {
+	p := c.config.Pagination
+
+	// skip is the number of items still to drop from the start of the
+	// results; offset and page pagination skip most of them server-side.
+	skip := offset
+	pageSize := p.PageSize
+	startPage := 0
+	switch p.Style {
+	case PaginationOffset:
+		skip = 0
+		if limit > 0 && limit < pageSize {
+			pageSize = limit
+		}
+	case PaginationPage:
+		startPage = skip / pageSize
+		skip %= pageSize
+	}
+
+	var items []any
+	cursor := ""
+	for page := 0; page < p.MaxPages; page++ {
+		pageParams := cloneValues(params)
+		c.setPageSize(pageParams, pageSize)
+
+		switch p.Style {
+		case PaginationOffset:
+			pageParams.Set(p.OffsetParam, strconv.Itoa(offset+len(items)))
+		case PaginationPage:
+			pageParams.Set(p.PageParam, strconv.Itoa(*p.FirstPage+startPage+page))
+		case PaginationCursor:
+			if cursor != "" {
+				pageParams.Set(p.CursorParam, cursor)
+			}
+		}
+
+		doc, err := c.get(ctx, ep.Path, ep, pageParams)
+		if err != nil {
+			return nil, err
+		}
+		results := c.results(ep, doc)
+		received := len(results)
+
+		if skip > 0 {
+			n := min(skip, len(results))
+			results = results[n:]
+			skip -= n
+		}
+		items = append(items, results...)
+		if limit > 0 && len(items) >= limit {
+			return items[:limit], nil
+		}
+
+		switch p.Style {
+		case PaginationNone:
+			return items, nil
+		case PaginationCursor:
+			next, ok := p.nextCursor.First(doc)
+			if !ok || stringify(next) == "" {
+				return items, nil
+			}
+			cursor = stringify(next)
+		default:
+			if received < pageSize {
+				return items, nil
+			}
+		}
+	}
+	return items, nil
+}
+
+// Get fetches a single item by resource ID. It returns errNotFound if the
+// API answers 404 or the response contains no item.
+func (c *Client) Get(ctx context.Context, id string) (any, error) {
+	ep := c.config.Endpoints.Get
+	path := strings.ReplaceAll(ep.Path, "{id}", url.PathEscape(id))
+
+	doc, err := c.get(ctx, path, ep, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	item, ok := ep.results.First(doc)
+	if !ok {
+		return nil, errNotFound
+	}
+	return item, nil
+}
+
+// results extracts the result items from a response.
+func (c *Client) results(ep *Endpoint, doc any) []any {
+	values := ep.results.Select(doc)
+	if len(values) == 1 {
+		if list, ok := values[0].([]any); ok {
+			return list
+		}
+	}
+	return values
+}
+
+// setPageSize sets the page size param, if pagination declares one.
+func (c *Client) setPageSize(params url.Values, size int) {
+	if c.config.Pagination.Style != PaginationNone && c.config.Pagination.LimitParam != "" {
+		params.Set(c.config.Pagination.LimitParam, strconv.Itoa(size))
+	}
+}
+
+// get performs a GET request and decodes the JSON response. Status codes are
+// mapped to provider errors, so that the retry middleware retries server
+// errors and rate limiting but not client errors.
+func (c *Client) get(ctx context.Context, path string, ep *Endpoint, params url.Values) (any, error) {
+	for key, value := range ep.Params {
+		params.Set(key, value)
+	}
+
+	u := c.config.URL + path
+	if encoded := params.Encode(); encoded != "" {
+		if strings.Contains(u, "?") {
+			u += "&" + encoded
+		} else {
+			u += "?" + encoded
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, provider.NewProviderError(provider.ErrorTypeConfig, "invalid request URL", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.config.AuthHeader != "" {
+		req.Header.Set(c.config.AuthHeader, c.config.AuthValue)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, provider.NewProviderError(provider.ErrorTypeTemporary, "request failed", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return nil, fmt.Errorf("%w: %w", errNotFound, err)
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			return nil, provider.NewProviderError(provider.ErrorTypeAuth, "authentication failed", err)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			return nil, provider.NewProviderError(provider.ErrorTypeRateLimit, "rate limited", err)
+		case resp.StatusCode >= 500:
+			return nil, provider.NewProviderError(provider.ErrorTypeTemporary, "server error", err)
+		default:
+			return nil, provider.NewProviderError(provider.ErrorTypeUnknown, "request rejected", err)
+		}
+	}
+
+	var doc any
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return doc, nil
+}
+
+// cloneValues returns a copy of params.
+func cloneValues(params url.Values) url.Values {
+	clone := make(url.Values, len(params))
+	for key, values := range params {
+		clone[key] = append([]string(nil), values...)
+	}
+	return clone
+}
@@ -0,0 +1,365 @@
+package httpjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yourname/mifind/internal/types"
+)
+
+// Pagination styles.
+const (
+	PaginationNone   = "none"
+	PaginationOffset = "offset"
+	PaginationPage   = "page"
+	PaginationCursor = "cursor"
+)
+
+// Filter operations a query param can accept.
+const (
+	OpEq       = "eq"
+	OpNeq      = "neq"
+	OpRange    = "range"
+	OpContains = "contains"
+	OpGlob     = "glob"
+)
+
+// Endpoint describes one HTTP endpoint of the API.
+type Endpoint struct {
+	// Path is appended to the base URL. For the get endpoint, "{id}" is
+	// replaced with the (escaped) resource ID.
+	Path string `json:"path"`
+
+	// QueryParam is the query param that carries the search text (search only).
+	QueryParam string `json:"query_param"`
+
+	// Params are static query params sent with every request.
+	Params map[string]string `json:"params"`
+
+	// Results is the path to the result items in the response ("$" by
+	// default). For the get endpoint it points at the single item.
+	Results string `json:"results"`
+
+	results Path
+}
+
+// Endpoints are the endpoints the provider calls.
+type Endpoints struct {
+	// Search is used by Search. Without it, Search returns no results.
+	Search *Endpoint `json:"search"`
+
+	// List is used by Discover, and by Hydrate if there is no get endpoint.
+	List *Endpoint `json:"list"`
+
+	// Get fetches a single item by ID.
+	Get *Endpoint `json:"get"`
+}
+
+// Pagination describes how list and search results are paged.
+type Pagination struct {
+	// Style is none, offset, page or cursor.
+	Style string `json:"style"`
+
+	// PageSize is the number of items requested per page.
+	PageSize int `json:"page_size"`
+
+	// MaxPages bounds the number of pages fetched per call.
+	MaxPages int `json:"max_pages"`
+
+	// LimitParam carries the page size (offset, page and cursor styles).
+	LimitParam string `json:"limit_param"`
+
+	// OffsetParam carries the offset of the first item (offset style).
+	OffsetParam string `json:"offset_param"`
+
+	// PageParam carries the page number (page style).
+	PageParam string `json:"page_param"`
+
+	// FirstPage is the number of the first page (page style, 1 by default).
+	FirstPage *int `json:"first_page"`
+
+	// CursorParam carries the cursor of the next page (cursor style).
+	CursorParam string `json:"cursor_param"`
+
+	// NextCursor is the path to the next cursor in the response (cursor style).
+	// Pagination stops when it is missing or empty.
+	NextCursor string `json:"next_cursor"`
+
+	nextCursor Path
+}
+
+// Relationship maps a response field to relationships of one type. The field
+// holds the resource ID of the target (or an array of them), which must be an
+// item of the same API.
+type Relationship struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+
+	path Path
+}
+
+// Mapping maps fields of a result item to entity fields.
+type Mapping struct {
+	// ID is the path to the item's ID. Required.
+	ID string `json:"id"`
+
+	// Title is the path to the item's title. Required.
+	Title string `json:"title"`
+
+	// Description is the path to the item's description.
+	Description string `json:"description"`
+
+	// Type is the path to the item's kind. Values are looked up in Types; if
+	// the path is unset or the kind is unknown, the provider's entity_type is
+	// used.
+	Type string `json:"type"`
+
+	// Types maps item kinds to entity types.
+	Types map[string]string `json:"types"`
+
+	// Attributes maps attribute names to paths.
+	Attributes map[string]string `json:"attributes"`
+
+	// Relationships maps fields to relationships.
+	Relationships []Relationship `json:"relationships"`
+
+	id, title, description, kind Path
+	attributes                   map[string]Path
+}
+
+// Filter declares which query param accepts a filter on an attribute.
+type Filter struct {
+	// Attribute is the filtered attribute.
+	Attribute string `json:"attribute"`
+
+	// Type is the attribute type (string by default).
+	Type types.AttributeType `json:"type"`
+
+	// Ops are the supported operations (eq by default).
+	Ops []string `json:"ops"`
+
+	// Param carries eq, neq, contains and glob filters. Slice values are
+	// sent as repeated params.
+	Param string `json:"param"`
+
+	// MinParam and MaxParam carry the bounds of range filters.
+	MinParam string `json:"min_param"`
+	MaxParam string `json:"max_param"`
+
+	// Description is shown in the UI.
+	Description string `json:"description"`
+}
+
+// supports reports whether the filter accepts the given operation.
+func (f Filter) supports(op string) bool {
+	for _, o := range f.Ops {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Config is the parsed configuration of an http-json instance.
+type Config struct {
+	URL        string
+	AuthHeader string
+	AuthValue  string
+	EntityType string
+	Endpoints  Endpoints
+	Pagination Pagination
+	Mapping    Mapping
+	Filters    []Filter
+}
+
+// parseConfig reads and validates the structured settings from an instance
+// config. Nested settings are decoded strictly, so misspelled keys are
+// reported instead of silently ignored.
+func parseConfig(config map[string]any) (*Config, error) {
+	c := &Config{}
+	c.URL, _ = config["url"].(string)
+	c.AuthHeader, _ = config["auth_header"].(string)
+	c.AuthValue, _ = config["auth_value"].(string)
+	c.EntityType, _ = config["entity_type"].(string)
+
+	if c.URL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	c.URL = strings.TrimSuffix(c.URL, "/")
+	if c.EntityType == "" {
+		c.EntityType = DefaultEntityType
+	}
+
+	for key, target := range map[string]any{
+		"endpoints":  &c.Endpoints,
+		"pagination": &c.Pagination,
+		"mapping":    &c.Mapping,
+		"filters":    &c.Filters,
+	} {
+		if err := decode(config[key], target); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	if err := c.compile(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// decode converts a config value (as read from YAML or JSON) into target.
+func decode(value any, target any) error {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(target)
+}
+
+// compile validates the config, applies defaults and compiles the paths.
+func (c *Config) compile() error {
+	var err error
+
+	if c.Endpoints.Search == nil && c.Endpoints.List == nil {
+		return fmt.Errorf("endpoints: a search or list endpoint is required")
+	}
+	for name, ep := range map[string]*Endpoint{
+		"search": c.Endpoints.Search,
+		"list":   c.Endpoints.List,
+		"get":    c.Endpoints.Get,
+	} {
+		if ep == nil {
+			continue
+		}
+		if ep.Results == "" {
+			ep.Results = "$"
+		}
+		if ep.results, err = ParsePath(ep.Results); err != nil {
+			return fmt.Errorf("endpoints.%s.results: %w", name, err)
+		}
+	}
+	if c.Endpoints.Search != nil && c.Endpoints.Search.QueryParam == "" {
+		return fmt.Errorf("endpoints.search.query_param is required")
+	}
+	if c.Endpoints.Get != nil && !strings.Contains(c.Endpoints.Get.Path, "{id}") {
+		return fmt.Errorf("endpoints.get.path must contain {id}")
+	}
+
+	p := &c.Pagination
+	if p.Style == "" {
+		p.Style = PaginationNone
+	}
+	if p.PageSize <= 0 {
+		p.PageSize = DefaultPageSize
+	}
+	if p.MaxPages <= 0 {
+		p.MaxPages = DefaultMaxPages
+	}
+	switch p.Style {
+	case PaginationNone:
+	case PaginationOffset:
+		if p.OffsetParam == "" {
+			return fmt.Errorf("pagination.offset_param is required for offset pagination")
+		}
+	case PaginationPage:
+		if p.PageParam == "" {
+			return fmt.Errorf("pagination.page_param is required for page pagination")
+		}
+		if p.FirstPage == nil {
+			first := 1
+			p.FirstPage = &first
+		}
+	case PaginationCursor:
+		if p.CursorParam == "" || p.NextCursor == "" {
+			return fmt.Errorf("pagination.cursor_param and pagination.next_cursor are required for cursor pagination")
+		}
+		if p.nextCursor, err = ParsePath(p.NextCursor); err != nil {
+			return fmt.Errorf("pagination.next_cursor: %w", err)
+		}
+	default:
+		return fmt.Errorf("pagination.style must be none, offset, page or cursor, got %q", p.Style)
+	}
+
+	m := &c.Mapping
+	if m.ID == "" || m.Title == "" {
+		return fmt.Errorf("mapping.id and mapping.title are required")
+	}
+	for _, field := range []struct {
+		name string
+		expr string
+		path *Path
+	}{
+		{"id", m.ID, &m.id},
+		{"title", m.Title, &m.title},
+		{"description", m.Description, &m.description},
+		{"type", m.Type, &m.kind},
+	} {
+		if field.expr == "" {
+			continue
+		}
+		if *field.path, err = ParsePath(field.expr); err != nil {
+			return fmt.Errorf("mapping.%s: %w", field.name, err)
+		}
+	}
+	m.attributes = make(map[string]Path, len(m.Attributes))
+	for name, expr := range m.Attributes {
+		if m.attributes[name], err = ParsePath(expr); err != nil {
+			return fmt.Errorf("mapping.attributes.%s: %w", name, err)
+		}
+	}
+	for i := range m.Relationships {
+		rel := &m.Relationships[i]
+		if rel.Type == "" || rel.Path == "" {
+			return fmt.Errorf("mapping.relationships[%d]: type and path are required", i)
+		}
+		if rel.path, err = ParsePath(rel.Path); err != nil {
+			return fmt.Errorf("mapping.relationships[%d]: %w", i, err)
+		}
+	}
+
+	for i := range c.Filters {
+		f := &c.Filters[i]
+		if f.Attribute == "" {
+			return fmt.Errorf("filters[%d]: attribute is required", i)
+		}
+		if f.Type == "" {
+			f.Type = types.AttributeTypeString
+		}
+		if len(f.Ops) == 0 {
+			f.Ops = []string{OpEq}
+		}
+		for _, op := range f.Ops {
+			switch op {
+			case OpEq, OpNeq, OpContains, OpGlob:
+				if f.Param == "" {
+					return fmt.Errorf("filters[%d] (%s): param is required for %s", i, f.Attribute, op)
+				}
+			case OpRange:
+				if f.MinParam == "" && f.MaxParam == "" {
+					return fmt.Errorf("filters[%d] (%s): min_param or max_param is required for range", i, f.Attribute)
+				}
+			default:
+				return fmt.Errorf("filters[%d] (%s): unknown op %q", i, f.Attribute, op)
+			}
+		}
+	}
+
+	return nil
+}
+
+// filter returns the filter declared for an attribute.
+func (c *Config) filter(attribute string) (Filter, bool) {
+	for _, f := range c.Filters {
+		if f.Attribute == attribute {
+			return f, true
+		}
+	}
+	return Filter{}, false
+}
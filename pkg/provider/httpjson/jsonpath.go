@@ -0,0 +1,170 @@
+package httpjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathStep is one step of a compiled path: a field name, an array index, or
+// a wildcard over all array elements.
+type pathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// Path is a compiled JSONPath-style expression. The supported subset is
+// "$" (the root), ".field" and "['field']" for object fields, "[n]" for
+// array elements (negative n counts from the end) and "[*]" or ".*" for all
+// elements of an array or all values of an object. The leading "$" is optional.
+type Path struct {
+	expr  string
+	steps []pathStep
+}
+
+// ParsePath compiles a path expression.
+func ParsePath(expr string) (Path, error) {
+	path := Path{expr: expr}
+	s := strings.TrimSpace(expr)
+	s = strings.TrimPrefix(s, "$")
+
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '.':
+			i++
+			end := i
+			for end < len(s) && s[end] != '.' && s[end] != '[' {
+				end++
+			}
+			name := s[i:end]
+			if name == "" {
+				return Path{}, fmt.Errorf("invalid path %q: empty field name", expr)
+			}
+			if name == "*" {
+				path.steps = append(path.steps, pathStep{wildcard: true})
+			} else {
+				path.steps = append(path.steps, pathStep{field: name})
+			}
+			i = end
+
+		case '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return Path{}, fmt.Errorf("invalid path %q: unterminated [", expr)
+			}
+			inner := strings.TrimSpace(s[i+1 : i+end])
+			i += end + 1
+
+			switch {
+			case inner == "*":
+				path.steps = append(path.steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				path.steps = append(path.steps, pathStep{field: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return Path{}, fmt.Errorf("invalid path %q: bad index %q", expr, inner)
+				}
+				path.steps = append(path.steps, pathStep{index: n, isIndex: true})
+			}
+
+		default:
+			// Allow "field.sub" without the leading "$."
+			if i == 0 {
+				s = "." + s
+				continue
+			}
+			return Path{}, fmt.Errorf("invalid path %q: unexpected %q", expr, s[i])
+		}
+	}
+
+	return path, nil
+}
+
+// String returns the original expression.
+func (p Path) String() string {
+	return p.expr
+}
+
+// IsZero reports whether the path was never set.
+func (p Path) IsZero() bool {
+	return p.expr == ""
+}
+
+// IsMulti reports whether the path contains a wildcard, so that it yields a
+// list of values even if it matches only one.
+func (p Path) IsMulti() bool {
+	for _, step := range p.steps {
+		if step.wildcard {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns every value the path matches in doc, which is a value
+// decoded by encoding/json. Missing fields and out-of-range indexes match
+// nothing.
+func (p Path) Select(doc any) []any {
+	current := []any{doc}
+	for _, step := range p.steps {
+		var next []any
+		for _, value := range current {
+			switch v := value.(type) {
+			case map[string]any:
+				if step.wildcard {
+					for _, child := range v {
+						next = append(next, child)
+					}
+				} else if child, ok := v[step.field]; ok && !step.isIndex {
+					next = append(next, child)
+				}
+			case []any:
+				switch {
+				case step.wildcard:
+					next = append(next, v...)
+				case step.isIndex:
+					idx := step.index
+					if idx < 0 {
+						idx += len(v)
+					}
+					if idx >= 0 && idx < len(v) {
+						next = append(next, v[idx])
+					}
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// First returns the first value the path matches in doc, if any. A path
+// ending at an array yields the array itself.
+func (p Path) First(doc any) (any, bool) {
+	values := p.Select(doc)
+	if len(values) == 0 || values[0] == nil {
+		return nil, false
+	}
+	return values[0], true
+}
+
+// stringify renders a JSON value for use in an ID or query param. Numbers
+// without a fractional part are rendered as integers.
+func stringify(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		if v == float64(int64(v)) {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,399 @@
+// Package httpjson implements a generic provider for simple REST/JSON APIs.
+// Endpoints, pagination, authentication, the mapping from response fields to
+// entity fields and the supported filters are all declared in the instance
+// config, so a self-hosted tool with a JSON API can be searched without
+// writing a provider for it.
+package httpjson
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/types"
+)
+
+const (
+	// DefaultEntityType is the entity type of items whose type is not mapped.
+	DefaultEntityType = "item"
+
+	// DefaultPageSize is the page size used when pagination doesn't set one.
+	DefaultPageSize = 100
+
+	// DefaultMaxPages bounds the pages fetched per call when pagination doesn't set it.
+	DefaultMaxPages = 10
+)
+
+// Provider implements the provider interface for a JSON API described by its config.
+type Provider struct {
+	provider.BaseProvider
+	config *Config
+	client *Client
+}
+
+// NewProvider creates a new http-json provider.
+func NewProvider() *Provider {
+	return &Provider{
+		BaseProvider: *provider.NewBaseProvider(provider.ProviderMetadata{
+			Name:        "http-json",
+			Description: "Generic REST/JSON API",
+			ConfigSchema: provider.AddStandardConfigFields(map[string]provider.ConfigField{
+				"url": {
+					Type:        "string",
+					Required:    true,
+					Description: "Base URL of the API (e.g., https://links.example.com/api)",
+				},
+				"auth_header": {
+					Type:        "string",
+					Description: "Header sent with every request for authentication (e.g., Authorization)",
+				},
+				"auth_value": {
+					Type:        "string",
+					Description: "Value of the auth header (e.g., Bearer <token>)",
+					Secret:      true,
+				},
+				"entity_type": {
+					Type:        "string",
+					Default:     DefaultEntityType,
+					Description: "Entity type of items whose type is not mapped",
+				},
+				"endpoints": {
+					Type:        "map",
+					Required:    true,
+					Description: "search, list and get endpoints: path, query_param, params, results",
+				},
+				"pagination": {
+					Type:        "map",
+					Description: "Pagination style (none, offset, page, cursor) and its params",
+				},
+				"mapping": {
+					Type:        "map",
+					Required:    true,
+					Description: "Paths of id, title, description, type, attributes and relationships in result items",
+				},
+				"filters": {
+					Type:        "list",
+					Description: "Query params that accept filters on attributes",
+				},
+			}),
+		}),
+	}
+}
+
+// Name returns the provider name.
+func (p *Provider) Name() string {
+	return "http-json"
+}
+
+// Initialize parses the API description and checks that the API is reachable.
+func (p *Provider) Initialize(ctx context.Context, config map[string]any) error {
+	instanceID, ok := config["instance_id"].(string)
+	if !ok || instanceID == "" {
+		return provider.NewProviderError(provider.ErrorTypeConfig, "instance_id is required", nil)
+	}
+	p.SetInstanceID(instanceID)
+
+	parsed, err := parseConfig(config)
+	if err != nil {
+		return provider.NewProviderError(provider.ErrorTypeConfig, err.Error(), nil)
+	}
+	p.config = parsed
+	p.client = NewClient(parsed)
+
+	if err := p.client.Health(ctx); err != nil {
+		return provider.NewProviderError(provider.ErrorTypeAuth, "failed to connect to API", err)
+	}
+
+	return nil
+}
+
+// Discover returns all items of the list endpoint (up to the page limit).
+// Without a list endpoint, nothing is discovered and search is live only.
+func (p *Provider) Discover(ctx context.Context) ([]types.Entity, error) {
+	ep := p.config.Endpoints.List
+	if ep == nil {
+		return nil, nil
+	}
+
+	items, err := p.client.Fetch(ctx, ep, url.Values{}, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return p.toEntities(items), nil
+}
+
+// Hydrate retrieves an item through the get endpoint, or by scanning the list
+// endpoint if the API has none.
+func (p *Provider) Hydrate(ctx context.Context, id string) (types.Entity, error) {
+	entityID, err := provider.ParseEntityID(id)
+	if err != nil {
+		return types.Entity{}, provider.ErrNotFound
+	}
+	resourceID := entityID.ResourceID()
+
+	if p.config.Endpoints.Get != nil {
+		item, err := p.client.Get(ctx, resourceID)
+		if errors.Is(err, errNotFound) {
+			return types.Entity{}, provider.ErrNotFound
+		}
+		if err != nil {
+			return types.Entity{}, err
+		}
+		entity, ok := p.itemToEntity(item)
+		if !ok {
+			return types.Entity{}, provider.ErrNotFound
+		}
+		return entity, nil
+	}
+
+	if p.config.Endpoints.List != nil {
+		items, err := p.client.Fetch(ctx, p.config.Endpoints.List, url.Values{}, 0, 0)
+		if err != nil {
+			return types.Entity{}, err
+		}
+		for _, item := range items {
+			if entity, ok := p.itemToEntity(item); ok && entity.ID == id {
+				return entity, nil
+			}
+		}
+	}
+
+	return types.Entity{}, provider.ErrNotFound
+}
+
+// GetRelated hydrates the targets of an entity's relationships of the given type.
+func (p *Provider) GetRelated(ctx context.Context, id string, relType string) ([]types.Entity, error) {
+	entity, err := p.Hydrate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var related []types.Entity
+	for _, rel := range entity.Relationships {
+		if rel.Type != relType {
+			continue
+		}
+		target, err := p.Hydrate(ctx, rel.TargetID)
+		if err != nil {
+			continue
+		}
+		related = append(related, target)
+	}
+	return related, nil
+}
+
+// Search queries the search endpoint, passing supported filters as query params.
+func (p *Provider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	ep := p.config.Endpoints.Search
+	if ep == nil {
+		return nil, nil
+	}
+
+	params := url.Values{}
+	params.Set(ep.QueryParam, query.Query)
+	for name, value := range query.Filters {
+		filter, ok := p.config.filter(name)
+		if !ok {
+			continue
+		}
+		setFilterParams(params, filter, value)
+	}
+
+	items, err := p.client.Fetch(ctx, ep, params, query.Offset, query.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entities := p.toEntities(items)
+	if query.Type == "" {
+		return entities, nil
+	}
+	filtered := entities[:0]
+	for _, entity := range entities {
+		if entity.Type == query.Type {
+			filtered = append(filtered, entity)
+		}
+	}
+	return filtered, nil
+}
+
+// setFilterParams adds the query params for a filter value. Range values
+// ({"min", "max"}) go to the min and max params, slices are sent as repeated
+// params and other values as a single param.
+func setFilterParams(params url.Values, filter Filter, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if !filter.supports(OpRange) {
+			return
+		}
+		if min, ok := v["min"]; ok && filter.MinParam != "" {
+			params.Set(filter.MinParam, stringify(min))
+		}
+		if max, ok := v["max"]; ok && filter.MaxParam != "" {
+			params.Set(filter.MaxParam, stringify(max))
+		}
+	case []string:
+		if filter.Param == "" {
+			return
+		}
+		for _, item := range v {
+			params.Add(filter.Param, item)
+		}
+	case []any:
+		if filter.Param == "" {
+			return
+		}
+		for _, item := range v {
+			params.Add(filter.Param, stringify(item))
+		}
+	default:
+		if filter.Param == "" {
+			return
+		}
+		params.Set(filter.Param, stringify(v))
+	}
+}
+
+// FilterCapabilities returns the filters declared in the config.
+func (p *Provider) FilterCapabilities(ctx context.Context) (map[string]provider.FilterCapability, error) {
+	caps := make(map[string]provider.FilterCapability, len(p.config.Filters))
+	for _, f := range p.config.Filters {
+		caps[f.Attribute] = provider.FilterCapability{
+			Type:             f.Type,
+			SupportsEq:       f.supports(OpEq),
+			SupportsNeq:      f.supports(OpNeq),
+			SupportsRange:    f.supports(OpRange),
+			SupportsGlob:     f.supports(OpGlob),
+			SupportsContains: f.supports(OpContains),
+			Description:      f.Description,
+		}
+	}
+	return caps, nil
+}
+
+// EntityTypes returns the configured entity type and the types item kinds
+// are mapped to.
+func (p *Provider) EntityTypes() []string {
+	if p.config == nil {
+		return nil
+	}
+	entityTypes := []string{p.config.EntityType}
+	for _, mapped := range p.config.Mapping.Types {
+		if !slices.Contains(entityTypes, mapped) {
+			entityTypes = append(entityTypes, mapped)
+		}
+	}
+	return entityTypes
+}
+
+// Health checks that the API is reachable.
+func (p *Provider) Health(ctx context.Context) error {
+	return p.client.Health(ctx)
+}
+
+// Shutdown gracefully shuts down the provider.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// toEntities converts result items to entities, skipping items without an ID.
+func (p *Provider) toEntities(items []any) []types.Entity {
+	entities := make([]types.Entity, 0, len(items))
+	for _, item := range items {
+		if entity, ok := p.itemToEntity(item); ok {
+			entities = append(entities, entity)
+		}
+	}
+	return entities
+}
+
+// itemToEntity converts a result item to an entity using the configured
+// mapping. It returns false if the item has no ID.
+func (p *Provider) itemToEntity(item any) (types.Entity, bool) {
+	m := p.config.Mapping
+
+	rawID, ok := m.id.First(item)
+	if !ok || stringify(rawID) == "" {
+		return types.Entity{}, false
+	}
+
+	entityType := p.config.EntityType
+	if !m.kind.IsZero() {
+		if kind, ok := m.kind.First(item); ok {
+			if mapped, ok := m.Types[stringify(kind)]; ok {
+				entityType = mapped
+			}
+		}
+	}
+
+	title := ""
+	if value, ok := m.title.First(item); ok {
+		title = stringify(value)
+	}
+
+	entity := types.NewEntity(p.BuildEntityID(stringify(rawID)).String(), entityType, p.Name(), title)
+
+	if !m.description.IsZero() {
+		if value, ok := m.description.First(item); ok {
+			entity.Description = stringify(value)
+		}
+	}
+
+	for name, path := range m.attributes {
+		if value, ok := attributeValue(path.Select(item), path.IsMulti()); ok {
+			entity.AddAttribute(name, value)
+		}
+	}
+
+	for _, rel := range m.Relationships {
+		for _, target := range flatten(rel.path.Select(item)) {
+			if targetID := stringify(target); targetID != "" {
+				entity.AddRelationship(rel.Type, p.BuildEntityID(targetID).String())
+			}
+		}
+	}
+
+	entity.AddSearchToken(title)
+	if entity.Description != "" {
+		entity.AddSearchToken(entity.Description)
+	}
+
+	return entity, true
+}
+
+// attributeValue turns the values matched by an attribute path into an
+// attribute value. Arrays and the matches of wildcard paths are lists; lists
+// of strings become []string.
+func attributeValue(values []any, multi bool) (any, bool) {
+	if len(values) == 1 && !multi {
+		if _, isList := values[0].([]any); !isList {
+			return values[0], values[0] != nil
+		}
+	}
+	values = flatten(values)
+	if len(values) == 0 {
+		return nil, false
+	}
+
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		s, ok := value.(string)
+		if !ok {
+			return values, true
+		}
+		strs = append(strs, s)
+	}
+	return strs, true
+}
+
+// flatten expands a single matched array into its elements.
+func flatten(values []any) []any {
+	if len(values) == 1 {
+		if list, ok := values[0].([]any); ok {
+			return list
+		}
+	}
+	return values
+}
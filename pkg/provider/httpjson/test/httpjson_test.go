@@ -0,0 +1,160 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/pkg/provider/httpjson"
+)
+
+var bookmarks = []map[string]any{
+	{"id": 1, "title": "Go spec", "kind": "link", "tags": []any{map[string]any{"name": "go"}}, "collection": 3},
+	{"id": 2, "title": "Meilisearch docs", "kind": "link", "tags": []any{}, "collection": 3},
+	{"id": 3, "title": "Reading list", "kind": "collection", "notes": "Things to read"},
+}
+
+// newBookmarkServer serves bookmarks with cursor pagination (one item per
+// page) behind a token.
+func newBookmarkServer(t *testing.T, searches *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/bookmarks":
+			start := 0
+			if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+				start = int(cursor[0] - '0')
+			}
+			resp := map[string]any{"data": bookmarks[start : start+1]}
+			if start+1 < len(bookmarks) {
+				resp["next"] = string(rune('0' + start + 1))
+			}
+			_ = json.NewEncoder(w).Encode(resp)
+
+		case r.URL.Path == "/search":
+			*searches = append(*searches, r.URL.RawQuery)
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": bookmarks[:1]})
+
+		case strings.HasPrefix(r.URL.Path, "/bookmarks/"):
+			id := strings.TrimPrefix(r.URL.Path, "/bookmarks/")
+			for _, b := range bookmarks {
+				if id == jsonNumber(b["id"]) {
+					_ = json.NewEncoder(w).Encode(map[string]any{"data": b})
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+
+		default:
+			t.Errorf("Unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// jsonNumber renders v as JSON, e.g. 1 for the float64 1.
+func jsonNumber(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+// TestProvider_ConfiguredAPI tests discovery through cursor pagination, the
+// field mapping, filter params and hydration of an API described by config.
+func TestProvider_ConfiguredAPI(t *testing.T) {
+	ctx := context.Background()
+	var searches []string
+	server := newBookmarkServer(t, &searches)
+	defer server.Close()
+
+	p := httpjson.NewProvider()
+	err := p.Initialize(ctx, map[string]any{
+		"instance_id": "links",
+		"url":         server.URL,
+		"auth_header": "X-Token",
+		"auth_value":  "secret",
+		"entity_type": "bookmark",
+		"endpoints": map[string]any{
+			"search": map[string]any{"path": "/search", "query_param": "q", "results": "$.data"},
+			"list":   map[string]any{"path": "/bookmarks", "results": "$.data"},
+			"get":    map[string]any{"path": "/bookmarks/{id}", "results": "$.data"},
+		},
+		"pagination": map[string]any{
+			"style":        "cursor",
+			"cursor_param": "cursor",
+			"next_cursor":  "$.next",
+		},
+		"mapping": map[string]any{
+			"id":          "$.id",
+			"title":       "$.title",
+			"description": "$.notes",
+			"type":        "$.kind",
+			"types":       map[string]any{"collection": "bookmark.collection"},
+			"attributes":  map[string]any{"tags": "$.tags[*].name"},
+			"relationships": []any{
+				map[string]any{"type": "collection", "path": "$.collection"},
+			},
+		},
+		"filters": []any{
+			map[string]any{"attribute": "tags", "type": "[]string", "param": "tag"},
+			map[string]any{"attribute": "created", "type": "time", "ops": []any{"range"}, "min_param": "after"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	entities, err := p.Discover(ctx)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if len(entities) != 3 {
+		t.Fatalf("Expected 3 entities across all pages, got %d", len(entities))
+	}
+	first := entities[0]
+	if first.ID != "http-json:links:1" || first.Title != "Go spec" || first.Type != "bookmark" {
+		t.Errorf("Unexpected mapping of first item: %s %q %s", first.ID, first.Title, first.Type)
+	}
+	if tags, _ := first.Attributes["tags"].([]string); len(tags) != 1 || tags[0] != "go" {
+		t.Errorf("Expected tags [go], got %#v", first.Attributes["tags"])
+	}
+	if len(first.Relationships) != 1 || first.Relationships[0].TargetID != "http-json:links:3" {
+		t.Errorf("Expected a collection relationship to item 3, got %+v", first.Relationships)
+	}
+	if entities[2].Type != "bookmark.collection" || entities[2].Description != "Things to read" {
+		t.Errorf("Unexpected mapping of collection: %s %q", entities[2].Type, entities[2].Description)
+	}
+
+	caps, _ := p.FilterCapabilities(ctx)
+	if !caps["tags"].SupportsEq || caps["tags"].SupportsRange || !caps["created"].SupportsRange {
+		t.Errorf("Unexpected filter capabilities: %+v", caps)
+	}
+
+	_, err = p.Search(ctx, provider.SearchQuery{
+		Query:   "go",
+		Filters: map[string]any{"tags": []string{"go", "lang"}, "created": map[string]any{"min": int64(1700000000)}},
+	})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(searches) != 1 || searches[0] != "after=1700000000&q=go&tag=go&tag=lang" {
+		t.Errorf("Unexpected search params: %v", searches)
+	}
+
+	related, err := p.GetRelated(ctx, first.ID, "collection")
+	if err != nil || len(related) != 1 || related[0].Title != "Reading list" {
+		t.Errorf("Expected the collection as related entity, got %+v (%v)", related, err)
+	}
+
+	if _, err := p.Hydrate(ctx, "http-json:links:99"); !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing item, got %v", err)
+	}
+}
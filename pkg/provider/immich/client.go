@@ -5,9 +5,11 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -52,37 +54,33 @@ func NewClientWithLogger(baseURL, apiKey string, insecureSkipVerify bool, logger
 // Search performs a search query against the Immich API.
 // Note: The Immich search API only returns assets and albums, not people.
 func (c *Client) Search(ctx context.Context, query string, limit int) (*SearchResponse, error) {
-	return c.SearchWithFilters(ctx, query, limit, nil, "", "", "", "")
+	return c.SearchWithFilters(ctx, query, limit, SearchFilters{})
 }
 
 // SearchWithFilters performs a search query with filters for people, locations, etc.
 // Uses smaller default limits (25 for text, 100 for filter-only) to avoid timeouts.
-func (c *Client) SearchWithFilters(ctx context.Context, query string, limit int, peopleIDs []string, country, state, city, albumID string) (*SearchResponse, error) {
+func (c *Client) SearchWithFilters(ctx context.Context, query string, limit int, filters SearchFilters) (*SearchResponse, error) {
 	// Determine default size based on search type
 	defaultSize := 100
 	if query != "" {
 		defaultSize = 25 // Text searches are slower
 	}
 
-	return c.doSearchRequest(ctx, query, limit, defaultSize, peopleIDs, country, state, city, albumID, true)
+	return c.doSearchRequest(ctx, query, limit, defaultSize, filters, true)
 }
 
 // doSearchRequest is the internal search implementation that all search methods use.
 // endpoint: "smart" for text search, "metadata" for filter-only
 // defaultSize: used when limit < 1
 // withExif: if true, only returns assets with EXIF data (used for filter values)
-func (c *Client) doSearchRequest(ctx context.Context, query string, limit, defaultSize int, peopleIDs []string, country, state, city, albumID string, withExif bool) (*SearchResponse, error) {
+func (c *Client) doSearchRequest(ctx context.Context, query string, limit, defaultSize int, filters SearchFilters, withExif bool) (*SearchResponse, error) {
 	if c.logger != nil {
 		c.logger.Debug().
 			Str("query", query).
 			Int("limit", limit).
 			Int("defaultSize", defaultSize).
 			Bool("withExif", withExif).
-			Strs("people", peopleIDs).
-			Str("country", country).
-			Str("state", state).
-			Str("city", city).
-			Str("album", albumID).
+			Interface("filters", filters).
 			Msg("Immich: Search request")
 	}
 
@@ -120,26 +118,7 @@ func (c *Client) doSearchRequest(ctx context.Context, query string, limit, defau
 		reqBody["withExif"] = true
 	}
 
-	// Add people filter if specified
-	if len(peopleIDs) > 0 {
-		reqBody["personIds"] = peopleIDs
-	}
-
-	// Add location filter if specified
-	if country != "" {
-		reqBody["country"] = country
-	}
-	if state != "" {
-		reqBody["state"] = state
-	}
-	if city != "" {
-		reqBody["city"] = city
-	}
-
-	// Add album filter if specified
-	if albumID != "" {
-		reqBody["albumId"] = albumID
-	}
+	filters.apply(reqBody)
 
 	if c.logger != nil {
 		c.logger.Debug().
@@ -430,7 +409,7 @@ func (c *Client) doRequest(req *http.Request, result any) error {
 				Str("body", string(body)).
 				Msg("Immich: HTTP error response")
 		}
-		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(body))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// Decode response
@@ -452,6 +431,36 @@ func (c *Client) doRequest(req *http.Request, result any) error {
 	return nil
 }
 
+// StatusError is returned for responses with a non-2xx status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is a response for a missing resource.
+// Immich answers 400 rather than 404 for IDs that don't exist or aren't
+// accessible with the API key, with a "Not found or no ... access" message.
+// Other 400 responses are malformed requests and aren't matched.
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	switch statusErr.StatusCode {
+	case http.StatusNotFound:
+		return true
+	case http.StatusBadRequest:
+		return strings.Contains(statusErr.Body, "Not found")
+	default:
+		return false
+	}
+}
+
 // SetTimeout sets the HTTP client timeout.
 func (c *Client) SetTimeout(timeout time.Duration) {
 	c.httpClient.Timeout = timeout
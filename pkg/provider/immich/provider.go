@@ -39,6 +39,7 @@ func NewProvider() *Provider {
 					Type:        "string",
 					Required:    true,
 					Description: "Immich API key",
+					Secret:      true,
 				},
 				"insecure_skip_verify": {
 					Type:        "bool",
@@ -163,24 +164,33 @@ func (p *Provider) Hydrate(ctx context.Context, id string) (types.Entity, error)
 		return types.Entity{}, provider.ErrNotFound
 	}
 
-	// Extract the resource type from the entity type
-	// For now, assume all IDs are assets
+	// Entity IDs don't record the resource kind, so try assets, albums and
+	// people in turn. Only a not-found answer moves on to the next kind.
 	asset, err := p.client.GetAsset(ctx, entityID.ResourceID())
 	if err == nil {
 		return p.assetToEntity(*asset), nil
 	}
+	if !IsNotFound(err) {
+		return types.Entity{}, err
+	}
 
 	// Try as album
 	album, err := p.client.GetAlbum(ctx, entityID.ResourceID())
 	if err == nil {
 		return p.albumToEntity(*album), nil
 	}
+	if !IsNotFound(err) {
+		return types.Entity{}, err
+	}
 
 	// Try as person
 	person, err := p.client.GetPerson(ctx, entityID.ResourceID())
 	if err == nil {
 		return p.personToEntity(*person), nil
 	}
+	if !IsNotFound(err) {
+		return types.Entity{}, err
+	}
 
 	return types.Entity{}, provider.ErrNotFound
 }
@@ -225,74 +235,33 @@ func (p *Provider) Search(ctx context.Context, query provider.SearchQuery) ([]ty
 		Interface("filters", query.Filters).
 		Msg("Immich: Search request")
 
-	// Extract filters from query
-	var peopleIDs []string
-	var city, state, country string
-	var albumID string
-
-	// Handle person filter - can be a single value or array of values
-	if personFilter, ok := query.Filters["person"]; ok && personFilter != nil {
-		switch v := personFilter.(type) {
-		case string:
-			peopleIDs = []string{v}
-		case []string:
-			peopleIDs = v
-		case []any:
-			peopleIDs = make([]string, 0, len(v))
-			for _, item := range v {
-				if str, ok := item.(string); ok {
-					peopleIDs = append(peopleIDs, str)
-				}
-			}
-		}
-		p.logger.Debug().
-			Strs("people_ids", peopleIDs).
-			Msg("Immich: Filter by people")
-	}
-
-	// Handle location filters
-	if cityFilter, ok := query.Filters[types.AttrLocationCity]; ok && cityFilter != nil {
-		city = fmt.Sprint(cityFilter)
-		p.logger.Debug().
-			Str("city", city).
-			Msg("Immich: Filter by city")
-	}
-	if stateFilter, ok := query.Filters[types.AttrLocationState]; ok && stateFilter != nil {
-		state = fmt.Sprint(stateFilter)
-		p.logger.Debug().
-			Str("state", state).
-			Msg("Immich: Filter by state")
-	}
-	if countryFilter, ok := query.Filters[types.AttrLocationCountry]; ok && countryFilter != nil {
-		country = fmt.Sprint(countryFilter)
-		p.logger.Debug().
-			Str("country", country).
-			Msg("Immich: Filter by country")
+	filters, ok := p.searchFilters(query)
+	if !ok {
+		// The type filter names no Immich asset type.
+		return []types.Entity{}, nil
 	}
 
-	// Handle album filter
-	if albumFilter, ok := query.Filters[types.AttrAlbum]; ok && albumFilter != nil {
-		albumID = fmt.Sprint(albumFilter)
-		p.logger.Debug().
-			Str("album_id", albumID).
-			Msg("Immich: Filter by album")
+	// Immich pages from the start, so fetch everything up to the end of the
+	// requested page and slice it below.
+	size := 0
+	if query.Limit > 0 {
+		size = query.Offset + query.Limit
 	}
 
-	searchResult, err := p.client.SearchWithFilters(ctx, query.Query, query.Limit, peopleIDs, country, state, city, albumID)
+	searchResult, err := p.client.SearchWithFilters(ctx, query.Query, size, filters)
 	if err != nil {
 		p.logger.Error().Err(err).Msg("Immich: Search request failed")
 		return nil, err
 	}
 
-	p.logger.Debug().
-		Int("assets", len(searchResult.Assets.Items)).
-		Int("albums", len(searchResult.Albums.Items)).
-		Msg("Immich: Search response")
-
 	var entities []types.Entity
 
 	// Add assets
 	if searchResult.Assets != nil {
+		p.logger.Debug().
+			Int("assets", len(searchResult.Assets.Items)).
+			Msg("Immich: Search response")
+
 		for _, asset := range searchResult.Assets.Items {
 			// Filter by type if specified
 			if query.Type != "" {
@@ -305,31 +274,126 @@ func (p *Provider) Search(ctx context.Context, query provider.SearchQuery) ([]ty
 		}
 	}
 
-	// Add albums
-	if searchResult.Albums != nil {
-		for _, album := range searchResult.Albums.Items {
-			entities = append(entities, p.albumToEntity(album))
+	// Filters only apply to assets, so albums and people are only
+	// returned by unfiltered searches.
+	if filters.IsEmpty() {
+		// Add albums
+		if searchResult.Albums != nil && (query.Type == "" || query.Type == types.TypeCollectionAlbum) {
+			for _, album := range searchResult.Albums.Items {
+				entities = append(entities, p.albumToEntity(album))
+			}
 		}
-	}
 
-	// Add people - fallback since Immich search API doesn't return people
-	// Fetch all people and filter locally by name (only if no person filter applied)
-	if len(peopleIDs) == 0 {
-		people, err := p.client.ListPeople(ctx, 0)
-		if err == nil && len(people) > 0 {
-			queryLower := strings.ToLower(query.Query)
-			for _, person := range people {
-				if person.Name != "" && strings.Contains(strings.ToLower(person.Name), queryLower) {
-					entities = append(entities, p.personToEntity(person))
-					if query.Limit > 0 && len(entities) >= query.Limit {
-						break
+		// Add people - fallback since Immich search API doesn't return people
+		// Fetch all people and filter locally by name
+		if query.Type == "" || query.Type == types.TypePerson {
+			people, err := p.client.ListPeople(ctx, 0)
+			if err == nil && len(people) > 0 {
+				queryLower := strings.ToLower(query.Query)
+				for _, person := range people {
+					if person.Name != "" && strings.Contains(strings.ToLower(person.Name), queryLower) {
+						entities = append(entities, p.personToEntity(person))
 					}
 				}
 			}
 		}
 	}
 
-	return entities, nil
+	return query.Paginate(entities), nil
+}
+
+// searchFilters translates the query's filters to Immich search filters. It
+// returns false if the type filter names no Immich asset type, so that
+// nothing can match.
+func (p *Provider) searchFilters(query provider.SearchQuery) (SearchFilters, bool) {
+	var filters SearchFilters
+
+	if personFilter, ok := query.Filters[types.AttrPerson]; ok && personFilter != nil {
+		filters.PersonIDs = provider.StringList(personFilter)
+	}
+	if albumFilter, ok := query.Filters[types.AttrAlbum]; ok && albumFilter != nil {
+		filters.AlbumIDs = provider.StringList(albumFilter)
+	}
+
+	// Handle location filters
+	if cityFilter, ok := query.Filters[types.AttrLocationCity]; ok && cityFilter != nil {
+		filters.City = fmt.Sprint(cityFilter)
+	}
+	if stateFilter, ok := query.Filters[types.AttrLocationState]; ok && stateFilter != nil {
+		filters.State = fmt.Sprint(stateFilter)
+	}
+	if countryFilter, ok := query.Filters[types.AttrLocationCountry]; ok && countryFilter != nil {
+		filters.Country = fmt.Sprint(countryFilter)
+	}
+
+	if favoriteFilter, ok := query.Filters[types.AttrIsFavorite]; ok {
+		filters.IsFavorite = boolFilter(favoriteFilter)
+	}
+	if archivedFilter, ok := query.Filters[types.AttrIsArchived]; ok {
+		filters.IsArchived = boolFilter(archivedFilter)
+	}
+	if createdFilter, ok := query.Filters[types.AttrCreated]; ok {
+		filters.TakenAfter, filters.TakenBefore = timeBounds(createdFilter)
+	}
+
+	entityType := query.Type
+	if typeFilter, ok := query.Filters[types.AttrType]; ok && typeFilter != nil {
+		entityType = fmt.Sprint(typeFilter)
+	}
+	if entityType != "" && entityType != types.TypeMediaAsset {
+		fileType, ok := MifindTypeToFileType(entityType)
+		if !ok && query.Filters[types.AttrType] != nil {
+			return SearchFilters{}, false
+		}
+		filters.Type = fileType
+	}
+
+	p.logger.Debug().
+		Interface("filters", filters).
+		Msg("Immich: Search filters")
+
+	return filters, true
+}
+
+// boolFilter converts a filter value to a bool. Options pass "true"/"false".
+func boolFilter(value any) *bool {
+	var b bool
+	switch v := value.(type) {
+	case bool:
+		b = v
+	case string:
+		b = v == "true"
+	default:
+		return nil
+	}
+	return &b
+}
+
+// timeBounds converts a created filter to the bounds of the taken date. An
+// exact timestamp bounds it on both sides.
+func timeBounds(value any) (after, before *time.Time) {
+	if r, ok := value.(map[string]any); ok {
+		return unixTime(r["min"]), unixTime(r["max"])
+	}
+	t := unixTime(value)
+	return t, t
+}
+
+// unixTime converts a Unix timestamp filter value to a time.
+func unixTime(value any) *time.Time {
+	var sec int64
+	switch v := value.(type) {
+	case int64:
+		sec = v
+	case int:
+		sec = int64(v)
+	case float64:
+		sec = int64(v)
+	default:
+		return nil
+	}
+	t := time.Unix(sec, 0).UTC()
+	return &t
 }
 
 // SupportsIncremental returns false - Immich provider doesn't support efficient incremental updates.
@@ -342,6 +406,22 @@ func (p *Provider) SupportsRelevanceScore() bool {
 	return true
 }
 
+// EntityTypes returns the types of Immich assets, albums and people.
+func (p *Provider) EntityTypes() []string {
+	return []string{
+		types.TypeMediaAsset,
+		types.TypeMediaAssetPhoto,
+		types.TypeMediaAssetVideo,
+		types.TypeCollectionAlbum,
+		types.TypePerson,
+	}
+}
+
+// Health checks that the Immich server is reachable.
+func (p *Provider) Health(ctx context.Context) error {
+	return p.client.Health(ctx)
+}
+
 // Shutdown shuts down the Immich provider.
 func (p *Provider) Shutdown(ctx context.Context) error {
 	// No cleanup needed
@@ -492,9 +572,8 @@ func (p *Provider) getLocationFilterOptions(ctx context.Context) ([]provider.Fil
 func (p *Provider) FilterCapabilities(ctx context.Context) (map[string]provider.FilterCapability, error) {
 	return map[string]provider.FilterCapability{
 		types.AttrType: {
-			Type:        types.AttributeTypeString,
-			SupportsEq:  true,
-			SupportsNeq: true,
+			Type:       types.AttributeTypeString,
+			SupportsEq: true,
 			Options: []provider.FilterOption{
 				{Value: types.TypeMediaAssetPhoto, Label: "Photo"},
 				{Value: types.TypeMediaAssetVideo, Label: "Video"},
@@ -525,58 +604,30 @@ func (p *Provider) FilterCapabilities(ctx context.Context) (map[string]provider.
 			SupportsRange: true,
 			Description:   "Creation timestamp (Unix)",
 		},
-		types.AttrWidth: {
-			Type:          types.AttributeTypeInt,
-			SupportsEq:    true,
-			SupportsRange: true,
-			Min:           ptrFloat64(1),
-			Description:   "Image/video width in pixels",
-		},
-		types.AttrHeight: {
-			Type:          types.AttributeTypeInt,
-			SupportsEq:    true,
-			SupportsRange: true,
-			Min:           ptrFloat64(1),
-			Description:   "Image/video height in pixels",
-		},
-		types.AttrCamera: {
-			Type:             types.AttributeTypeString,
-			SupportsEq:       true,
-			SupportsContains: true,
-			Description:      "Camera make/model",
-		},
-		types.AttrGPS: {
-			Type:          types.AttributeTypeGPS,
-			SupportsRange: true,
-			Description:   "GPS coordinates",
-		},
 		types.AttrLocationCity: {
-			Type:             types.AttributeTypeString,
-			SupportsEq:       true,
-			SupportsContains: true,
-			Description:      "City name",
+			Type:        types.AttributeTypeString,
+			SupportsEq:  true,
+			Description: "City name",
 		},
 		types.AttrLocationState: {
-			Type:             types.AttributeTypeString,
-			SupportsEq:       true,
-			SupportsContains: true,
-			Description:      "State name",
+			Type:        types.AttributeTypeString,
+			SupportsEq:  true,
+			Description: "State name",
 		},
 		types.AttrLocationCountry: {
-			Type:             types.AttributeTypeString,
-			SupportsEq:       true,
-			SupportsContains: true,
-			Description:      "Country name",
+			Type:        types.AttributeTypeString,
+			SupportsEq:  true,
+			Description: "Country name",
 		},
 		types.AttrAlbum: {
 			Type:        types.AttributeTypeString,
 			SupportsEq:  true,
-			Description: "Album name",
+			Description: "Album ID",
 		},
 		types.AttrPerson: {
 			Type:        types.AttributeTypeStringSlice,
 			SupportsEq:  true,
-			Description: "Person (detected faces)",
+			Description: "Person ID (detected faces)",
 		},
 	}, nil
 }
@@ -680,11 +731,6 @@ func (p *Provider) AttributeExtensions(ctx context.Context) map[string]types.Att
 	}
 }
 
-// ptrFloat64 returns a pointer to a float64.
-func ptrFloat64(v float64) *float64 {
-	return &v
-}
-
 // assetToEntity converts an Immich asset to an Entity.
 func (p *Provider) assetToEntity(asset Asset) types.Entity {
 	entityID := p.BuildEntityID(asset.ID).String()
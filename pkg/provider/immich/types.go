@@ -198,6 +198,62 @@ type PeopleSearchResponse struct {
 	Items []Person `json:"items"`
 }
 
+// SearchFilters are the asset filters of a search request.
+type SearchFilters struct {
+	PersonIDs   []string
+	AlbumIDs    []string
+	Country     string
+	State       string
+	City        string
+	Type        string // "IMAGE" or "VIDEO"
+	IsFavorite  *bool
+	IsArchived  *bool
+	TakenAfter  *time.Time
+	TakenBefore *time.Time
+}
+
+// IsEmpty reports whether no filter is set.
+func (f SearchFilters) IsEmpty() bool {
+	return len(f.PersonIDs) == 0 && len(f.AlbumIDs) == 0 &&
+		f.Country == "" && f.State == "" && f.City == "" && f.Type == "" &&
+		f.IsFavorite == nil && f.IsArchived == nil &&
+		f.TakenAfter == nil && f.TakenBefore == nil
+}
+
+// apply adds the filters to a search request body.
+func (f SearchFilters) apply(body map[string]any) {
+	if len(f.PersonIDs) > 0 {
+		body["personIds"] = f.PersonIDs
+	}
+	if len(f.AlbumIDs) > 0 {
+		body["albumIds"] = f.AlbumIDs
+	}
+	if f.Country != "" {
+		body["country"] = f.Country
+	}
+	if f.State != "" {
+		body["state"] = f.State
+	}
+	if f.City != "" {
+		body["city"] = f.City
+	}
+	if f.Type != "" {
+		body["type"] = f.Type
+	}
+	if f.IsFavorite != nil {
+		body["isFavorite"] = *f.IsFavorite
+	}
+	if f.IsArchived != nil {
+		body["isArchived"] = *f.IsArchived
+	}
+	if f.TakenAfter != nil {
+		body["takenAfter"] = f.TakenAfter.UTC().Format(time.RFC3339)
+	}
+	if f.TakenBefore != nil {
+		body["takenBefore"] = f.TakenBefore.UTC().Format(time.RFC3339)
+	}
+}
+
 // AssetBulkUploadCheckResponse represents the response for bulk upload check.
 type AssetBulkUploadCheckResponse struct {
 	Results []AssetBulkUploadCheckResult `json:"results"`
@@ -228,3 +284,16 @@ func FileTypeToMifindType(assetType string) string {
 		return types.TypeMediaAsset
 	}
 }
+
+// MifindTypeToFileType converts a mifind type to an Immich asset type. It
+// returns false for types that aren't assets of a single Immich type.
+func MifindTypeToFileType(mifindType string) (string, bool) {
+	switch mifindType {
+	case types.TypeMediaAssetPhoto:
+		return EntityTypePhoto, true
+	case types.TypeMediaAssetVideo:
+		return EntityTypeVideo, true
+	default:
+		return "", false
+	}
+}
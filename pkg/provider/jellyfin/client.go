@@ -3,6 +3,7 @@ package jellyfin
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,9 @@ import (
 	"time"
 )
 
+// errNotFound is returned for 404 responses.
+var errNotFound = errors.New("not found")
+
 // Client is an HTTP client for the Jellyfin API.
 type Client struct {
 	baseURL    string
@@ -92,6 +96,9 @@ func (c *Client) GetItems(ctx context.Context, params GetItemsParams) (*ItemsRes
 	if params.MinCommunityRating > 0 {
 		q.Add("minCommunityRating", fmt.Sprintf("%.1f", params.MinCommunityRating))
 	}
+	if len(params.OfficialRatings) > 0 {
+		q.Add("officialRatings", joinPipe(params.OfficialRatings))
+	}
 	if params.MaxOfficialRating != "" {
 		q.Add("maxOfficialRating", params.MaxOfficialRating)
 	}
@@ -231,6 +238,7 @@ type GetItemsParams struct {
 	Studios            []string
 	Years              []string
 	MinCommunityRating float64
+	OfficialRatings    []string
 	MaxOfficialRating  string
 	MinPremiereDate    string
 	MaxPremiereDate    string
@@ -278,6 +286,10 @@ func (c *Client) doRequest(req *http.Request, result any) error {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %s", errNotFound, string(body))
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("request failed: status %d: %s", resp.StatusCode, string(body))
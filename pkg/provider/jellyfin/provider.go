@@ -2,7 +2,10 @@ package jellyfin
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -12,6 +15,7 @@ import (
 
 const (
 	// Entity types
+	TypeJellyfin = "media.asset.jellyfin"
 	TypeMovie  = "media.asset.jellyfin.movie"
 	TypeSeries = "media.asset.jellyfin.series"
 	TypeSeason = "media.asset.jellyfin.season"
@@ -54,6 +58,7 @@ func NewProvider() *Provider {
 					Type:        "string",
 					Required:    true,
 					Description: "Jellyfin API key",
+					Secret:      true,
 				},
 			}),
 		}),
@@ -137,9 +142,12 @@ func (p *Provider) Hydrate(ctx context.Context, id string) (types.Entity, error)
 	}
 
 	item, err := p.client.GetItem(ctx, entityID.ResourceID())
-	if err != nil {
+	if errors.Is(err, errNotFound) {
 		return types.Entity{}, provider.ErrNotFound
 	}
+	if err != nil {
+		return types.Entity{}, err
+	}
 
 	return p.itemToEntity(*item), nil
 }
@@ -218,20 +226,28 @@ func (p *Provider) Search(ctx context.Context, query provider.SearchQuery) ([]ty
 	}
 
 	// Map filters to Jellyfin parameters
-	if genre, ok := query.Filters[AttrGenre].([]string); ok && len(genre) > 0 {
-		params.Genres = genre
+	if genre, ok := query.Filters[AttrGenre]; ok {
+		params.Genres = provider.StringList(genre)
 	}
-	if studio, ok := query.Filters[AttrStudio].([]string); ok && len(studio) > 0 {
-		params.Studios = studio
+	if studio, ok := query.Filters[AttrStudio]; ok {
+		params.Studios = provider.StringList(studio)
 	}
-	if years, ok := query.Filters[AttrYear].([]string); ok && len(years) > 0 {
-		params.Years = years
+	if year, ok := query.Filters[AttrYear]; ok {
+		params.Years = yearList(year)
 	}
-	if minRating, ok := query.Filters[AttrRating].(float64); ok {
-		params.MinCommunityRating = minRating
+	// Jellyfin only has a minimum community rating, so the maximum is
+	// applied to the results.
+	maxRating := 0.0
+	if rating, ok := query.Filters[AttrRating].(map[string]any); ok {
+		if min, ok := toFloat(rating["min"]); ok {
+			params.MinCommunityRating = min
+		}
+		if max, ok := toFloat(rating["max"]); ok {
+			maxRating = max
+		}
 	}
-	if officialRating, ok := query.Filters[AttrOfficialRating].(string); ok {
-		params.MaxOfficialRating = officialRating
+	if officialRating, ok := query.Filters[AttrOfficialRating]; ok {
+		params.OfficialRatings = provider.StringList(officialRating)
 	}
 
 	// Filter by type
@@ -253,12 +269,66 @@ func (p *Provider) Search(ctx context.Context, query provider.SearchQuery) ([]ty
 
 	entities := make([]types.Entity, 0, len(resp.Items))
 	for _, item := range resp.Items {
+		if maxRating > 0 && item.CommunityRating > maxRating {
+			continue
+		}
 		entities = append(entities, p.itemToEntity(item))
 	}
 
 	return entities, nil
 }
 
+// earliestYear bounds year ranges without a minimum.
+const earliestYear = 1888
+
+// yearList converts a year filter to the list of years Jellyfin accepts.
+// Ranges are expanded to every year they include; open ends are bounded by
+// earliestYear and next year.
+func yearList(value any) []string {
+	r, ok := value.(map[string]any)
+	if !ok {
+		if year, ok := toFloat(value); ok {
+			return []string{strconv.Itoa(int(year))}
+		}
+		return provider.StringList(value)
+	}
+
+	first, last := earliestYear, time.Now().Year()+1
+	if min, ok := toFloat(r["min"]); ok {
+		first = int(math.Ceil(min))
+	}
+	if max, ok := toFloat(r["max"]); ok {
+		last = int(math.Floor(max))
+	}
+	if first > last {
+		// Jellyfin ignores an empty list, so ask for a year nothing has.
+		return []string{"0"}
+	}
+
+	years := make([]string, 0, last-first+1)
+	for year := first; year <= last; year++ {
+		years = append(years, strconv.Itoa(year))
+	}
+	return years
+}
+
+// toFloat converts a numeric filter value to float64.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
 // FilterCapabilities returns the filter capabilities for Jellyfin.
 func (p *Provider) FilterCapabilities(ctx context.Context) (map[string]provider.FilterCapability, error) {
 	return map[string]provider.FilterCapability{
@@ -329,6 +399,11 @@ func (p *Provider) FilterValues(ctx context.Context, filterName string) ([]provi
 	}
 }
 
+// Health checks that the Jellyfin server is reachable.
+func (p *Provider) Health(ctx context.Context) error {
+	return p.client.Health(ctx)
+}
+
 // Shutdown gracefully shuts down the provider.
 func (p *Provider) Shutdown(ctx context.Context) error {
 	return nil
@@ -458,6 +533,38 @@ func (p *Provider) discoverWithLimit(ctx context.Context, limit int) ([]types.En
 	return entities, nil
 }
 
+// EntityTypes returns the types of the Jellyfin items the provider searches.
+func (p *Provider) EntityTypes() []string {
+	return []string{TypeMovie, TypeSeries, TypeSeason, TypeEpisode}
+}
+
+// TypeDefinitions returns the Jellyfin item types. Item kinds without their
+// own type are emitted as unregistered children of TypeJellyfin.
+func (p *Provider) TypeDefinitions() []types.TypeDefinition {
+	eq := types.FilterConfig{SupportsEq: true, SupportsNeq: true}
+	eqRange := types.FilterConfig{SupportsEq: true, SupportsNeq: true, SupportsRange: true}
+	return []types.TypeDefinition{
+		{
+			Name:        TypeJellyfin,
+			Parent:      types.TypeMediaAsset,
+			Description: "Base type for Jellyfin library items",
+			Attributes: map[string]types.AttributeDef{
+				AttrGenre:          {Name: AttrGenre, Type: types.AttributeTypeString, Filterable: true, Filter: eq},
+				AttrStudio:         {Name: AttrStudio, Type: types.AttributeTypeString, Filterable: true, Filter: eq},
+				AttrYear:           {Name: AttrYear, Type: types.AttributeTypeInt, Filterable: true, Filter: eqRange},
+				AttrRating:         {Name: AttrRating, Type: types.AttributeTypeFloat, Filterable: true, Filter: eqRange},
+				AttrOfficialRating: {Name: AttrOfficialRating, Type: types.AttributeTypeString, Filterable: true, Filter: eq},
+				AttrRuntime:        {Name: AttrRuntime, Type: types.AttributeTypeInt, Filterable: true, Filter: eqRange},
+				AttrOverview:       {Name: AttrOverview, Type: types.AttributeTypeString},
+			},
+		},
+		{Name: TypeMovie, Parent: TypeJellyfin, Description: "Jellyfin movie"},
+		{Name: TypeSeries, Parent: TypeJellyfin, Description: "Jellyfin TV series"},
+		{Name: TypeSeason, Parent: TypeJellyfin, Description: "Jellyfin TV season"},
+		{Name: TypeEpisode, Parent: TypeJellyfin, Description: "Jellyfin TV episode"},
+	}
+}
+
 // AttributeExtensions returns provider-specific attribute extensions.
 func (p *Provider) AttributeExtensions(ctx context.Context) map[string]types.AttributeDef {
 	return map[string]types.AttributeDef{
@@ -0,0 +1,248 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+)
+
+// Config configures a plugin executable.
+type Config struct {
+	// Command is the plugin executable
+	Command string `mapstructure:"command"`
+
+	// Args are passed to the executable
+	Args []string `mapstructure:"args"`
+
+	// Env holds extra environment variables, added to the host environment
+	Env map[string]string `mapstructure:"env"`
+
+	// Dir is the working directory (empty = the host's working directory)
+	Dir string `mapstructure:"dir"`
+}
+
+// process is a running plugin process and its JSON-RPC connection.
+type process struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	logger *zerolog.Logger
+
+	writeMu sync.Mutex
+	enc     *json.Encoder
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan Response
+	exitErr error
+
+	// done is closed once the process has exited and all pending calls failed
+	done chan struct{}
+}
+
+// startProcess launches a plugin executable.
+func startProcess(config Config, logger *zerolog.Logger) (*process, error) {
+	if config.Command == "" {
+		return nil, fmt.Errorf("plugin command is required")
+	}
+
+	cmd := exec.Command(config.Command, config.Args...)
+	cmd.Dir = config.Dir
+	cmd.Env = os.Environ()
+	for key, value := range config.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin stdout: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin stderr: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start plugin %q: %w", config.Command, err)
+	}
+
+	p := &process{
+		cmd:     cmd,
+		stdin:   stdin,
+		logger:  logger,
+		enc:     json.NewEncoder(stdin),
+		pending: make(map[int64]chan Response),
+		done:    make(chan struct{}),
+	}
+
+	go p.logStderr(stderr)
+	go p.readLoop(stdout)
+
+	return p, nil
+}
+
+// readLoop dispatches responses to their callers until stdout is closed,
+// then waits for the process and fails all pending calls.
+func (p *process) readLoop(stdout io.Reader) {
+	dec := json.NewDecoder(stdout)
+	for {
+		var resp Response
+		if err := dec.Decode(&resp); err != nil {
+			if err != io.EOF {
+				p.logger.Error().Err(err).Msg("Invalid message from plugin, stopping it")
+				_ = p.cmd.Process.Kill()
+			}
+			break
+		}
+
+		p.mu.Lock()
+		ch, ok := p.pending[resp.ID]
+		delete(p.pending, resp.ID)
+		p.mu.Unlock()
+		if ok {
+			ch <- resp
+		}
+	}
+
+	err := p.cmd.Wait()
+
+	p.mu.Lock()
+	p.exitErr = err
+	p.pending = make(map[int64]chan Response)
+	p.mu.Unlock()
+	close(p.done)
+
+	if err != nil {
+		p.logger.Warn().Err(err).Msg("Plugin process exited")
+	} else {
+		p.logger.Debug().Msg("Plugin process exited")
+	}
+}
+
+// logStderr forwards the plugin's stderr to the log, one line per entry.
+func (p *process) logStderr(stderr io.Reader) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		p.logger.Info().Str("stream", "stderr").Msg(scanner.Text())
+	}
+}
+
+// call sends a request and decodes the result into result (which may be nil).
+// If ctx is done first, the plugin is told to cancel the call.
+func (p *process) call(ctx context.Context, method string, params, result any) error {
+	var raw json.RawMessage
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s params: %w", method, err)
+		}
+		raw = data
+	}
+
+	ch := make(chan Response, 1)
+	p.mu.Lock()
+	select {
+	case <-p.done:
+		p.mu.Unlock()
+		return p.exited()
+	default:
+	}
+	p.nextID++
+	id := p.nextID
+	p.pending[id] = ch
+	p.mu.Unlock()
+
+	if err := p.send(Request{JSONRPC: "2.0", ID: &id, Method: method, Params: raw}); err != nil {
+		p.forget(id)
+		return provider.NewProviderError(provider.ErrorTypeTemporary, "failed to send to plugin", err)
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			if resp.Error.Code == CodeProviderError {
+				return resp.Error.ProviderError()
+			}
+			return resp.Error
+		}
+		if result != nil {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("failed to decode %s result: %w", method, err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		p.forget(id)
+		_ = p.notify(MethodCancel, CancelParams{ID: id})
+		return ctx.Err()
+	case <-p.done:
+		return p.exited()
+	}
+}
+
+// notify sends a notification.
+func (p *process) notify(method string, params any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	return p.send(Request{JSONRPC: "2.0", Method: method, Params: data})
+}
+
+// send writes a single message.
+func (p *process) send(req Request) error {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	return p.enc.Encode(req)
+}
+
+// forget drops a pending call whose result is no longer wanted.
+func (p *process) forget(id int64) {
+	p.mu.Lock()
+	delete(p.pending, id)
+	p.mu.Unlock()
+}
+
+// exited returns the error for calls on a process that is gone. It is
+// temporary, since the provider restarts the process.
+func (p *process) exited() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return provider.NewProviderError(provider.ErrorTypeTemporary, "plugin process exited", p.exitErr)
+}
+
+// alive reports whether the process is still running.
+func (p *process) alive() bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// close closes the plugin's stdin, which tells it to exit, and waits for it
+// until ctx is done, after which the process is killed.
+func (p *process) close(ctx context.Context) error {
+	_ = p.stdin.Close()
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		_ = p.cmd.Process.Kill()
+		<-p.done
+		return ctx.Err()
+	}
+}
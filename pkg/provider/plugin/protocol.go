@@ -0,0 +1,253 @@
+// Package plugin runs providers as separate processes.
+//
+// A plugin is an executable that speaks JSON-RPC 2.0 on stdin/stdout, one
+// message per line. The host launches one process per provider instance and
+// calls the methods below, which mirror the provider.Provider interface and
+// its optional FilterValuesProvider, ThumbnailProvider,
+// AttributeExtensionsProvider and HealthChecker interfaces. Anything the
+// plugin writes to stderr is forwarded to the host log.
+//
+// Params and results use the JSON encoding of the corresponding Go types
+// (types.Entity, provider.SearchQuery, provider.FilterCapability, ...).
+// Plugins written in Go should use the sdk subpackage instead of
+// implementing the protocol by hand.
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// ProtocolVersion is the plugin protocol version. The host refuses plugins
+// that report a different version in their describe result.
+const ProtocolVersion = 1
+
+// Methods of the plugin protocol.
+const (
+	MethodDescribe            = "describe"
+	MethodInitialize          = "initialize"
+	MethodDiscover            = "discover"
+	MethodDiscoverSince       = "discover_since"
+	MethodHydrate             = "hydrate"
+	MethodGetRelated          = "get_related"
+	MethodSearch              = "search"
+	MethodFilterCapabilities  = "filter_capabilities"
+	MethodFilterValues        = "filter_values"
+	MethodThumbnail           = "thumbnail"
+	MethodAttributeExtensions = "attribute_extensions"
+	MethodHealth              = "health"
+	MethodShutdown            = "shutdown"
+
+	// MethodCancel is a notification (no id, no response) sent by the host
+	// when the context of an in-flight call is done.
+	MethodCancel = "$/cancel"
+)
+
+// Request is a JSON-RPC request or notification.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *int64          `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response is a JSON-RPC response.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error. Provider errors carry their provider.ErrorType
+// in Data so that the host can rebuild them (e.g. to retry temporary errors).
+type Error struct {
+	Code    int        `json:"code"`
+	Message string     `json:"message"`
+	Data    *ErrorData `json:"data,omitempty"`
+}
+
+// ErrorData is the data of a provider error.
+type ErrorData struct {
+	Type provider.ErrorType `json:"type"`
+}
+
+// JSON-RPC error codes.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+
+	// CodeProviderError is used for errors returned by the provider itself
+	CodeProviderError = -32000
+)
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("plugin error %d: %s", e.Code, e.Message)
+}
+
+// NewError encodes a provider error for the wire.
+func NewError(err error) *Error {
+	errType := provider.ErrorTypeUnknown
+	var perr *provider.ProviderError
+	if errors.As(err, &perr) {
+		errType = perr.Type
+	}
+	return &Error{
+		Code:    CodeProviderError,
+		Message: err.Error(),
+		Data:    &ErrorData{Type: errType},
+	}
+}
+
+// ProviderError decodes a wire error into a provider error. Not-found errors
+// wrap provider.ErrNotFound so that errors.Is works across the process boundary.
+func (e *Error) ProviderError() error {
+	if e.Data == nil {
+		return provider.NewProviderError(provider.ErrorTypeUnknown, e.Message, nil)
+	}
+	if e.Data.Type == provider.ErrorTypeNotFound {
+		return fmt.Errorf("%w: %s", provider.ErrNotFound, e.Message)
+	}
+	return provider.NewProviderError(e.Data.Type, e.Message, nil)
+}
+
+// ConfigField is the wire form of provider.ConfigField.
+type ConfigField struct {
+	Type        string `json:"type"`
+	Required    bool   `json:"required,omitempty"`
+	Description string `json:"description,omitempty"`
+	Default     any    `json:"default,omitempty"`
+	Secret      bool   `json:"secret,omitempty"`
+}
+
+// Capabilities lists the optional parts of the protocol a plugin implements.
+type Capabilities struct {
+	Incremental         bool `json:"incremental"`
+	RelevanceScore      bool `json:"relevance_score"`
+	FilterValues        bool `json:"filter_values"`
+	Thumbnails          bool `json:"thumbnails"`
+	AttributeExtensions bool `json:"attribute_extensions"`
+	Health              bool `json:"health"`
+	FilterExpressions   bool `json:"filter_expressions"`
+}
+
+// DescribeResult is the result of MethodDescribe.
+type DescribeResult struct {
+	ProtocolVersion int                    `json:"protocol_version"`
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description"`
+	ConfigSchema    map[string]ConfigField `json:"config_schema"`
+	Capabilities    Capabilities           `json:"capabilities"`
+	EntityTypes     []string               `json:"entity_types,omitempty"`
+	TypeDefinitions []types.TypeDefinition `json:"type_definitions,omitempty"`
+}
+
+// InitializeParams are the params of MethodInitialize.
+type InitializeParams struct {
+	Config map[string]any `json:"config"`
+}
+
+// DiscoverSinceParams are the params of MethodDiscoverSince.
+type DiscoverSinceParams struct {
+	Since time.Time `json:"since"`
+}
+
+// HydrateParams are the params of MethodHydrate.
+type HydrateParams struct {
+	ID string `json:"id"`
+}
+
+// GetRelatedParams are the params of MethodGetRelated.
+type GetRelatedParams struct {
+	ID      string `json:"id"`
+	RelType string `json:"rel_type"`
+}
+
+// SearchParams are the params of MethodSearch.
+type SearchParams struct {
+	Query provider.SearchQuery `json:"query"`
+}
+
+// FilterValuesParams are the params of MethodFilterValues.
+type FilterValuesParams struct {
+	FilterName string `json:"filter_name"`
+}
+
+// ThumbnailParams are the params of MethodThumbnail.
+type ThumbnailParams struct {
+	ID string `json:"id"`
+}
+
+// ThumbnailResult is the result of MethodThumbnail. Data is base64 encoded on the wire.
+type ThumbnailResult struct {
+	Data        []byte `json:"data"`
+	ContentType string `json:"content_type"`
+}
+
+// CancelParams are the params of MethodCancel.
+type CancelParams struct {
+	ID int64 `json:"id"`
+}
+
+// EntitiesResult is the result of the methods that return entities.
+type EntitiesResult struct {
+	Entities []types.Entity `json:"entities"`
+}
+
+// EntityResult is the result of MethodHydrate.
+type EntityResult struct {
+	Entity types.Entity `json:"entity"`
+}
+
+// FilterCapabilitiesResult is the result of MethodFilterCapabilities.
+type FilterCapabilitiesResult struct {
+	Capabilities map[string]provider.FilterCapability `json:"capabilities"`
+}
+
+// FilterValuesResult is the result of MethodFilterValues.
+type FilterValuesResult struct {
+	Options []provider.FilterOption `json:"options"`
+}
+
+// AttributeExtensionsResult is the result of MethodAttributeExtensions.
+type AttributeExtensionsResult struct {
+	Extensions map[string]types.AttributeDef `json:"extensions"`
+}
+
+// schemaToWire converts a provider config schema to its wire form.
+func schemaToWire(schema map[string]provider.ConfigField) map[string]ConfigField {
+	wire := make(map[string]ConfigField, len(schema))
+	for name, field := range schema {
+		wire[name] = ConfigField{
+			Type:        field.Type,
+			Required:    field.Required,
+			Description: field.Description,
+			Default:     field.Default,
+			Secret:      field.Secret,
+		}
+	}
+	return wire
+}
+
+// schemaFromWire converts a wire config schema to a provider config schema.
+func schemaFromWire(wire map[string]ConfigField) map[string]provider.ConfigField {
+	schema := make(map[string]provider.ConfigField, len(wire))
+	for name, field := range wire {
+		schema[name] = provider.ConfigField{
+			Type:        field.Type,
+			Required:    field.Required,
+			Description: field.Description,
+			Default:     field.Default,
+			Secret:      field.Secret,
+		}
+	}
+	return schema
+}
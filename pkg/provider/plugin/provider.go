@@ -0,0 +1,388 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// Restart backoff bounds for crashed plugin processes.
+const (
+	restartBaseDelay = time.Second
+	restartMaxDelay  = time.Minute
+)
+
+// shutdownTimeout bounds how long a plugin is given to exit after shutdown.
+const shutdownTimeout = 5 * time.Second
+
+// Provider is a provider.Provider that forwards every call to a plugin process.
+// The process is launched by Initialize and restarted with exponential backoff
+// if it exits; calls made while it is down fail with a temporary error, so the
+// retry middleware and the entity store fallback apply as for any provider.
+type Provider struct {
+	description DescribeResult
+	config      Config
+	logger      zerolog.Logger
+
+	mu         sync.Mutex
+	proc       *process
+	instanceID string
+	settings   map[string]any
+	stop       chan struct{}
+	stopped    bool
+}
+
+// NewProvider creates a proxy for the plugin described by description.
+func NewProvider(config Config, description DescribeResult, logger *zerolog.Logger) *Provider {
+	return &Provider{
+		description: description,
+		config:      config,
+		logger:      logger.With().Str("plugin", description.Name).Logger(),
+		stop:        make(chan struct{}),
+	}
+}
+
+// Describe launches a plugin once and returns its description.
+func Describe(ctx context.Context, config Config, logger *zerolog.Logger) (DescribeResult, error) {
+	var description DescribeResult
+
+	proc, err := startProcess(config, logger)
+	if err != nil {
+		return description, err
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = proc.close(closeCtx)
+	}()
+
+	if err := proc.call(ctx, MethodDescribe, nil, &description); err != nil {
+		return description, fmt.Errorf("plugin %q: describe failed: %w", config.Command, err)
+	}
+	if description.ProtocolVersion != ProtocolVersion {
+		return description, fmt.Errorf("plugin %q: unsupported protocol version %d (want %d)", config.Command, description.ProtocolVersion, ProtocolVersion)
+	}
+	if description.Name == "" {
+		return description, fmt.Errorf("plugin %q: describe returned no name", config.Command)
+	}
+	return description, nil
+}
+
+// Register describes every configured plugin and registers it as a provider
+// type under the name it reports. Instances of plugin types are configured in
+// the providers list like built-in types; each instance runs its own process.
+func Register(ctx context.Context, registry *provider.Registry, configs []Config, logger *zerolog.Logger) error {
+	for _, config := range configs {
+		description, err := Describe(ctx, config, logger)
+		if err != nil {
+			return err
+		}
+
+		if err := registry.Register(provider.ProviderMetadata{
+			Name:         description.Name,
+			Description:  description.Description,
+			ConfigSchema: schemaFromWire(description.ConfigSchema),
+			Factory: func() provider.Provider {
+				return NewProvider(config, description, logger)
+			},
+		}); err != nil {
+			return fmt.Errorf("plugin %q: %w", config.Command, err)
+		}
+
+		logger.Info().
+			Str("plugin", description.Name).
+			Str("command", config.Command).
+			Msg("Plugin provider registered")
+	}
+	return nil
+}
+
+// Name returns the provider type reported by the plugin.
+func (p *Provider) Name() string {
+	return p.description.Name
+}
+
+// InstanceID returns the instance ID.
+func (p *Provider) InstanceID() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.instanceID
+}
+
+// Initialize launches the plugin process, passes it the config and starts
+// supervising it.
+func (p *Provider) Initialize(ctx context.Context, config map[string]any) error {
+	instanceID, _ := config["instance_id"].(string)
+
+	proc, err := p.launch(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.proc = proc
+	p.instanceID = instanceID
+	p.settings = config
+	p.mu.Unlock()
+
+	go p.supervise(proc)
+	return nil
+}
+
+// launch starts a process and initializes it.
+func (p *Provider) launch(ctx context.Context, config map[string]any) (*process, error) {
+	proc, err := startProcess(p.config, &p.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := proc.call(ctx, MethodInitialize, InitializeParams{Config: config}, nil); err != nil {
+		closeCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = proc.close(closeCtx)
+		return nil, err
+	}
+	return proc, nil
+}
+
+// supervise restarts the plugin process whenever it exits, until Shutdown.
+func (p *Provider) supervise(proc *process) {
+	for failures := 0; ; {
+		select {
+		case <-p.stop:
+			return
+		case <-proc.done:
+		}
+
+		delay := restartBaseDelay << failures
+		if delay > restartMaxDelay || delay <= 0 {
+			delay = restartMaxDelay
+		}
+		p.logger.Warn().Dur("delay", delay).Msg("Plugin process exited, restarting")
+
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(delay):
+		}
+
+		p.mu.Lock()
+		settings := p.settings
+		p.mu.Unlock()
+
+		next, err := p.launch(context.Background(), settings)
+		if err != nil {
+			p.logger.Error().Err(err).Msg("Plugin restart failed")
+			failures++
+			continue
+		}
+
+		p.mu.Lock()
+		if p.stopped {
+			p.mu.Unlock()
+			closeCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			_ = next.close(closeCtx)
+			cancel()
+			return
+		}
+		p.proc = next
+		p.mu.Unlock()
+
+		p.logger.Info().Msg("Plugin process restarted")
+		proc = next
+		failures = 0
+	}
+}
+
+// current returns the running process, or a temporary error while it is down.
+func (p *Provider) current() (*process, error) {
+	p.mu.Lock()
+	proc := p.proc
+	p.mu.Unlock()
+
+	if proc == nil || !proc.alive() {
+		return nil, provider.NewProviderError(provider.ErrorTypeTemporary, "plugin not running", nil)
+	}
+	return proc, nil
+}
+
+// call forwards a call to the running process.
+func (p *Provider) call(ctx context.Context, method string, params, result any) error {
+	proc, err := p.current()
+	if err != nil {
+		return err
+	}
+	return proc.call(ctx, method, params, result)
+}
+
+// Discover forwards to the plugin.
+func (p *Provider) Discover(ctx context.Context) ([]types.Entity, error) {
+	var result EntitiesResult
+	if err := p.call(ctx, MethodDiscover, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Entities, nil
+}
+
+// DiscoverSince forwards to the plugin.
+func (p *Provider) DiscoverSince(ctx context.Context, since time.Time) ([]types.Entity, error) {
+	if !p.description.Capabilities.Incremental {
+		return nil, provider.ErrIncrementalNotSupported
+	}
+
+	var result EntitiesResult
+	if err := p.call(ctx, MethodDiscoverSince, DiscoverSinceParams{Since: since}, &result); err != nil {
+		return nil, err
+	}
+	return result.Entities, nil
+}
+
+// Hydrate forwards to the plugin.
+func (p *Provider) Hydrate(ctx context.Context, id string) (types.Entity, error) {
+	var result EntityResult
+	if err := p.call(ctx, MethodHydrate, HydrateParams{ID: id}, &result); err != nil {
+		return types.Entity{}, err
+	}
+	return result.Entity, nil
+}
+
+// GetRelated forwards to the plugin.
+func (p *Provider) GetRelated(ctx context.Context, id string, relType string) ([]types.Entity, error) {
+	var result EntitiesResult
+	if err := p.call(ctx, MethodGetRelated, GetRelatedParams{ID: id, RelType: relType}, &result); err != nil {
+		return nil, err
+	}
+	return result.Entities, nil
+}
+
+// Search forwards to the plugin.
+func (p *Provider) Search(ctx context.Context, query provider.SearchQuery) ([]types.Entity, error) {
+	var result EntitiesResult
+	if err := p.call(ctx, MethodSearch, SearchParams{Query: query}, &result); err != nil {
+		return nil, err
+	}
+	return result.Entities, nil
+}
+
+// FilterCapabilities forwards to the plugin.
+func (p *Provider) FilterCapabilities(ctx context.Context) (map[string]provider.FilterCapability, error) {
+	var result FilterCapabilitiesResult
+	if err := p.call(ctx, MethodFilterCapabilities, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.Capabilities, nil
+}
+
+// FilterValues forwards to the plugin if it supports pre-obtained filter values.
+func (p *Provider) FilterValues(ctx context.Context, filterName string) ([]provider.FilterOption, error) {
+	if !p.description.Capabilities.FilterValues {
+		return nil, nil
+	}
+
+	var result FilterValuesResult
+	if err := p.call(ctx, MethodFilterValues, FilterValuesParams{FilterName: filterName}, &result); err != nil {
+		return nil, err
+	}
+	return result.Options, nil
+}
+
+// GetThumbnail forwards to the plugin. Plugins without thumbnail support
+// return a not_supported error, and callers fall back to thumbnail URLs.
+func (p *Provider) GetThumbnail(ctx context.Context, id string) ([]byte, string, error) {
+	if !p.description.Capabilities.Thumbnails {
+		return nil, "", provider.NewProviderError(provider.ErrorTypeNotSupported, "thumbnails not supported", nil)
+	}
+
+	var result ThumbnailResult
+	if err := p.call(ctx, MethodThumbnail, ThumbnailParams{ID: id}, &result); err != nil {
+		return nil, "", err
+	}
+	return result.Data, result.ContentType, nil
+}
+
+// AttributeExtensions forwards to the plugin if it extends attributes.
+func (p *Provider) AttributeExtensions(ctx context.Context) map[string]types.AttributeDef {
+	if !p.description.Capabilities.AttributeExtensions {
+		return map[string]types.AttributeDef{}
+	}
+
+	var result AttributeExtensionsResult
+	if err := p.call(ctx, MethodAttributeExtensions, nil, &result); err != nil {
+		p.logger.Warn().Err(err).Msg("Failed to get attribute extensions")
+		return map[string]types.AttributeDef{}
+	}
+	return result.Extensions
+}
+
+// EntityTypes returns the entity types the plugin declared when described.
+func (p *Provider) EntityTypes() []string {
+	return p.description.EntityTypes
+}
+
+// TypeDefinitions returns the types the plugin defined when described.
+func (p *Provider) TypeDefinitions() []types.TypeDefinition {
+	return p.description.TypeDefinitions
+}
+
+// Health reports whether the process is running and, if the plugin has its
+// own health check, whether its backend is reachable.
+func (p *Provider) Health(ctx context.Context) error {
+	proc, err := p.current()
+	if err != nil {
+		return err
+	}
+	if !p.description.Capabilities.Health {
+		return nil
+	}
+	return proc.call(ctx, MethodHealth, nil, nil)
+}
+
+// SupportsIncremental reports the plugin's capability.
+func (p *Provider) SupportsIncremental() bool {
+	return p.description.Capabilities.Incremental
+}
+
+// SupportsFilterExpressions reports the plugin's capability.
+func (p *Provider) SupportsFilterExpressions() bool {
+	return p.description.Capabilities.FilterExpressions
+}
+
+// SupportsRelevanceScore reports the plugin's capability.
+func (p *Provider) SupportsRelevanceScore() bool {
+	return p.description.Capabilities.RelevanceScore
+}
+
+// Shutdown stops supervision, asks the plugin to shut down and waits for the
+// process to exit.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return nil
+	}
+	p.stopped = true
+	close(p.stop)
+	proc := p.proc
+	p.mu.Unlock()
+
+	if proc == nil || !proc.alive() {
+		return nil
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, shutdownTimeout)
+		defer cancel()
+	}
+
+	err := proc.call(ctx, MethodShutdown, nil, nil)
+	if closeErr := proc.close(ctx); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+// Package sdk is the Go SDK for out-of-process mifind providers.
+//
+// A plugin implements Provider, usually by embedding BaseProvider, and calls
+// Serve from main:
+//
+//	func main() {
+//		if err := sdk.Serve(NewMyProvider()); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+//
+// The optional FilterValuesProvider, ThumbnailProvider,
+// AttributeExtensionsProvider, HealthChecker, TypeProvider,
+// TypeDefinitionsProvider and FilterExpressionProvider interfaces are detected
+// and advertised to the host automatically. The plugin is then listed under
+// "plugins" in the mifind config, and its instances are configured in the
+// "providers" list under the name the provider reports.
+//
+// Stdout carries the protocol; Serve redirects os.Stdout to stderr so that
+// stray output can't corrupt it. Anything written to stderr ends up in the
+// host log.
+package sdk
+
+import (
+	"os"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/types"
+	"github.com/yourname/mifind/pkg/provider/plugin"
+)
+
+// Provider types, re-exported for plugins built outside this module.
+type (
+	Provider                    = provider.Provider
+	BaseProvider                = provider.BaseProvider
+	ProviderMetadata            = provider.ProviderMetadata
+	ConfigField                 = provider.ConfigField
+	SearchQuery                 = provider.SearchQuery
+	FilterCapability            = provider.FilterCapability
+	FilterOption                = provider.FilterOption
+	FilterValuesProvider        = provider.FilterValuesProvider
+	ThumbnailProvider           = provider.ThumbnailProvider
+	AttributeExtensionsProvider = provider.AttributeExtensionsProvider
+	HealthChecker               = provider.HealthChecker
+	TypeProvider                = provider.TypeProvider
+	TypeDefinitionsProvider     = provider.TypeDefinitionsProvider
+	FilterExpressionProvider    = provider.FilterExpressionProvider
+	EntityID                    = provider.EntityID
+	ProviderError               = provider.ProviderError
+	ErrorType                   = provider.ErrorType
+)
+
+// Entity types, re-exported for plugins built outside this module.
+type (
+	Entity         = types.Entity
+	Relationship   = types.Relationship
+	AttributeDef   = types.AttributeDef
+	AttributeType  = types.AttributeType
+	TypeDefinition = types.TypeDefinition
+)
+
+// Error types understood by the host. Temporary and rate limit errors are retried.
+const (
+	ErrorTypeConfig       = provider.ErrorTypeConfig
+	ErrorTypeAuth         = provider.ErrorTypeAuth
+	ErrorTypeNotFound     = provider.ErrorTypeNotFound
+	ErrorTypeNotSupported = provider.ErrorTypeNotSupported
+	ErrorTypeRateLimit    = provider.ErrorTypeRateLimit
+	ErrorTypeTemporary    = provider.ErrorTypeTemporary
+	ErrorTypeUnknown      = provider.ErrorTypeUnknown
+)
+
+// Common errors. Return ErrNotFound (or wrap it) from Hydrate for unknown IDs.
+var (
+	ErrNotFound                = provider.ErrNotFound
+	ErrNotConfigured           = provider.ErrNotConfigured
+	ErrAuthenticationFailed    = provider.ErrAuthenticationFailed
+	ErrIncrementalNotSupported = provider.ErrIncrementalNotSupported
+	ErrRateLimited             = provider.ErrRateLimited
+	ErrTemporary               = provider.ErrTemporary
+)
+
+// NewBaseProvider creates the base for a provider with the given metadata.
+func NewBaseProvider(meta ProviderMetadata) *BaseProvider {
+	return provider.NewBaseProvider(meta)
+}
+
+// AddStandardConfigFields adds the fields every provider accepts (instance_id) to a schema.
+func AddStandardConfigFields(schema map[string]ConfigField) map[string]ConfigField {
+	return provider.AddStandardConfigFields(schema)
+}
+
+// NewEntityID builds an entity ID ("providerType:instanceID:entityID").
+func NewEntityID(providerType, instanceID, entityID string) EntityID {
+	return provider.NewEntityID(providerType, instanceID, entityID)
+}
+
+// NewProviderError creates a provider error of the given type.
+func NewProviderError(errType ErrorType, message string, err error) *ProviderError {
+	return provider.NewProviderError(errType, message, err)
+}
+
+// Serve serves p on stdin/stdout until the host closes stdin.
+func Serve(p Provider) error {
+	out := os.Stdout
+	os.Stdout = os.Stderr
+
+	return plugin.NewServer(p).Serve(os.Stdin, out)
+}
@@ -0,0 +1,292 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/yourname/mifind/internal/provider"
+)
+
+// Server serves a provider over the plugin protocol. Requests are handled
+// concurrently; MethodCancel cancels the context of an in-flight request.
+type Server struct {
+	provider provider.Provider
+
+	writeMu sync.Mutex
+	enc     *json.Encoder
+
+	mu      sync.Mutex
+	cancels map[int64]context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewServer creates a server for the given provider.
+func NewServer(p provider.Provider) *Server {
+	return &Server{
+		provider: p,
+		cancels:  make(map[int64]context.CancelFunc),
+	}
+}
+
+// Serve reads requests from r and writes responses to w until r is closed.
+// In-flight requests are cancelled and waited for before Serve returns.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.enc = json.NewEncoder(w)
+	dec := json.NewDecoder(r)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		s.wg.Wait()
+	}()
+
+	for {
+		var req Request
+		if err := dec.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+
+		if req.Method == MethodCancel {
+			var params CancelParams
+			if err := json.Unmarshal(req.Params, &params); err == nil {
+				s.cancel(params.ID)
+			}
+			continue
+		}
+		if req.ID == nil {
+			// Unknown notification
+			continue
+		}
+
+		id := *req.ID
+		reqCtx, reqCancel := context.WithCancel(ctx)
+		s.mu.Lock()
+		s.cancels[id] = reqCancel
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.cancel(id)
+
+			result, err := s.handle(reqCtx, req.Method, req.Params)
+			s.respond(id, result, err)
+		}()
+	}
+}
+
+// cancel cancels and forgets an in-flight request.
+func (s *Server) cancel(id int64) {
+	s.mu.Lock()
+	cancel, ok := s.cancels[id]
+	delete(s.cancels, id)
+	s.mu.Unlock()
+	if ok {
+		cancel()
+	}
+}
+
+// respond writes the response to a request.
+func (s *Server) respond(id int64, result any, err error) {
+	resp := Response{JSONRPC: "2.0", ID: id}
+	if err != nil {
+		var rpcErr *Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = NewError(err)
+		}
+		resp.Error = rpcErr
+	} else {
+		data, err := json.Marshal(result)
+		if err != nil {
+			resp.Error = &Error{Code: CodeInternalError, Message: fmt.Sprintf("failed to encode result: %v", err)}
+		} else {
+			resp.Result = data
+		}
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.enc.Encode(resp)
+}
+
+// handle dispatches a request to the provider.
+func (s *Server) handle(ctx context.Context, method string, raw json.RawMessage) (any, error) {
+	p := s.provider
+
+	switch method {
+	case MethodDescribe:
+		return s.describe(), nil
+
+	case MethodInitialize:
+		var params InitializeParams
+		if err := decodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		return struct{}{}, p.Initialize(ctx, s.normalizeConfig(params.Config))
+
+	case MethodDiscover:
+		entities, err := p.Discover(ctx)
+		return EntitiesResult{Entities: entities}, err
+
+	case MethodDiscoverSince:
+		var params DiscoverSinceParams
+		if err := decodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		entities, err := p.DiscoverSince(ctx, params.Since)
+		return EntitiesResult{Entities: entities}, err
+
+	case MethodHydrate:
+		var params HydrateParams
+		if err := decodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		entity, err := p.Hydrate(ctx, params.ID)
+		return EntityResult{Entity: entity}, err
+
+	case MethodGetRelated:
+		var params GetRelatedParams
+		if err := decodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		entities, err := p.GetRelated(ctx, params.ID, params.RelType)
+		return EntitiesResult{Entities: entities}, err
+
+	case MethodSearch:
+		var params SearchParams
+		if err := decodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		entities, err := p.Search(ctx, params.Query)
+		return EntitiesResult{Entities: entities}, err
+
+	case MethodFilterCapabilities:
+		caps, err := p.FilterCapabilities(ctx)
+		return FilterCapabilitiesResult{Capabilities: caps}, err
+
+	case MethodFilterValues:
+		fv, ok := p.(provider.FilterValuesProvider)
+		if !ok {
+			return nil, methodNotFound(method)
+		}
+		var params FilterValuesParams
+		if err := decodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		options, err := fv.FilterValues(ctx, params.FilterName)
+		return FilterValuesResult{Options: options}, err
+
+	case MethodThumbnail:
+		tp, ok := p.(provider.ThumbnailProvider)
+		if !ok {
+			return nil, methodNotFound(method)
+		}
+		var params ThumbnailParams
+		if err := decodeParams(raw, &params); err != nil {
+			return nil, err
+		}
+		data, contentType, err := tp.GetThumbnail(ctx, params.ID)
+		return ThumbnailResult{Data: data, ContentType: contentType}, err
+
+	case MethodAttributeExtensions:
+		ext, ok := p.(provider.AttributeExtensionsProvider)
+		if !ok {
+			return nil, methodNotFound(method)
+		}
+		return AttributeExtensionsResult{Extensions: ext.AttributeExtensions(ctx)}, nil
+
+	case MethodHealth:
+		hc, ok := p.(provider.HealthChecker)
+		if !ok {
+			return struct{}{}, nil
+		}
+		return struct{}{}, hc.Health(ctx)
+
+	case MethodShutdown:
+		return struct{}{}, p.Shutdown(ctx)
+
+	default:
+		return nil, methodNotFound(method)
+	}
+}
+
+// describer is implemented by providers that embed provider.BaseProvider.
+type describer interface {
+	Metadata() provider.ProviderMetadata
+}
+
+// describe builds the describe result from the provider's metadata and the
+// optional interfaces it implements.
+func (s *Server) describe() DescribeResult {
+	p := s.provider
+
+	result := DescribeResult{
+		ProtocolVersion: ProtocolVersion,
+		Name:            p.Name(),
+		ConfigSchema:    map[string]ConfigField{},
+		Capabilities: Capabilities{
+			Incremental:    p.SupportsIncremental(),
+			RelevanceScore: p.SupportsRelevanceScore(),
+		},
+	}
+	if exprs, ok := p.(provider.FilterExpressionProvider); ok {
+		result.Capabilities.FilterExpressions = exprs.SupportsFilterExpressions()
+	}
+	if described, ok := p.(describer); ok {
+		meta := described.Metadata()
+		result.Description = meta.Description
+		result.ConfigSchema = schemaToWire(meta.ConfigSchema)
+	}
+
+	_, result.Capabilities.FilterValues = p.(provider.FilterValuesProvider)
+	_, result.Capabilities.Thumbnails = p.(provider.ThumbnailProvider)
+	_, result.Capabilities.AttributeExtensions = p.(provider.AttributeExtensionsProvider)
+	_, result.Capabilities.Health = p.(provider.HealthChecker)
+	if typed, ok := p.(provider.TypeProvider); ok {
+		result.EntityTypes = typed.EntityTypes()
+	}
+	if defined, ok := p.(provider.TypeDefinitionsProvider); ok {
+		result.TypeDefinitions = defined.TypeDefinitions()
+	}
+
+	return result
+}
+
+// normalizeConfig converts whole numbers in int config fields back to int,
+// since JSON decodes every number as float64.
+func (s *Server) normalizeConfig(config map[string]any) map[string]any {
+	described, ok := s.provider.(describer)
+	if !ok {
+		return config
+	}
+	schema := described.Metadata().ConfigSchema
+	for key, value := range config {
+		if f, ok := value.(float64); ok && schema[key].Type == "int" && f == float64(int64(f)) {
+			config[key] = int(f)
+		}
+	}
+	return config
+}
+
+// decodeParams decodes request params, reporting failures as invalid params.
+func decodeParams(raw json.RawMessage, params any) error {
+	if len(raw) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(raw, params); err != nil {
+		return &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// methodNotFound returns the error for an unsupported method.
+func methodNotFound(method string) error {
+	return &Error{Code: CodeMethodNotFound, Message: fmt.Sprintf("method %q not supported", method)}
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/pkg/provider/plugin"
+	"github.com/yourname/mifind/pkg/provider/plugin/sdk"
+)
+
+// TestMain lets the test binary act as a plugin serving the mock provider,
+// so the tests can launch it as a child process.
+func TestMain(m *testing.M) {
+	if os.Getenv("MIFIND_TEST_PLUGIN") == "1" {
+		if err := sdk.Serve(mock.NewMockProvider()); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// TestPlugin_RoundTrip tests that a plugin is registered under the name it
+// reports and that calls and errors survive the process boundary.
+func TestPlugin_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	logger := zerolog.Nop()
+
+	registry := provider.NewRegistry()
+	config := plugin.Config{
+		Command: os.Args[0],
+		Env:     map[string]string{"MIFIND_TEST_PLUGIN": "1"},
+	}
+	if err := plugin.Register(ctx, registry, []plugin.Config{config}, &logger); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	meta := registry.Get("mock")
+	if meta == nil {
+		t.Fatal("Expected plugin to be registered as \"mock\"")
+	}
+	if meta.ConfigSchema["entity_count"].Type != "int" {
+		t.Errorf("Expected the plugin's config schema, got %+v", meta.ConfigSchema)
+	}
+
+	manager := provider.NewManager(registry, &logger)
+	if err := manager.Initialize(ctx, "mock", map[string]any{"instance_id": "p", "entity_count": 4}); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	defer manager.ShutdownAll(ctx)
+
+	results := manager.SearchAll(ctx, provider.SearchQuery{})
+	entities := results["mock:p"]
+	if len(entities) != 4 {
+		t.Fatalf("Expected 4 entities from the plugin, got %d", len(entities))
+	}
+
+	entity, err := manager.Hydrate(ctx, entities[0].ID)
+	if err != nil {
+		t.Fatalf("Hydrate failed: %v", err)
+	}
+	if entity.ID != entities[0].ID || entity.Title != entities[0].Title {
+		t.Errorf("Hydrate returned %q (%q), want %q (%q)", entity.ID, entity.Title, entities[0].ID, entities[0].Title)
+	}
+
+	_, err = manager.Hydrate(ctx, "mock:p:missing")
+	if !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound across the process boundary, got %v", err)
+	}
+
+	prov, _ := manager.Get("mock:p")
+	checker, ok := provider.Unwrap(prov).(provider.HealthChecker)
+	if !ok {
+		t.Fatal("Expected the plugin proxy to implement HealthChecker")
+	}
+	if err := checker.Health(ctx); err != nil {
+		t.Errorf("Expected a running plugin to be healthy, got %v", err)
+	}
+}
@@ -0,0 +1,232 @@
+package providertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// GitLabToken is the access token the GitLab fake accepts.
+const GitLabToken = "providertest-gitlab-token"
+
+// GitLabProject is the path of the recorded project that has issues.
+const GitLabProject = "platform/api-gateway"
+
+const (
+	// gitlabDefaultPerPage and gitlabMaxPerPage are GitLab's page sizes.
+	gitlabDefaultPerPage = 20
+	gitlabMaxPerPage     = 100
+)
+
+// gitlabFake serves the recorded GitLab projects.
+type gitlabFake struct {
+	user     map[string]any
+	projects []map[string]any
+	issues   []map[string]any
+	labels   []map[string]any
+}
+
+// NewGitLabServer starts a fake GitLab server under /api/v4. It serves three
+// recorded projects, and the issues and labels of GitLabProject, and
+// implements the search, filter, sort and paging params of the project and
+// issue lists. Requests must carry GitLabToken.
+func NewGitLabServer(t *testing.T) *Server {
+	t.Helper()
+	fake := &gitlabFake{}
+	loadFixture("gitlab/user.json", &fake.user)
+	loadFixture("gitlab/projects.json", &fake.projects)
+	loadFixture("gitlab/issues.json", &fake.issues)
+	loadFixture("gitlab/labels.json", &fake.labels)
+	return newServer(t, fake)
+}
+
+// ServeHTTP implements the GitLab REST endpoints the provider uses.
+func (f *gitlabFake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("PRIVATE-TOKEN") != GitLabToken {
+		gitlabError(w, http.StatusUnauthorized, "401 Unauthorized")
+		return
+	}
+
+	// Project IDs may be URL-encoded paths, so split the escaped path.
+	path, ok := strings.CutPrefix(r.URL.EscapedPath(), "/api/v4/")
+	if !ok || r.Method != http.MethodGet {
+		gitlabError(w, http.StatusNotFound, "404 Not Found")
+		return
+	}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		parts[i], _ = url.PathUnescape(part)
+	}
+
+	switch {
+	case len(parts) == 1 && parts[0] == "user":
+		writeJSON(w, f.user)
+
+	case len(parts) == 1 && parts[0] == "projects":
+		f.listProjects(w, r.URL.Query())
+
+	case len(parts) >= 2 && parts[0] == "projects":
+		project := f.project(parts[1])
+		if project == nil {
+			gitlabError(w, http.StatusNotFound, "404 Project Not Found")
+			return
+		}
+		f.serveProject(w, r.URL.Query(), project, parts[2:])
+
+	default:
+		gitlabError(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+// serveProject serves a project and its sub-resources.
+func (f *gitlabFake) serveProject(w http.ResponseWriter, q url.Values, project map[string]any, rest []string) {
+	issues := f.projectIssues(project)
+
+	switch {
+	case len(rest) == 0:
+		writeJSON(w, project)
+
+	case len(rest) == 1 && rest[0] == "issues":
+		f.listIssues(w, q, issues)
+
+	case len(rest) == 2 && rest[0] == "issues":
+		for _, issue := range issues {
+			if strconv.Itoa(intField(issue, "iid")) == rest[1] {
+				writeJSON(w, issue)
+				return
+			}
+		}
+		gitlabError(w, http.StatusNotFound, "404 Not found")
+
+	case len(rest) == 1 && rest[0] == "labels":
+		labels := f.labels
+		if len(issues) == 0 {
+			labels = nil
+		}
+		writeJSON(w, gitlabPage(w, q, labels))
+
+	default:
+		gitlabError(w, http.StatusNotFound, "404 Not Found")
+	}
+}
+
+// listProjects filters, sorts and pages the projects like GET /projects.
+func (f *gitlabFake) listProjects(w http.ResponseWriter, q url.Values) {
+	search := strings.ToLower(q.Get("search"))
+	matches := []map[string]any{}
+	for _, project := range f.projects {
+		archived, _ := project["archived"].(bool)
+		switch {
+		case search != "" &&
+			!strings.Contains(strings.ToLower(stringField(project, "name")), search) &&
+			!strings.Contains(strings.ToLower(stringField(project, "path_with_namespace")), search),
+			q.Has("archived") && strconv.FormatBool(archived) != q.Get("archived"),
+			q.Has("visibility") && stringField(project, "visibility") != q.Get("visibility"),
+			q.Has("last_activity_after") && stringField(project, "last_activity_at") <= q.Get("last_activity_after"):
+			continue
+		}
+		matches = append(matches, project)
+	}
+
+	orderBy := q.Get("order_by")
+	if orderBy == "" {
+		orderBy = "created_at"
+	}
+	sortByField(matches, orderBy, q.Get("sort") != "asc")
+	writeJSON(w, gitlabPage(w, q, matches))
+}
+
+// listIssues filters, sorts and pages a project's issues like
+// GET /projects/:id/issues.
+func (f *gitlabFake) listIssues(w http.ResponseWriter, q url.Values, issues []map[string]any) {
+	search := strings.ToLower(q.Get("search"))
+	state := q.Get("state")
+	labels := splitParam(q["labels"], ",")
+
+	matches := []map[string]any{}
+	for _, issue := range issues {
+		text := strings.ToLower(stringField(issue, "title") + " " + stringField(issue, "description"))
+		switch {
+		case search != "" && !strings.Contains(text, search),
+			state != "" && state != "all" && stringField(issue, "state") != state,
+			!containsAll(stringValues(issue["labels"]), labels):
+			continue
+		}
+		matches = append(matches, issue)
+	}
+
+	sortByField(matches, "created_at", q.Get("sort") != "asc")
+	writeJSON(w, gitlabPage(w, q, matches))
+}
+
+// project returns the project with the given numeric ID or path.
+func (f *gitlabFake) project(id string) map[string]any {
+	for _, project := range f.projects {
+		if strconv.Itoa(intField(project, "id")) == id || stringField(project, "path_with_namespace") == id {
+			return project
+		}
+	}
+	return nil
+}
+
+// projectIssues returns the issues of a project.
+func (f *gitlabFake) projectIssues(project map[string]any) []map[string]any {
+	var issues []map[string]any
+	for _, issue := range f.issues {
+		if intField(issue, "project_id") == intField(project, "id") {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// gitlabPage pages items by the page and per_page params and sets GitLab's
+// pagination headers.
+func gitlabPage(w http.ResponseWriter, q url.Values, items []map[string]any) []map[string]any {
+	pageNum, _ := strconv.Atoi(q.Get("page"))
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	perPage, _ := strconv.Atoi(q.Get("per_page"))
+	if perPage < 1 {
+		perPage = gitlabDefaultPerPage
+	}
+	if perPage > gitlabMaxPerPage {
+		perPage = gitlabMaxPerPage
+	}
+
+	w.Header().Set("X-Page", strconv.Itoa(pageNum))
+	w.Header().Set("X-Per-Page", strconv.Itoa(perPage))
+	w.Header().Set("X-Total", strconv.Itoa(len(items)))
+	if pageNum*perPage < len(items) {
+		w.Header().Set("X-Next-Page", strconv.Itoa(pageNum+1))
+	}
+	return page(items, (pageNum-1)*perPage, perPage)
+}
+
+// sortByField sorts items by a string field, such as an RFC 3339 timestamp.
+func sortByField(items []map[string]any, field string, desc bool) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return stringField(items[i], field) > stringField(items[j], field)
+		}
+		return stringField(items[i], field) < stringField(items[j], field)
+	})
+}
+
+// intField returns a numeric field of a JSON object as an int.
+func intField(object map[string]any, name string) int {
+	n, _ := object[name].(float64)
+	return int(n)
+}
+
+// gitlabError writes a GitLab error response.
+func gitlabError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"message": message})
+}
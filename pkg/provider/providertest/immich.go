@@ -0,0 +1,280 @@
+package providertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ImmichAPIKey is the API key the Immich fake accepts.
+const ImmichAPIKey = "providertest-immich-key"
+
+// immichAsset is a recorded asset with the fields the fake filters on.
+type immichAsset struct {
+	raw      map[string]any
+	id       string
+	text     string
+	taken    time.Time
+	people   []string
+	city     string
+	state    string
+	country  string
+	favorite bool
+	archived bool
+}
+
+// immichFake serves the recorded Immich library.
+type immichFake struct {
+	assets []immichAsset
+	albums []map[string]any
+	people map[string]any
+}
+
+// NewImmichServer starts a fake Immich server. It serves a small recorded
+// library of four assets, two albums and three people, and implements the
+// metadata and smart search filters, paging, and the 400 responses Immich
+// gives for unknown IDs. Requests must carry ImmichAPIKey.
+//
+// Album fixtures list their members in "assetIds", which GET /api/albums/{id}
+// expands to "assets" like the real server.
+func NewImmichServer(t *testing.T) *Server {
+	t.Helper()
+	fake := &immichFake{}
+
+	var assets []map[string]any
+	loadFixture("immich/assets.json", &assets)
+	for _, raw := range assets {
+		fake.assets = append(fake.assets, newImmichAsset(raw))
+	}
+	sort.SliceStable(fake.assets, func(i, j int) bool {
+		return fake.assets[i].taken.After(fake.assets[j].taken)
+	})
+	loadFixture("immich/albums.json", &fake.albums)
+	loadFixture("immich/people.json", &fake.people)
+
+	return newServer(t, fake)
+}
+
+// newImmichAsset extracts the filterable fields of a recorded asset.
+func newImmichAsset(raw map[string]any) immichAsset {
+	asset := immichAsset{raw: raw}
+	asset.id, _ = raw["id"].(string)
+	asset.favorite, _ = raw["isFavorite"].(bool)
+	asset.archived, _ = raw["isArchived"].(bool)
+	if taken, ok := raw["fileCreatedAt"].(string); ok {
+		asset.taken, _ = time.Parse(time.RFC3339, taken)
+	}
+
+	text := []string{stringField(raw, "originalFileName")}
+	if exif, ok := raw["exifInfo"].(map[string]any); ok {
+		asset.city = stringField(exif, "city")
+		asset.state = stringField(exif, "state")
+		asset.country = stringField(exif, "country")
+		text = append(text, stringField(exif, "description"), asset.city, asset.state, asset.country)
+	}
+	asset.text = strings.ToLower(strings.Join(text, " "))
+
+	if people, ok := raw["people"].([]any); ok {
+		for _, person := range people {
+			if person, ok := person.(map[string]any); ok {
+				asset.people = append(asset.people, stringField(person, "id"))
+			}
+		}
+	}
+	return asset
+}
+
+// stringField returns a string field of a JSON object, or "".
+func stringField(object map[string]any, name string) string {
+	s, _ := object[name].(string)
+	return s
+}
+
+// ServeHTTP implements the Immich endpoints the provider uses.
+func (f *immichFake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-API-Key") != ImmichAPIKey {
+		writeError(w, http.StatusUnauthorized, "Invalid API key")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/")
+	switch {
+	case r.Method == http.MethodGet && path == "server/ping":
+		writeJSON(w, map[string]any{"res": "pong"})
+
+	case r.Method == http.MethodPost && (path == "search/metadata" || path == "search/smart"):
+		f.search(w, r, path == "search/smart")
+
+	case r.Method == http.MethodGet && path == "search/cities":
+		seen := make(map[string]bool)
+		cities := []map[string]any{}
+		for _, asset := range f.assets {
+			if asset.city != "" && !seen[asset.city] {
+				seen[asset.city] = true
+				cities = append(cities, asset.raw)
+			}
+		}
+		writeJSON(w, cities)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "assets/"):
+		id := strings.TrimPrefix(path, "assets/")
+		for _, asset := range f.assets {
+			if asset.id == id {
+				writeJSON(w, asset.raw)
+				return
+			}
+		}
+		writeError(w, http.StatusBadRequest, "Not found or no asset.read access")
+
+	case r.Method == http.MethodGet && path == "albums":
+		writeJSON(w, f.albums)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "albums/"):
+		f.album(w, strings.TrimPrefix(path, "albums/"))
+
+	case r.Method == http.MethodGet && path == "people":
+		writeJSON(w, f.people)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "people/"):
+		id := strings.TrimPrefix(path, "people/")
+		people, _ := f.people["people"].([]any)
+		for _, person := range people {
+			if person, ok := person.(map[string]any); ok && person["id"] == id {
+				writeJSON(w, person)
+				return
+			}
+		}
+		writeError(w, http.StatusBadRequest, "Not found or no person.read access")
+
+	default:
+		writeError(w, http.StatusNotFound, "Cannot "+r.Method+" "+r.URL.Path)
+	}
+}
+
+// immichSearch is the body of a metadata or smart search.
+type immichSearch struct {
+	Page        int        `json:"page"`
+	Size        int        `json:"size"`
+	Query       string     `json:"query"`
+	Type        string     `json:"type"`
+	IsFavorite  *bool      `json:"isFavorite"`
+	IsArchived  *bool      `json:"isArchived"`
+	TakenAfter  *time.Time `json:"takenAfter"`
+	TakenBefore *time.Time `json:"takenBefore"`
+	City        string     `json:"city"`
+	State       string     `json:"state"`
+	Country     string     `json:"country"`
+	PersonIDs   []string   `json:"personIds"`
+	AlbumIDs    []string   `json:"albumIds"`
+}
+
+// search filters and pages the assets. Smart search matches the query
+// against file names, descriptions and places in place of CLIP embeddings.
+func (f *immichFake) search(w http.ResponseWriter, r *http.Request, smart bool) {
+	var req immichSearch
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Size < 1 || req.Size > 1000 {
+		writeError(w, http.StatusBadRequest, "size must not be less than 1 or greater than 1000")
+		return
+	}
+
+	var albumAssets map[string]bool
+	if len(req.AlbumIDs) > 0 {
+		albumAssets = make(map[string]bool)
+		for _, album := range f.albums {
+			if contains(req.AlbumIDs, stringField(album, "id")) {
+				ids, _ := album["assetIds"].([]any)
+				for _, id := range ids {
+					albumAssets[id.(string)] = true
+				}
+			}
+		}
+	}
+
+	matches := []map[string]any{}
+	for _, asset := range f.assets {
+		switch {
+		case smart && !strings.Contains(asset.text, strings.ToLower(req.Query)),
+			req.Type != "" && asset.raw["type"] != req.Type,
+			req.IsFavorite != nil && asset.favorite != *req.IsFavorite,
+			req.IsArchived != nil && asset.archived != *req.IsArchived,
+			req.TakenAfter != nil && asset.taken.Before(*req.TakenAfter),
+			req.TakenBefore != nil && asset.taken.After(*req.TakenBefore),
+			req.City != "" && asset.city != req.City,
+			req.State != "" && asset.state != req.State,
+			req.Country != "" && asset.country != req.Country,
+			albumAssets != nil && !albumAssets[asset.id],
+			!containsAll(asset.people, req.PersonIDs):
+			continue
+		}
+		matches = append(matches, asset.raw)
+	}
+
+	items := page(matches, (req.Page-1)*req.Size, req.Size)
+	var nextPage any
+	if req.Page*req.Size < len(matches) {
+		nextPage = strconv.Itoa(req.Page + 1)
+	}
+	writeJSON(w, map[string]any{
+		"albums": map[string]any{"total": 0, "count": 0, "items": []any{}, "facets": []any{}},
+		"assets": map[string]any{"total": len(items), "count": len(items), "items": items, "facets": []any{}, "nextPage": nextPage},
+	})
+}
+
+// album serves an album with its assets.
+func (f *immichFake) album(w http.ResponseWriter, id string) {
+	for _, album := range f.albums {
+		if stringField(album, "id") != id {
+			continue
+		}
+		ids, _ := album["assetIds"].([]any)
+		assets := []map[string]any{}
+		for _, asset := range f.assets {
+			for _, member := range ids {
+				if member == asset.id {
+					assets = append(assets, asset.raw)
+				}
+			}
+		}
+		response := make(map[string]any, len(album)+1)
+		for key, value := range album {
+			if key != "assetIds" {
+				response[key] = value
+			}
+		}
+		response["assets"] = assets
+		writeJSON(w, response)
+		return
+	}
+	writeError(w, http.StatusBadRequest, "Not found or no album.read access")
+}
+
+// contains reports whether list contains s.
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAll reports whether list contains every element of want.
+func containsAll(list, want []string) bool {
+	for _, s := range want {
+		if !contains(list, s) {
+			return false
+		}
+	}
+	return true
+}
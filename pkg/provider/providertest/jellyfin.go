@@ -0,0 +1,199 @@
+package providertest
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// JellyfinAPIKey is the API key the Jellyfin fake accepts.
+const JellyfinAPIKey = "providertest-jellyfin-key"
+
+// jellyfinFake serves the recorded Jellyfin library.
+type jellyfinFake struct {
+	info  map[string]any
+	items []map[string]any
+}
+
+// NewJellyfinServer starts a fake Jellyfin server. It serves a small
+// recorded library of three movies, a series and one of its episodes, and
+// implements the /Items search, filter, sort and paging params. Requests
+// must carry JellyfinAPIKey.
+func NewJellyfinServer(t *testing.T) *Server {
+	t.Helper()
+	fake := &jellyfinFake{}
+
+	var items struct {
+		Items []map[string]any `json:"Items"`
+	}
+	loadFixture("jellyfin/items.json", &items)
+	fake.items = items.Items
+	loadFixture("jellyfin/system_info.json", &fake.info)
+
+	return newServer(t, fake)
+}
+
+// ServeHTTP implements the Jellyfin endpoints the provider uses.
+func (f *jellyfinFake) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Emby-Token") != JellyfinAPIKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch path := r.URL.Path; {
+	case path == "/System/Info":
+		writeJSON(w, f.info)
+
+	case path == "/Items":
+		f.search(w, r)
+
+	case strings.HasPrefix(path, "/Items/"):
+		id := strings.TrimPrefix(path, "/Items/")
+		for _, item := range f.items {
+			if item["Id"] == id {
+				writeJSON(w, item)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+
+	case path == "/Genres":
+		writeJSON(w, map[string]any{"Items": f.names("Genres"), "TotalRecordCount": len(f.names("Genres"))})
+
+	case path == "/Studios":
+		writeJSON(w, map[string]any{"Items": f.names("Studios"), "TotalRecordCount": len(f.names("Studios"))})
+
+	case strings.HasPrefix(path, "/Shows/"):
+		writeJSON(w, map[string]any{"Items": []any{}, "TotalRecordCount": 0})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// search filters, sorts and pages the items like GET /Items.
+func (f *jellyfinFake) search(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	term := strings.ToLower(q.Get("searchTerm"))
+	itemTypes := splitParam(q["includeItemTypes"], ",")
+	genres := splitParam(q["genres"], "|")
+	studios := splitParam(q["studios"], "|")
+	years := splitParam(q["years"], ",")
+	ratings := splitParam(q["officialRatings"], "|")
+	minRating, _ := strconv.ParseFloat(q.Get("minCommunityRating"), 64)
+
+	matches := []map[string]any{}
+	for _, item := range f.items {
+		rating, _ := item["CommunityRating"].(float64)
+		year, _ := item["ProductionYear"].(float64)
+		switch {
+		case term != "" && !strings.Contains(strings.ToLower(stringField(item, "Name")), term),
+			len(itemTypes) > 0 && !contains(itemTypes, stringField(item, "Type")),
+			len(genres) > 0 && !containsAny(stringValues(item["Genres"]), genres),
+			len(studios) > 0 && !containsAny(f.studioNames(item), studios),
+			len(years) > 0 && !contains(years, strconv.Itoa(int(year))),
+			len(ratings) > 0 && !contains(ratings, stringField(item, "OfficialRating")),
+			minRating > 0 && rating < minRating:
+			continue
+		}
+		matches = append(matches, item)
+	}
+
+	sortBy := q.Get("sortBy")
+	if sortBy == "" {
+		sortBy = "SortName"
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if q.Get("sortOrder") == "Descending" {
+			i, j = j, i
+		}
+		return stringField(matches[i], sortBy) < stringField(matches[j], sortBy)
+	})
+
+	start, _ := strconv.Atoi(q.Get("startIndex"))
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	writeJSON(w, map[string]any{
+		"Items":            page(matches, start, limit),
+		"TotalRecordCount": len(matches),
+		"StartIndex":       start,
+	})
+}
+
+// names lists the distinct values of a list field of the items as named
+// items, like GET /Genres and /Studios.
+func (f *jellyfinFake) names(field string) []map[string]any {
+	seen := make(map[string]bool)
+	var names []string
+	for _, item := range f.items {
+		values := stringValues(item[field])
+		if field == "Studios" {
+			values = f.studioNames(item)
+		}
+		for _, name := range values {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	result := make([]map[string]any, 0, len(names))
+	for _, name := range names {
+		result = append(result, map[string]any{"Name": name, "Id": name})
+	}
+	return result
+}
+
+// studioNames returns the names of an item's studios.
+func (f *jellyfinFake) studioNames(item map[string]any) []string {
+	studios, _ := item["Studios"].([]any)
+	names := make([]string, 0, len(studios))
+	for _, studio := range studios {
+		if studio, ok := studio.(map[string]any); ok {
+			names = append(names, stringField(studio, "Name"))
+		}
+	}
+	return names
+}
+
+// splitParam splits repeated and delimited query param values.
+func splitParam(values []string, sep string) []string {
+	var result []string
+	for _, value := range values {
+		for _, part := range strings.Split(value, sep) {
+			if part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+	return result
+}
+
+// stringValues converts a decoded JSON list of strings.
+func stringValues(value any) []string {
+	list, _ := value.([]any)
+	result := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// containsAny reports whether list contains any element of want.
+func containsAny(list, want []string) bool {
+	for _, s := range want {
+		if contains(list, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,435 @@
+// Package providertest checks providers against the Provider contract.
+//
+// Run executes a standard suite against an initialized provider, usually one
+// pointed at a fake backend:
+//
+//	func TestConformance(t *testing.T) {
+//		server := providertest.NewJellyfinServer(t)
+//		p := jellyfin.NewProvider()
+//		if err := p.Initialize(ctx, map[string]any{
+//			"instance_id": "test",
+//			"url":         server.URL,
+//			"api_key":     providertest.JellyfinAPIKey,
+//		}); err != nil {
+//			t.Fatal(err)
+//		}
+//		providertest.Run(t, providertest.Harness{Provider: p, InstanceID: "test", Server: server})
+//	}
+//
+// Recorded fake servers ship for Immich, Jellyfin and GitLab. They serve
+// responses recorded from real instances (see testdata) and implement the
+// paging and filter params the providers use.
+package providertest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/search/filters"
+	"github.com/yourname/mifind/internal/types"
+)
+
+// cancelTimeout bounds how long a call may take to return once its context
+// is cancelled.
+const cancelTimeout = 5 * time.Second
+
+// Harness describes the provider under test.
+type Harness struct {
+	// Provider is the initialized provider.
+	Provider provider.Provider
+
+	// InstanceID is the instance_id the provider was initialized with.
+	InstanceID string
+
+	// Query is a search that returns at least two results. The zero query
+	// (match all) is used if it is left empty.
+	Query provider.SearchQuery
+
+	// FilterCases gives probe values for filters that can't be checked
+	// against entity attributes, e.g. provider-level filters on IDs.
+	FilterCases map[string]FilterCase
+
+	// Server is the provider's fake backend, if any. It is used to check that
+	// in-flight calls return when their context is cancelled.
+	Server *Server
+}
+
+// FilterCase is a filter value and the entities a search with it may return.
+type FilterCase struct {
+	// Value is the filter value, as the search API passes it to providers.
+	Value any
+
+	// Expect lists the IDs of the entities that match Value. A filtered
+	// search must return at least one of them and nothing else.
+	Expect []string
+}
+
+// Run runs the conformance suite against the provider:
+//
+//   - every entity ID is valid and names the provider and instance
+//   - every search result hydrates to the same entity
+//   - unknown and malformed IDs hydrate to ErrNotFound
+//   - every advertised filter capability is honoured
+//   - limit and offset are respected
+//   - calls return the context's error once it is cancelled
+//
+// Equality and range filters are probed with values taken from the results
+// of Query; the "type" filter is compared with the entity type. Filters
+// whose values don't appear in entity attributes need a FilterCase.
+func Run(t *testing.T, h Harness) {
+	t.Helper()
+	ctx := context.Background()
+
+	baseline, err := h.Provider.Search(ctx, h.Query)
+	if err != nil {
+		t.Fatalf("Search(%+v) failed: %v", h.Query, err)
+	}
+	if len(baseline) < 2 {
+		t.Fatalf("Search(%+v) returned %d results, the harness query must return at least 2", h.Query, len(baseline))
+	}
+
+	t.Run("EntityIDs", func(t *testing.T) { checkEntityIDs(t, h, baseline) })
+	t.Run("Hydrate", func(t *testing.T) { checkHydrate(t, h, baseline) })
+	t.Run("NotFound", func(t *testing.T) { checkNotFound(t, h) })
+	t.Run("Filters", func(t *testing.T) { checkFilters(t, h, baseline) })
+	t.Run("LimitOffset", func(t *testing.T) { checkLimitOffset(t, h) })
+	t.Run("Cancellation", func(t *testing.T) { checkCancellation(t, h, baseline[0].ID) })
+}
+
+// checkEntityIDs checks the IDs of search and discovery results.
+func checkEntityIDs(t *testing.T, h Harness, baseline []types.Entity) {
+	discovered, err := h.Provider.Discover(context.Background())
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	for name, entities := range map[string][]types.Entity{"Search": baseline, "Discover": discovered} {
+		seen := make(map[string]bool, len(entities))
+		for _, entity := range entities {
+			id := provider.EntityID(entity.ID)
+			if !id.IsValid() {
+				t.Errorf("%s: invalid entity ID %q", name, entity.ID)
+				continue
+			}
+			if id.ProviderType() != h.Provider.Name() || id.InstanceID() != h.InstanceID {
+				t.Errorf("%s: entity ID %q does not start with %q", name, entity.ID, provider.InstanceKey(h.Provider.Name(), h.InstanceID))
+			}
+			if entity.Provider != h.Provider.Name() {
+				t.Errorf("%s: entity %q has Provider %q, want %q", name, entity.ID, entity.Provider, h.Provider.Name())
+			}
+			if seen[entity.ID] {
+				t.Errorf("%s: entity %q returned twice", name, entity.ID)
+			}
+			seen[entity.ID] = true
+		}
+	}
+}
+
+// checkHydrate checks that search results hydrate to the same entity.
+func checkHydrate(t *testing.T, h Harness, baseline []types.Entity) {
+	ctx := context.Background()
+	for _, want := range baseline {
+		got, err := h.Provider.Hydrate(ctx, want.ID)
+		if err != nil {
+			t.Errorf("Hydrate(%q) failed: %v", want.ID, err)
+			continue
+		}
+		if got.ID != want.ID || got.Type != want.Type || got.Title != want.Title {
+			t.Errorf("Hydrate(%q) = {%q, %q, %q}, search returned {%q, %q, %q}",
+				want.ID, got.ID, got.Type, got.Title, want.ID, want.Type, want.Title)
+		}
+	}
+}
+
+// checkNotFound checks that unknown and malformed IDs hydrate to ErrNotFound.
+func checkNotFound(t *testing.T, h Harness) {
+	ctx := context.Background()
+	ids := []string{
+		provider.NewEntityID(h.Provider.Name(), h.InstanceID, "providertest-missing").String(),
+		"providertest-malformed",
+	}
+	for _, id := range ids {
+		if _, err := h.Provider.Hydrate(ctx, id); !errors.Is(err, provider.ErrNotFound) {
+			t.Errorf("Hydrate(%q) = %v, want ErrNotFound", id, err)
+		}
+	}
+}
+
+// checkFilters checks that every advertised filter capability is honoured.
+func checkFilters(t *testing.T, h Harness, baseline []types.Entity) {
+	ctx := context.Background()
+	caps, err := h.Provider.FilterCapabilities(ctx)
+	if err != nil {
+		t.Fatalf("FilterCapabilities failed: %v", err)
+	}
+
+	names := make([]string, 0, len(caps))
+	for name := range caps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		capability := caps[name]
+		t.Run(name, func(t *testing.T) {
+			if fc, ok := h.FilterCases[name]; ok {
+				results := searchWithFilter(t, h, name, fc.Value)
+				expected := make(map[string]bool, len(fc.Expect))
+				for _, id := range fc.Expect {
+					expected[id] = true
+				}
+				for _, entity := range results {
+					if !expected[entity.ID] {
+						t.Errorf("filter %s=%v returned %q, which is not expected to match", name, fc.Value, entity.ID)
+					}
+				}
+				return
+			}
+
+			probe, ok := probeValue(name, baseline)
+			if !ok {
+				t.Fatalf("capability %q is advertised but no result carries it; add a FilterCase", name)
+			}
+
+			checked := false
+			if capability.SupportsEq {
+				checked = true
+				value := eqValue(capability.Type, probe)
+				for _, entity := range searchWithFilter(t, h, name, value) {
+					if actual, _ := attributeOf(entity, name); !matchesEq(actual, probe) {
+						t.Errorf("filter %s=%v returned %q with %s=%v", name, value, entity.ID, name, actual)
+					}
+				}
+			}
+			if capability.SupportsRange {
+				bound, ok := toFloat(probe)
+				if !ok {
+					t.Fatalf("capability %q supports ranges but %v is not numeric", name, probe)
+				}
+				checked = true
+				value := rangeValue(capability.Type, bound)
+				for _, entity := range searchWithFilter(t, h, name, value) {
+					actual, _ := attributeOf(entity, name)
+					if f, ok := toFloat(actual); !ok || f != bound {
+						t.Errorf("filter %s=%v returned %q with %s=%v", name, value, entity.ID, name, actual)
+					}
+				}
+			}
+			if capability.SupportsGeo {
+				center, ok := probe.(types.GPS)
+				if !ok {
+					t.Fatalf("capability %q supports geo filters but %v is not a types.GPS", name, probe)
+				}
+				checked = true
+				radius := filters.NewGeoRadiusFilter(center.Latitude, center.Longitude, 1)
+				value := map[string]any{string(radius.Operation()): radius.Value()}
+				for _, entity := range searchWithFilter(t, h, name, value) {
+					actual, _ := attributeOf(entity, name)
+					if point, ok := actual.(types.GPS); !ok || !radius.Contains(point) {
+						t.Errorf("filter %s=%v returned %q with %s=%v", name, value, entity.ID, name, actual)
+					}
+				}
+			}
+			if !checked {
+				t.Logf("capability %q only supports operations that can't be expressed in SearchQuery.Filters", name)
+			}
+		})
+	}
+}
+
+// searchWithFilter runs the harness query with a single filter and fails
+// the test if it errors or returns nothing.
+func searchWithFilter(t *testing.T, h Harness, name string, value any) []types.Entity {
+	t.Helper()
+	query := h.Query
+	query.Filters = map[string]any{name: value}
+
+	results, err := h.Provider.Search(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Search with filter %s=%v failed: %v", name, value, err)
+	}
+	if len(results) == 0 {
+		t.Errorf("Search with filter %s=%v returned nothing, though the unfiltered results match it", name, value)
+	}
+	return results
+}
+
+// checkLimitOffset checks that consecutive pages line up.
+func checkLimitOffset(t *testing.T, h Harness) {
+	ctx := context.Background()
+
+	query := h.Query
+	query.Limit, query.Offset = 2, 0
+	first, err := h.Provider.Search(ctx, query)
+	if err != nil {
+		t.Fatalf("Search(limit=2) failed: %v", err)
+	}
+	if len(first) != 2 {
+		t.Fatalf("Search(limit=2) returned %d results, want 2", len(first))
+	}
+
+	query.Limit, query.Offset = 1, 1
+	second, err := h.Provider.Search(ctx, query)
+	if err != nil {
+		t.Fatalf("Search(limit=1, offset=1) failed: %v", err)
+	}
+	if len(second) != 1 {
+		t.Fatalf("Search(limit=1, offset=1) returned %d results, want 1", len(second))
+	}
+	if second[0].ID != first[1].ID {
+		t.Errorf("Search(limit=1, offset=1) returned %q, want the second result of Search(limit=2), %q", second[0].ID, first[1].ID)
+	}
+}
+
+// checkCancellation checks that calls with a cancelled context return its
+// error, and that in-flight calls return promptly when it is cancelled.
+func checkCancellation(t *testing.T, h Harness, id string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if results, err := h.Provider.Search(ctx, h.Query); !errors.Is(err, context.Canceled) {
+		t.Errorf("Search with a cancelled context = %d results, %v; want context.Canceled", len(results), err)
+	}
+	if _, err := h.Provider.Hydrate(ctx, id); !errors.Is(err, context.Canceled) {
+		t.Errorf("Hydrate with a cancelled context = %v, want context.Canceled", err)
+	}
+
+	if h.Server == nil {
+		return
+	}
+	resume := h.Server.Stall()
+	defer resume()
+
+	ctx, cancel = context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := h.Provider.Search(ctx, h.Query)
+		done <- err
+	}()
+	stalled := make(chan struct{})
+	go func() {
+		h.Server.WaitStalled()
+		close(stalled)
+	}()
+	select {
+	case <-stalled:
+	case err := <-done:
+		cancel()
+		t.Fatalf("Search returned %v without calling the server", err)
+	case <-time.After(cancelTimeout):
+		cancel()
+		t.Fatalf("Search did not call the server within %s", cancelTimeout)
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Search cancelled in flight = %v, want context.Canceled", err)
+		}
+	case <-time.After(cancelTimeout):
+		t.Errorf("Search did not return within %s of its context being cancelled", cancelTimeout)
+	}
+}
+
+// attributeOf returns an entity's value for a filter name. The "type" filter
+// refers to the entity type.
+func attributeOf(entity types.Entity, name string) (any, bool) {
+	if name == types.AttrType {
+		return entity.Type, true
+	}
+	value, ok := entity.Attributes[name]
+	return value, ok
+}
+
+// probeValue returns a filter value taken from the first result that has
+// the attribute. For list attributes, it is the first element.
+func probeValue(name string, entities []types.Entity) (any, bool) {
+	for _, entity := range entities {
+		value, ok := attributeOf(entity, name)
+		if !ok || value == nil {
+			continue
+		}
+		if list, ok := value.([]string); ok {
+			if len(list) == 0 {
+				continue
+			}
+			return list[0], true
+		}
+		return value, true
+	}
+	return nil, false
+}
+
+// eqValue converts a probe to the value the search API passes for an
+// equality filter of the given type.
+func eqValue(attrType types.AttributeType, probe any) any {
+	switch attrType {
+	case types.AttributeTypeStringSlice:
+		return []string{fmt.Sprint(probe)}
+	case types.AttributeTypeInt, types.AttributeTypeInt64, types.AttributeTypeTime:
+		if f, ok := toFloat(probe); ok {
+			return int64(f)
+		}
+	case types.AttributeTypeFloat, types.AttributeTypeFloat64:
+		if f, ok := toFloat(probe); ok {
+			return f
+		}
+	}
+	return probe
+}
+
+// rangeValue returns a range filter value matching exactly bound.
+func rangeValue(attrType types.AttributeType, bound float64) map[string]any {
+	if attrType == types.AttributeTypeTime {
+		return map[string]any{"min": int64(bound), "max": int64(bound)}
+	}
+	return map[string]any{"min": bound, "max": bound}
+}
+
+// matchesEq reports whether an attribute value matches an equality probe.
+// List attributes match if they contain the probe.
+func matchesEq(actual, probe any) bool {
+	if list, ok := actual.([]string); ok {
+		for _, item := range list {
+			if item == fmt.Sprint(probe) {
+				return true
+			}
+		}
+		return false
+	}
+	if a, ok := toFloat(actual); ok {
+		b, ok := toFloat(probe)
+		return ok && a == b
+	}
+	return fmt.Sprint(actual) == fmt.Sprint(probe)
+}
+
+// toFloat converts numeric values to float64.
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, !math.IsNaN(v)
+	case time.Time:
+		return float64(v.Unix()), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
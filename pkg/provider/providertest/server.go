@@ -0,0 +1,122 @@
+package providertest
+
+import (
+	"embed"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+//go:embed testdata
+var testdata embed.FS
+
+// Server is a fake backend. While stalled, it holds requests until the
+// client gives up on them, so tests can cancel calls in flight.
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	stalled bool
+	waiting chan struct{}
+}
+
+// newServer starts a server for handler and closes it when the test ends.
+func newServer(t *testing.T, handler http.Handler) *Server {
+	t.Helper()
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.hold(r) {
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Stall makes the server hold new requests until their client cancels them.
+// The returned function resumes normal service.
+func (s *Server) Stall() (resume func()) {
+	s.mu.Lock()
+	s.stalled = true
+	s.waiting = make(chan struct{})
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		s.stalled = false
+		s.mu.Unlock()
+	}
+}
+
+// WaitStalled blocks until a request is being held by a stalled server.
+func (s *Server) WaitStalled() {
+	s.mu.Lock()
+	waiting := s.waiting
+	s.mu.Unlock()
+	if waiting != nil {
+		<-waiting
+	}
+}
+
+// hold holds a request while the server is stalled and reports whether it did.
+func (s *Server) hold(r *http.Request) bool {
+	s.mu.Lock()
+	stalled, waiting := s.stalled, s.waiting
+	if stalled && waiting != nil {
+		s.waiting = nil
+		close(waiting)
+	}
+	s.mu.Unlock()
+
+	if !stalled {
+		return false
+	}
+	// The server only notices the client going away once the request body
+	// has been read.
+	_, _ = io.Copy(io.Discard, r.Body)
+	<-r.Context().Done()
+	return true
+}
+
+// loadFixture decodes a recorded response from testdata into out.
+func loadFixture(name string, out any) {
+	data, err := testdata.ReadFile("testdata/" + name)
+	if err != nil {
+		panic("providertest: " + err.Error())
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		panic("providertest: decoding " + name + ": " + err.Error())
+	}
+}
+
+// writeJSON writes value as a JSON response.
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes an error response in the given status.
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"message": message, "statusCode": status})
+}
+
+// page returns the page of items starting at offset, at most limit long.
+func page[T any](items []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,76 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yourname/mifind/internal/provider"
+	"github.com/yourname/mifind/internal/provider/mock"
+	"github.com/yourname/mifind/pkg/provider/gitlab"
+	"github.com/yourname/mifind/pkg/provider/immich"
+	"github.com/yourname/mifind/pkg/provider/jellyfin"
+	"github.com/yourname/mifind/pkg/provider/providertest"
+)
+
+// initialize initializes p with config and fails the test on error.
+func initialize(t *testing.T, p provider.Provider, config map[string]any) {
+	t.Helper()
+	config["instance_id"] = "test"
+	if err := p.Initialize(context.Background(), config); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+}
+
+// TestConformance runs the conformance suite against the mock provider and
+// the built-in providers backed by their recorded fake servers.
+func TestConformance(t *testing.T) {
+	t.Run("mock", func(t *testing.T) {
+		p := mock.NewMockProvider()
+		initialize(t, p, map[string]any{})
+		providertest.Run(t, providertest.Harness{Provider: p, InstanceID: "test"})
+	})
+
+	t.Run("immich", func(t *testing.T) {
+		server := providertest.NewImmichServer(t)
+		p := immich.NewProvider()
+		initialize(t, p, map[string]any{"url": server.URL, "api_key": providertest.ImmichAPIKey})
+
+		id := func(resourceID string) string {
+			return provider.NewEntityID("immich", "test", resourceID).String()
+		}
+		providertest.Run(t, providertest.Harness{
+			Provider:   p,
+			InstanceID: "test",
+			Server:     server,
+			FilterCases: map[string]providertest.FilterCase{
+				"album": {
+					Value:  "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+					Expect: []string{id("3f5b8a2e-6c41-4d8e-9a7b-1e2f3c4d5a61"), id("8a0e6b77-2d19-4c35-b1f4-6a2c9e8d7f13")},
+				},
+				"person": {
+					Value:  []string{"e1f2a3b4-c5d6-4e7f-8091-a2b3c4d5e6f7"},
+					Expect: []string{id("c2d4e6f8-0a1b-4c3d-9e5f-7a8b9c0d1e2f"), id("5e7f9a1b-3c5d-4e7f-a1b3-c5d7e9f1a3b5")},
+				},
+			},
+		})
+	})
+
+	t.Run("jellyfin", func(t *testing.T) {
+		server := providertest.NewJellyfinServer(t)
+		p := jellyfin.NewProvider()
+		initialize(t, p, map[string]any{"url": server.URL, "api_key": providertest.JellyfinAPIKey})
+		providertest.Run(t, providertest.Harness{Provider: p, InstanceID: "test", Server: server})
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		server := providertest.NewGitLabServer(t)
+		p := gitlab.NewProvider()
+		initialize(t, p, map[string]any{
+			"url":           server.URL,
+			"access_token":  providertest.GitLabToken,
+			"search_issues": true,
+			"projects":      []string{providertest.GitLabProject},
+		})
+		providertest.Run(t, providertest.Harness{Provider: p, InstanceID: "test", Server: server})
+	})
+}